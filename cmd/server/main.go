@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -56,20 +57,44 @@ func main() {
 	consignmentRepo := repository.NewConsignmentRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
 	settlementRepo := repository.NewSettlementRepository(db)
+	creditRepo := repository.NewCreditRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+
+	uow := service.NewUnitOfWork(db)
 
 	userService := service.NewUserService(userRepo)
-	storeService := service.NewStoreService(storeRepo)
+	sessionService, err := service.NewSessionService(sessionRepo, userRepo, jwtService, cfg.RefreshTokenExpiresIn)
+	if err != nil {
+		log.Fatalf("cannot create session service: %v", err)
+	}
+	storeService := service.NewStoreService(storeRepo, uow)
+	staffService := service.NewStaffService(storeRepo, userRepo)
 	cardService := service.NewCardService(cardRepo, storeRepo)
-	consignmentService := service.NewConsignmentService(consignmentRepo, cardRepo, storeRepo)
-	transactionService := service.NewTransactionService(transactionRepo, consignmentRepo, storeRepo, db)
-	settlementService := service.NewSettlementService(settlementRepo, consignmentRepo, storeRepo, db)
+	consignmentService := service.NewConsignmentService(consignmentRepo, cardRepo, storeRepo, uow)
+	transactionService := service.NewTransactionService(transactionRepo, consignmentRepo, cardRepo, settlementRepo, creditRepo, storeRepo, uow)
+	settlementService := service.NewSettlementService(settlementRepo, consignmentRepo, creditRepo, storeRepo, uow)
+	creditService := service.NewCreditService(creditRepo, storeRepo, userRepo)
+	reportService := service.NewReportService(reportRepo, storeRepo)
+
+	// Background jobs: automatic price drops and consignment expiry
+	expiryScheduler, err := service.NewExpiryScheduler(consignmentService, cfg.ExpiryCheckInterval)
+	if err != nil {
+		log.Fatalf("cannot create expiry scheduler: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	expiryScheduler.Start(ctx)
 
-	userHandler := api.NewUserHandler(userService, jwtService)
+	userHandler := api.NewUserHandler(userService, sessionService)
 	storeHandler := api.NewStoreHandler(storeService)
+	staffHandler := api.NewStaffHandler(staffService)
 	cardHandler := api.NewCardHandler(cardService)
 	consignmentHandler := api.NewConsignmentHandler(consignmentService)
 	transactionHandler := api.NewTransactionHandler(transactionService)
 	settlementHandler := api.NewSettlementHandler(settlementService)
+	creditHandler := api.NewCreditHandler(creditService)
+	reportHandler := api.NewReportHandler(reportService)
 
 	// Setup server and routes
 	r := gin.Default()
@@ -95,10 +120,12 @@ func main() {
 	})
 	r.POST("/register", userHandler.Register)
 	r.POST("/login", userHandler.Login)
+	r.POST("/refresh", userHandler.Refresh)
+	r.POST("/logout", userHandler.Logout)
 
 	// Authenticated routes
 	apiRoutes := r.Group("/api")
-	apiRoutes.Use(api.AuthMiddleware(jwtService))
+	apiRoutes.Use(api.AuthMiddleware(jwtService, sessionService))
 	{
 		apiRoutes.GET("/profile", func(c *gin.Context) {
 			payload, exists := c.Get(api.AuthorizationPayloadKey)
@@ -114,13 +141,49 @@ func main() {
 		userRoutes.Use(api.RoleMiddleware("ADMIN"))
 		{
 			userRoutes.GET("/:id", userHandler.GetUserByID)
+			userRoutes.DELETE("/:id/sessions", userHandler.RevokeSessions)
+		}
+
+		// Admin review of store registrations
+		adminStoreRoutes := apiRoutes.Group("/admin/stores")
+		adminStoreRoutes.Use(api.RoleMiddleware("ADMIN"))
+		{
+			adminStoreRoutes.GET("", storeHandler.ListStores)
+			adminStoreRoutes.POST("/:id/approve", storeHandler.ApproveStore)
+			adminStoreRoutes.POST("/:id/reject", storeHandler.RejectStore)
+			adminStoreRoutes.POST("/:id/suspend", storeHandler.SuspendStore)
 		}
 
 		// Store routes
 		storeRoutes := apiRoutes.Group("/stores")
-		storeRoutes.Use(api.RoleMiddleware("STORE"))
 		{
-			storeRoutes.POST("", storeHandler.CreateStore)
+			// Store owner actions on their own store
+			storeRoutes.POST("", api.RoleMiddleware("STORE"), storeHandler.CreateStore)
+			storeRoutes.GET("/me", api.RoleMiddleware("STORE"), storeHandler.GetMyStore)
+			storeRoutes.PUT("/me", api.RoleMiddleware("STORE"), storeHandler.UpdateMyStore)
+			storeRoutes.GET("/me/commission-schedules", api.RoleMiddleware("STORE"), storeHandler.ListCommissionSchedules)
+			storeRoutes.POST("/me/commission-schedules", api.RoleMiddleware("STORE"), storeHandler.CreateCommissionSchedule)
+			storeRoutes.DELETE("/me/commission-schedules/:scheduleId", api.RoleMiddleware("STORE"), storeHandler.DeleteCommissionSchedule)
+
+			// Store staff; what each member may do depends on their role at the store
+			storeRoutes.GET("/me/staff", api.RoleMiddleware("STORE"), staffHandler.ListStaff)
+			storeRoutes.POST("/me/staff", api.RoleMiddleware("STORE"), staffHandler.InviteStaff)
+			storeRoutes.PUT("/me/staff/:memberId", api.RoleMiddleware("STORE"), staffHandler.UpdateStaffRole)
+			storeRoutes.DELETE("/me/staff/:memberId", api.RoleMiddleware("STORE"), staffHandler.RemoveStaff)
+			storeRoutes.DELETE("/me/membership", api.RoleMiddleware("STORE"), staffHandler.LeaveStore)
+
+			// Store directory, so players can find where to consign
+			storeRoutes.GET("", api.RoleMiddleware("PLAYER", "STORE"), storeHandler.SearchStores)
+			storeRoutes.GET("/:id", api.RoleMiddleware("PLAYER", "STORE"), storeHandler.GetStore)
+		}
+
+		// Invitations to join a store's staff
+		invitationRoutes := apiRoutes.Group("/store-invitations")
+		invitationRoutes.Use(api.RoleMiddleware("STORE"))
+		{
+			invitationRoutes.GET("", staffHandler.ListMyInvitations)
+			invitationRoutes.POST("/:id/accept", staffHandler.AcceptInvitation)
+			invitationRoutes.POST("/:id/decline", staffHandler.DeclineInvitation)
 		}
 
 		// Card routes
@@ -144,8 +207,33 @@ func main() {
 			
 			// Store updates the status of an item in a consignment
 			consignmentRoutes.PUT("/items/:itemId", api.RoleMiddleware("STORE"), consignmentHandler.UpdateConsignmentItemStatus)
+			// Store reviews many items at once, all or nothing
+			consignmentRoutes.POST("/items/review", api.RoleMiddleware("STORE"), consignmentHandler.ReviewItems)
+
+			// Players list their own consignments, stores list those addressed to them
+			consignmentRoutes.GET("", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.ListConsignments)
+			// Stores see which items on sale are about to reach the end of their agreement period
+			consignmentRoutes.GET("/expiring", api.RoleMiddleware("STORE"), consignmentHandler.ListExpiringItems)
+			consignmentRoutes.GET("/:id", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.GetConsignment)
 
-			// Routes for listing consignments can be added here later if needed
+			// Status history of a single item, for dispute handling
+			consignmentRoutes.GET("/items/:itemId/history", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.GetConsignmentItemHistory)
+
+			// Stores attach intake photos of the physical copy; both sides can view them
+			consignmentRoutes.POST("/items/:itemId/photos", api.RoleMiddleware("STORE"), consignmentHandler.AddItemPhoto)
+			consignmentRoutes.GET("/items/:itemId/photos", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.ListItemPhotos)
+
+			// Players cancel pending items or ask for approved items back; the store confirms the return
+			consignmentRoutes.POST("/items/:itemId/cancel", api.RoleMiddleware("PLAYER"), consignmentHandler.CancelConsignmentItem)
+			consignmentRoutes.POST("/items/:itemId/withdraw", api.RoleMiddleware("PLAYER"), consignmentHandler.WithdrawConsignmentItem)
+			consignmentRoutes.POST("/items/:itemId/return", api.RoleMiddleware("STORE"), consignmentHandler.ConfirmItemReturn)
+			// Players put expired items back on sale (or collect them, confirmed through /return)
+			consignmentRoutes.POST("/items/:itemId/relist", api.RoleMiddleware("PLAYER"), consignmentHandler.RelistItem)
+
+			// Price negotiation between the store and the player
+			consignmentRoutes.GET("/items/:itemId/price-proposals", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.ListPriceProposals)
+			consignmentRoutes.POST("/items/:itemId/price-proposals", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.ProposePrice)
+			consignmentRoutes.POST("/items/:itemId/price-proposals/accept", api.RoleMiddleware("PLAYER", "STORE"), consignmentHandler.AcceptPriceProposal)
 		}
 
 		// Transaction routes
@@ -153,6 +241,16 @@ func main() {
 		transactionRoutes.Use(api.RoleMiddleware("STORE"))
 		{
 			transactionRoutes.POST("", transactionHandler.CreateTransaction)
+			transactionRoutes.POST("/:id/void", transactionHandler.VoidTransaction)
+			transactionRoutes.POST("/:id/refund", transactionHandler.RefundTransaction)
+		}
+
+		// Sale routes: several items checked out under one receipt
+		saleRoutes := apiRoutes.Group("/sales")
+		saleRoutes.Use(api.RoleMiddleware("STORE"))
+		{
+			saleRoutes.POST("", transactionHandler.CreateSale)
+			saleRoutes.GET("/:id", transactionHandler.GetSale)
 		}
 
 
@@ -161,9 +259,39 @@ func main() {
 		{
 			// Player action: Create a settlement request
 			settlementRoutes.POST("", api.RoleMiddleware("PLAYER"), settlementHandler.CreateSettlement)
-			// Shared/Store actions:
-			// settlementRoutes.GET("", settlementHandler.ListSettlements)
-			// settlementRoutes.PUT("/:id", api.RoleMiddleware("STORE"), settlementHandler.CompleteSettlement)
+			// Shared action: players list their own settlements, stores list requests addressed to them
+			settlementRoutes.GET("", api.RoleMiddleware("PLAYER", "STORE"), settlementHandler.ListSettlements)
+			settlementRoutes.GET("/:id", api.RoleMiddleware("PLAYER", "STORE"), settlementHandler.GetSettlement)
+			// Store action: mark a settlement as paid out
+			settlementRoutes.PUT("/:id", api.RoleMiddleware("STORE"), settlementHandler.CompleteSettlement)
+		}
+
+		// Store reports, as JSON or CSV
+		reportRoutes := apiRoutes.Group("/reports")
+		reportRoutes.Use(api.RoleMiddleware("STORE"))
+		{
+			reportRoutes.GET("/sales", reportHandler.SalesByPeriod)
+			reportRoutes.GET("/commission", reportHandler.CommissionByPaymentMethod)
+			reportRoutes.GET("/liabilities", reportHandler.PlayerLiabilities)
+			reportRoutes.GET("/top-sellers", reportHandler.TopSellers)
+		}
+
+		// Routes about the signed-in player
+		meRoutes := apiRoutes.Group("/me")
+		meRoutes.Use(api.RoleMiddleware("PLAYER"))
+		{
+			meRoutes.GET("/earnings", settlementHandler.GetMyEarnings)
+		}
+
+		// Store credit routes
+		creditRoutes := apiRoutes.Group("/credits")
+		{
+			// Store actions: top up a customer's credit and look up their balance
+			creditRoutes.POST("/top-ups", api.RoleMiddleware("STORE"), creditHandler.TopUp)
+			creditRoutes.GET("/accounts/:userId", api.RoleMiddleware("STORE"), creditHandler.GetStoreAccount)
+			// Player actions: own balances, across stores or at one store
+			creditRoutes.GET("", api.RoleMiddleware("PLAYER"), creditHandler.ListMyBalances)
+			creditRoutes.GET("/stores/:storeId", api.RoleMiddleware("PLAYER"), creditHandler.GetMyAccount)
 		}
 	}
 
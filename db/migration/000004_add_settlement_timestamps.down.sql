@@ -0,0 +1,2 @@
+ALTER TABLE settlements DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE settlements DROP COLUMN IF EXISTS requested_at;
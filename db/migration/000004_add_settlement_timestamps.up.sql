@@ -0,0 +1,10 @@
+-- Track when each settlement transition happened.
+ALTER TABLE settlements ADD COLUMN requested_at TIMESTAMP;
+ALTER TABLE settlements ADD COLUMN completed_at TIMESTAMP;
+
+-- Existing settlements were requested when they were created.
+UPDATE settlements SET requested_at = created_at WHERE requested_at IS NULL;
+UPDATE settlements SET completed_at = updated_at WHERE status = 'COMPLETED' AND completed_at IS NULL;
+
+ALTER TABLE settlements ALTER COLUMN requested_at SET NOT NULL;
+ALTER TABLE settlements ALTER COLUMN requested_at SET DEFAULT NOW();
@@ -0,0 +1,3 @@
+-- Stores can be deactivated; only ACTIVE stores accept new consignments.
+ALTER TABLE stores ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE'
+    CHECK (status IN ('ACTIVE', 'INACTIVE'));
@@ -0,0 +1,6 @@
+-- Collapse the derived statuses back into the original two.
+UPDATE consignments SET status = 'COMPLETED' WHERE status IN ('PARTIALLY_APPROVED', 'ALL_REJECTED', 'CLOSED');
+
+ALTER TABLE consignments DROP CONSTRAINT IF EXISTS consignments_status_check;
+ALTER TABLE consignments ADD CONSTRAINT consignments_status_check
+    CHECK (status IN ('PROCESSING', 'COMPLETED'));
@@ -0,0 +1,20 @@
+-- Allow the request statuses that are now derived from item states.
+ALTER TABLE consignments DROP CONSTRAINT IF EXISTS consignments_status_check;
+ALTER TABLE consignments ADD CONSTRAINT consignments_status_check
+    CHECK (status IN ('PROCESSING', 'COMPLETED', 'PARTIALLY_APPROVED', 'ALL_REJECTED', 'CLOSED'));
+
+-- Backfill existing requests with the same rules as model.DeriveConsignmentRequestStatus.
+UPDATE consignments c SET status = derived.status, updated_at = NOW()
+FROM (
+    SELECT consignment_id,
+        CASE
+            WHEN COUNT(*) FILTER (WHERE status = 'PENDING') > 0 THEN 'PROCESSING'
+            WHEN COUNT(*) FILTER (WHERE status = 'REJECTED') = COUNT(*) THEN 'ALL_REJECTED'
+            WHEN COUNT(*) FILTER (WHERE status IN ('REJECTED', 'CLEARED')) = COUNT(*) THEN 'CLOSED'
+            WHEN COUNT(*) FILTER (WHERE status = 'REJECTED') > 0 THEN 'PARTIALLY_APPROVED'
+            ELSE 'COMPLETED'
+        END AS status
+    FROM consignment_items
+    GROUP BY consignment_id
+) derived
+WHERE c.id = derived.consignment_id AND c.status <> derived.status;
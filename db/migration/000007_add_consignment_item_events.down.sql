@@ -0,0 +1 @@
+DROP TABLE IF EXISTS consignment_item_events;
@@ -0,0 +1,16 @@
+-- Audit trail of every consignment item status change.
+-- actor_id is NULL for changes made by the system; from_status is NULL for the submission event.
+CREATE TABLE consignment_item_events (
+    id SERIAL PRIMARY KEY,
+    consignment_item_id INT NOT NULL REFERENCES consignment_items(id) ON DELETE CASCADE,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_consignment_item_events_item ON consignment_item_events(consignment_item_id, created_at);
+
+-- Settlement used to write "Settled" into rejection_reason; that column is now only set on rejection.
+UPDATE consignment_items SET rejection_reason = NULL WHERE status = 'CLEARED' AND rejection_reason = 'Settled';
@@ -0,0 +1,13 @@
+ALTER TABLE settlements DROP COLUMN IF EXISTS fees_deducted;
+
+DROP TABLE IF EXISTS withdrawal_fees;
+
+ALTER TABLE stores DROP COLUMN IF EXISTS withdrawal_fee;
+
+-- Fold the new item statuses back into the closest old ones before restoring the constraint.
+UPDATE consignment_items SET status = 'REJECTED' WHERE status IN ('CANCELLED', 'RETURNED');
+UPDATE consignment_items SET status = 'APPROVED' WHERE status = 'WITHDRAWN';
+
+ALTER TABLE consignment_items DROP CONSTRAINT IF EXISTS consignment_items_status_check;
+ALTER TABLE consignment_items ADD CONSTRAINT consignment_items_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'SOLD', 'CLEARED'));
@@ -0,0 +1,24 @@
+-- Players can cancel pending items and withdraw approved ones; the store confirms the physical return.
+ALTER TABLE consignment_items DROP CONSTRAINT IF EXISTS consignment_items_status_check;
+ALTER TABLE consignment_items ADD CONSTRAINT consignment_items_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'SOLD', 'CLEARED', 'CANCELLED', 'WITHDRAWN', 'RETURNED'));
+
+-- Optional flat fee a store charges for each withdrawn item (0 means no fee).
+ALTER TABLE stores ADD COLUMN withdrawal_fee NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (withdrawal_fee >= 0);
+
+-- Fees charged when a withdrawn item is returned. They stay outstanding (settlement_id IS NULL)
+-- until a later settlement between the same player and store deducts them.
+CREATE TABLE withdrawal_fees (
+    id SERIAL PRIMARY KEY,
+    consignment_item_id INT UNIQUE NOT NULL REFERENCES consignment_items(id) ON DELETE CASCADE,
+    player_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    settlement_id INT REFERENCES settlements(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_withdrawal_fees_outstanding ON withdrawal_fees(player_id, store_id) WHERE settlement_id IS NULL;
+
+-- Amount deducted from a settlement for withdrawal fees; settlements.amount is the net payout.
+ALTER TABLE settlements ADD COLUMN fees_deducted NUMERIC(12,2) NOT NULL DEFAULT 0;
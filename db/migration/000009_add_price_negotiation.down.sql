@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS price_proposals;
+
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS listed_price;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS min_price;
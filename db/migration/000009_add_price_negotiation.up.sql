@@ -0,0 +1,17 @@
+-- Player's price floor (0 = none) and the price agreed with the store (0 = not agreed yet).
+ALTER TABLE consignment_items ADD COLUMN min_price NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (min_price >= 0);
+ALTER TABLE consignment_items ADD COLUMN listed_price NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (listed_price >= 0);
+
+-- Offers exchanged between the store and the player. At most one proposal per item is OPEN.
+CREATE TABLE price_proposals (
+    id SERIAL PRIMARY KEY,
+    consignment_item_id INT NOT NULL REFERENCES consignment_items(id) ON DELETE CASCADE,
+    proposed_by VARCHAR(10) NOT NULL CHECK (proposed_by IN ('STORE', 'PLAYER')),
+    proposer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    price NUMERIC(10,2) NOT NULL CHECK (price > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'ACCEPTED', 'SUPERSEDED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_price_proposals_one_open ON price_proposals(consignment_item_id) WHERE status = 'OPEN';
@@ -0,0 +1,17 @@
+UPDATE consignment_items SET status = 'APPROVED' WHERE status = 'EXPIRED';
+
+ALTER TABLE consignment_items DROP CONSTRAINT IF EXISTS consignment_items_status_check;
+ALTER TABLE consignment_items ADD CONSTRAINT consignment_items_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'SOLD', 'CLEARED', 'CANCELLED', 'WITHDRAWN', 'RETURNED'));
+
+DROP INDEX IF EXISTS idx_consignment_items_expires_at;
+
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS price_drops;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS listed_at;
+
+ALTER TABLE consignments DROP COLUMN IF EXISTS agreement_days;
+
+ALTER TABLE stores DROP COLUMN IF EXISTS price_drop_rate;
+ALTER TABLE stores DROP COLUMN IF EXISTS price_drop_days;
+ALTER TABLE stores DROP COLUMN IF EXISTS agreement_days;
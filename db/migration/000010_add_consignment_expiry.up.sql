@@ -0,0 +1,21 @@
+-- Per-store consignment policy. 0 disables the feature.
+ALTER TABLE stores ADD COLUMN agreement_days INT NOT NULL DEFAULT 0 CHECK (agreement_days >= 0);
+ALTER TABLE stores ADD COLUMN price_drop_days INT NOT NULL DEFAULT 0 CHECK (price_drop_days >= 0);
+ALTER TABLE stores ADD COLUMN price_drop_rate NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (price_drop_rate >= 0 AND price_drop_rate <= 100);
+
+-- The agreement period the player accepted when submitting, copied from the store.
+ALTER TABLE consignments ADD COLUMN agreement_days INT NOT NULL DEFAULT 0;
+
+-- listed_at is when the item went on sale (approval or re-listing); expires_at ends the agreement
+-- period and is NULL when the store has none. price_drops counts the automatic drops applied so far.
+ALTER TABLE consignment_items ADD COLUMN listed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE consignment_items ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE consignment_items ADD COLUMN price_drops INT NOT NULL DEFAULT 0;
+
+UPDATE consignment_items SET listed_at = updated_at WHERE status = 'APPROVED';
+
+CREATE INDEX idx_consignment_items_expires_at ON consignment_items(expires_at) WHERE status = 'APPROVED';
+
+ALTER TABLE consignment_items DROP CONSTRAINT IF EXISTS consignment_items_status_check;
+ALTER TABLE consignment_items ADD CONSTRAINT consignment_items_status_check
+    CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'SOLD', 'CLEARED', 'CANCELLED', 'WITHDRAWN', 'RETURNED', 'EXPIRED'));
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS consignment_item_photos;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS grade_score;
+ALTER TABLE transactions DROP COLUMN IF EXISTS grade_company;
+ALTER TABLE transactions DROP COLUMN IF EXISTS condition;
+
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS grade_score;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS grade_company;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS condition;
@@ -0,0 +1,21 @@
+-- Condition of the physical copy, recorded by the store when it approves the item.
+-- grade_company and grade_score are only set for copies graded by a third party.
+ALTER TABLE consignment_items ADD COLUMN condition VARCHAR(3) CHECK (condition IN ('NM', 'LP', 'MP', 'HP', 'DMG'));
+ALTER TABLE consignment_items ADD COLUMN grade_company VARCHAR(50);
+ALTER TABLE consignment_items ADD COLUMN grade_score NUMERIC(3,1) CHECK (grade_score >= 1 AND grade_score <= 10);
+
+-- Sales keep the condition the item was sold in.
+ALTER TABLE transactions ADD COLUMN condition VARCHAR(3);
+ALTER TABLE transactions ADD COLUMN grade_company VARCHAR(50);
+ALTER TABLE transactions ADD COLUMN grade_score NUMERIC(3,1);
+
+-- Photos the store took of each item at intake.
+CREATE TABLE consignment_item_photos (
+    id SERIAL PRIMARY KEY,
+    consignment_item_id INT NOT NULL REFERENCES consignment_items(id) ON DELETE CASCADE,
+    url VARCHAR(255) NOT NULL,
+    uploaded_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_consignment_item_photos_item ON consignment_item_photos(consignment_item_id);
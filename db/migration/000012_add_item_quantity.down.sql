@@ -0,0 +1,4 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS split_from_id;
+ALTER TABLE consignment_items DROP COLUMN IF EXISTS quantity;
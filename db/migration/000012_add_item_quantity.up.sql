@@ -0,0 +1,7 @@
+-- An item stands for one or more copies of the same card. Partial approvals and partial sales
+-- split the units off into a new item, remembered in split_from_id.
+ALTER TABLE consignment_items ADD COLUMN quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0);
+ALTER TABLE consignment_items ADD COLUMN split_from_id INT REFERENCES consignment_items(id) ON DELETE SET NULL;
+
+-- transactions.price is the unit price; existing sales were all of a single copy.
+ALTER TABLE transactions ADD COLUMN quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0);
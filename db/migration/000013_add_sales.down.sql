@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_sale_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS sale_id;
+
+DROP TABLE IF EXISTS sale_tenders;
+DROP TABLE IF EXISTS sales;
@@ -0,0 +1,25 @@
+-- A sale groups the transactions rung up together at the counter under one receipt.
+CREATE TABLE sales (
+    id SERIAL PRIMARY KEY,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    receipt_number VARCHAR(30) NOT NULL UNIQUE,
+    total NUMERIC(12,2) NOT NULL CHECK (total >= 0),
+    cashier_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sales_store_id ON sales(store_id);
+
+-- How the buyer paid; a sale may be split across payment methods.
+CREATE TABLE sale_tenders (
+    id SERIAL PRIMARY KEY,
+    sale_id INT NOT NULL REFERENCES sales(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('CASH', 'CREDIT')),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX idx_sale_tenders_sale_id ON sale_tenders(sale_id);
+
+ALTER TABLE transactions ADD COLUMN sale_id INT REFERENCES sales(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_sale_id ON transactions(sale_id);
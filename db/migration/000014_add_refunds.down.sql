@@ -0,0 +1,18 @@
+ALTER TABLE settlements DROP COLUMN IF EXISTS adjustments_deducted;
+
+DROP TABLE IF EXISTS settlement_adjustments;
+
+DROP INDEX IF EXISTS idx_transactions_reverses_id;
+
+DELETE FROM transactions WHERE type = 'REFUND';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_quantity_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_quantity_check CHECK (quantity > 0);
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE transactions DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE transactions DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reason;
+ALTER TABLE transactions DROP COLUMN IF EXISTS actor_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reverses_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS type;
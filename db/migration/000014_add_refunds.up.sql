@@ -0,0 +1,32 @@
+-- A sale can be voided (same day, before settlement) or refunded. A refund is written as a
+-- REFUND transaction reversing the sale: same unit price, negative quantity.
+ALTER TABLE transactions ADD COLUMN type VARCHAR(10) NOT NULL DEFAULT 'SALE' CHECK (type IN ('SALE', 'REFUND'));
+ALTER TABLE transactions ADD COLUMN reverses_id INT REFERENCES transactions(id) ON DELETE CASCADE;
+ALTER TABLE transactions ADD COLUMN actor_id INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN reason TEXT;
+ALTER TABLE transactions ADD COLUMN voided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE transactions ADD COLUMN voided_by INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN void_reason TEXT;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_quantity_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_quantity_check
+    CHECK ((type = 'SALE' AND quantity > 0) OR (type = 'REFUND' AND quantity < 0 AND reverses_id IS NOT NULL));
+
+-- A sale is refunded at most once.
+CREATE UNIQUE INDEX idx_transactions_reverses_id ON transactions(reverses_id) WHERE reverses_id IS NOT NULL;
+
+-- Player shares paid out for sales that were refunded after settlement. They stay outstanding
+-- (settlement_id IS NULL) until a later settlement between the same player and store deducts them.
+CREATE TABLE settlement_adjustments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT UNIQUE NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    player_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    settlement_id INT REFERENCES settlements(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_settlement_adjustments_outstanding ON settlement_adjustments(player_id, store_id) WHERE settlement_id IS NULL;
+
+ALTER TABLE settlements ADD COLUMN adjustments_deducted NUMERIC(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS buyer_id;
+ALTER TABLE sales DROP COLUMN IF EXISTS buyer_id;
+
+DROP TRIGGER IF EXISTS credit_ledger_entries_append_only ON credit_ledger_entries;
+DROP FUNCTION IF EXISTS reject_credit_ledger_update();
+
+DROP TABLE IF EXISTS credit_ledger_entries;
@@ -0,0 +1,33 @@
+-- Store credit (儲值金): an append-only ledger per user and store. A balance is the sum of its
+-- entries; top-ups and refunds add credit, CREDIT sales spend it.
+CREATE TABLE credit_ledger_entries (
+    id SERIAL PRIMARY KEY,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('TOP_UP', 'SPEND', 'REFUND', 'SETTLEMENT')),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount <> 0),
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    sale_id INT REFERENCES sales(id) ON DELETE SET NULL,
+    settlement_id INT REFERENCES settlements(id) ON DELETE SET NULL,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_ledger_entries_account ON credit_ledger_entries(store_id, user_id);
+
+-- Entries are never edited. Only the reference columns may change, when the row they point
+-- to is deleted.
+CREATE FUNCTION reject_credit_ledger_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'credit_ledger_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER credit_ledger_entries_append_only
+    BEFORE UPDATE OF store_id, user_id, kind, amount, note, created_at ON credit_ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_credit_ledger_update();
+
+-- The account a CREDIT sale was charged to, so voids and refunds can give the credit back.
+ALTER TABLE sales ADD COLUMN buyer_id INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN buyer_id INT REFERENCES users(id) ON DELETE SET NULL;
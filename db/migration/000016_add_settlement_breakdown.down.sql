@@ -0,0 +1,5 @@
+ALTER TABLE settlements DROP COLUMN IF EXISTS net_amount;
+ALTER TABLE settlements DROP COLUMN IF EXISTS commission_amount;
+ALTER TABLE settlements DROP COLUMN IF EXISTS gross_amount;
+ALTER TABLE settlements DROP COLUMN IF EXISTS commission_rate;
+ALTER TABLE settlements DROP COLUMN IF EXISTS payout_method;
@@ -0,0 +1,11 @@
+-- The player picks how to be paid when requesting a settlement. The sales are split again at the
+-- store's commission rate for that method, and the breakdown is kept on the settlement.
+ALTER TABLE settlements ADD COLUMN payout_method VARCHAR(10) NOT NULL DEFAULT 'CASH' CHECK (payout_method IN ('CASH', 'CREDIT'));
+ALTER TABLE settlements ADD COLUMN commission_rate NUMERIC(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE settlements ADD COLUMN gross_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE settlements ADD COLUMN commission_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE settlements ADD COLUMN net_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+-- Earlier settlements only kept the payout; their net is what was paid plus what was deducted.
+-- The sales behind them were split at several rates, so gross and commission stay unknown (0).
+UPDATE settlements SET net_amount = amount + fees_deducted + adjustments_deducted;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS settlement_transactions;
+ALTER TABLE stores DROP COLUMN IF EXISTS min_settlement_amount;
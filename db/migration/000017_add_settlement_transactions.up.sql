@@ -0,0 +1,37 @@
+-- Stores can set a minimum payout; a settlement whose player share is below it is refused.
+ALTER TABLE stores ADD COLUMN min_settlement_amount NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (min_settlement_amount >= 0);
+
+-- The sales each settlement paid, with the split at the settlement's commission rate.
+-- A sale is settled at most once.
+CREATE TABLE settlement_transactions (
+    id SERIAL PRIMARY KEY,
+    settlement_id INT NOT NULL REFERENCES settlements(id) ON DELETE CASCADE,
+    transaction_id INT UNIQUE NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    gross_amount NUMERIC(12,2) NOT NULL,
+    commission_amount NUMERIC(12,2) NOT NULL,
+    net_amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_settlement_transactions_settlement ON settlement_transactions(settlement_id);
+
+-- Recover the lines of earlier settlements from the item history: each cleared item was paid
+-- for by its last sale before it was cleared. Settlements from before the breakdown was kept
+-- were split at the rate frozen on each sale; later ones at the settlement's own rate.
+INSERT INTO settlement_transactions (settlement_id, transaction_id, gross_amount, commission_amount, net_amount)
+SELECT s.id, sale.id,
+       sale.price * sale.quantity,
+       ROUND(sale.price * r.rate / 100, 2) * sale.quantity,
+       (sale.price - ROUND(sale.price * r.rate / 100, 2)) * sale.quantity
+FROM consignment_item_events e
+JOIN settlements s ON e.reason = 'cleared by settlement ' || s.id
+JOIN LATERAL (
+    SELECT t.* FROM transactions t
+    WHERE t.consignment_item_id = e.consignment_item_id AND t.type = 'SALE' AND t.created_at <= e.created_at
+    ORDER BY t.created_at DESC, t.id DESC
+    LIMIT 1
+) sale ON TRUE
+CROSS JOIN LATERAL (
+    SELECT CASE WHEN s.gross_amount > 0 THEN s.commission_rate ELSE sale.commission_rate END AS rate
+) r
+WHERE e.to_status = 'CLEARED'
+ON CONFLICT (transaction_id) DO NOTHING;
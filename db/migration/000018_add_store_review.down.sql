@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_stores_status;
+
+ALTER TABLE stores DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE stores DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE stores DROP COLUMN IF EXISTS status_reason;
+
+UPDATE stores SET status = 'INACTIVE' WHERE status IN ('PENDING_REVIEW', 'REJECTED', 'SUSPENDED');
+ALTER TABLE stores ALTER COLUMN status SET DEFAULT 'ACTIVE';
+ALTER TABLE stores DROP CONSTRAINT IF EXISTS stores_status_check;
+ALTER TABLE stores ADD CONSTRAINT stores_status_check CHECK (status IN ('ACTIVE', 'INACTIVE'));
@@ -0,0 +1,12 @@
+-- New stores wait for an admin to review them. Admins approve or reject them, and can suspend
+-- an active store; the reason and the reviewing admin are kept with the store.
+ALTER TABLE stores DROP CONSTRAINT IF EXISTS stores_status_check;
+ALTER TABLE stores ADD CONSTRAINT stores_status_check
+    CHECK (status IN ('PENDING_REVIEW', 'ACTIVE', 'INACTIVE', 'REJECTED', 'SUSPENDED'));
+ALTER TABLE stores ALTER COLUMN status SET DEFAULT 'PENDING_REVIEW';
+
+ALTER TABLE stores ADD COLUMN status_reason TEXT;
+ALTER TABLE stores ADD COLUMN reviewed_by INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE stores ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_stores_status ON stores(status);
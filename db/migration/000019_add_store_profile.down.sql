@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_stores_city;
+
+ALTER TABLE stores DROP COLUMN IF EXISTS opening_hours;
+ALTER TABLE stores DROP COLUMN IF EXISTS email;
+ALTER TABLE stores DROP COLUMN IF EXISTS phone;
+ALTER TABLE stores DROP COLUMN IF EXISTS city;
+ALTER TABLE stores DROP COLUMN IF EXISTS address;
@@ -0,0 +1,8 @@
+-- Contact details and opening hours that stores show in the public store directory.
+ALTER TABLE stores ADD COLUMN address TEXT;
+ALTER TABLE stores ADD COLUMN city VARCHAR(100);
+ALTER TABLE stores ADD COLUMN phone VARCHAR(50);
+ALTER TABLE stores ADD COLUMN email VARCHAR(255);
+ALTER TABLE stores ADD COLUMN opening_hours TEXT;
+
+CREATE INDEX idx_stores_city ON stores(LOWER(city));
@@ -0,0 +1,4 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS commission_rule_id;
+
+DROP TABLE IF EXISTS commission_rules;
+DROP TABLE IF EXISTS commission_schedules;
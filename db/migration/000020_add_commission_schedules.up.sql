@@ -0,0 +1,30 @@
+-- Versioned commission terms. A store adds a new schedule to change its rates; the schedule with
+-- the latest effective_from not after a sale is in force for it. Sales no rule covers are charged
+-- the store's base rates (stores.commission_cash / commission_credit).
+CREATE TABLE commission_schedules (
+    id SERIAL PRIMARY KEY,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (store_id, effective_from)
+);
+
+-- The rates of a schedule by unit price band [min_price, max_price), optionally only for cards
+-- of one rarity and/or series.
+CREATE TABLE commission_rules (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES commission_schedules(id) ON DELETE CASCADE,
+    min_price NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (min_price >= 0),
+    max_price NUMERIC(12,2) CHECK (max_price > min_price),
+    rarity VARCHAR(50),
+    series VARCHAR(255),
+    commission_cash NUMERIC(5,2) NOT NULL CHECK (commission_cash BETWEEN 0 AND 100),
+    commission_credit NUMERIC(5,2) NOT NULL CHECK (commission_credit BETWEEN 0 AND 100)
+);
+
+CREATE INDEX idx_commission_rules_schedule ON commission_rules(schedule_id);
+
+-- The rule each sale was charged under; NULL for the store's base rates.
+ALTER TABLE transactions ADD COLUMN commission_rule_id INT REFERENCES commission_rules(id);
@@ -0,0 +1,5 @@
+ALTER TABLE settlements DROP COLUMN IF EXISTS completed_by;
+ALTER TABLE cards DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE cards DROP COLUMN IF EXISTS created_by;
+
+DROP TABLE IF EXISTS store_members;
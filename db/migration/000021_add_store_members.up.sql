@@ -0,0 +1,25 @@
+-- Store staff. A store's owner invites other store users with a role; the invite becomes an
+-- active membership once the user accepts it. Owners are members with the OWNER role.
+CREATE TABLE store_members (
+    id SERIAL PRIMARY KEY,
+    store_id INT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('OWNER', 'MANAGER', 'CASHIER', 'INTAKE_CLERK')),
+    status VARCHAR(20) NOT NULL DEFAULT 'INVITED' CHECK (status IN ('INVITED', 'ACTIVE', 'DECLINED', 'REMOVED')),
+    invited_by INT REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A user works at one store at a time, and has at most one open invite or membership per store.
+CREATE UNIQUE INDEX idx_store_members_active_user ON store_members(user_id) WHERE status = 'ACTIVE';
+CREATE UNIQUE INDEX idx_store_members_open ON store_members(store_id, user_id) WHERE status IN ('INVITED', 'ACTIVE');
+
+INSERT INTO store_members (store_id, user_id, role, status, accepted_at, created_at, updated_at)
+SELECT id, user_id, 'OWNER', 'ACTIVE', created_at, created_at, created_at FROM stores;
+
+-- Which staff member made changes that are not already kept in a history table.
+ALTER TABLE cards ADD COLUMN created_by INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE cards ADD COLUMN updated_by INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE settlements ADD COLUMN completed_by INT REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+-- Login sessions. Each login starts a session whose refresh token is replaced on every refresh;
+-- only SHA-256 hashes of refresh tokens are stored. Access tokens carry the session ID, so a
+-- revoked session stops working on its next request.
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    -- The token replaced by the last refresh, kept to spot a stolen token being replayed
+    previous_token_hash VARCHAR(64),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_previous_token_hash ON sessions(previous_token_hash);
@@ -24,6 +24,301 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/admin/stores": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin lists stores, optionally only those in one status (e.g. PENDING_REVIEW for the review queue), oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List stores for review",
+                "parameters": [
+                    {
+                        "enum": [
+                            "PENDING_REVIEW",
+                            "ACTIVE",
+                            "INACTIVE",
+                            "REJECTED",
+                            "SUSPENDED"
+                        ],
+                        "type": "string",
+                        "description": "Store status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Store"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid store status\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list stores\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/stores/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin opens a store that is pending review, was rejected or is suspended.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Approve a store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid store ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"store cannot be moved to that status\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to review store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/stores/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin turns down a store registration with a reason the store owner can see.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reject a store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the rejection",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"a reason is required\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"store cannot be moved to that status\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to review store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/stores/{id}/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin closes an active store with a reason. A suspended store cannot manage cards, consignments or sales until it is approved again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Suspend a store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the suspension",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"a reason is required\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"store cannot be moved to that status\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to review store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/api/cards": {
             "get": {
                 "security": [
@@ -66,7 +361,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new card to the store associated with the user, with an optional image upload.",
+                "description": "Adds a new card to the store associated with the user, with an optional JPEG, PNG or WebP image upload.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -118,7 +413,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"bad_request_error\"}",
+                        "description": "{\"error\": \"image must be a JPEG, PNG or WebP file\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -369,43 +664,96 @@ const docTemplate = `{
             }
         },
         "/api/consignments": {
-            "post": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Player creates a consignment request for one or more cards to a store.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Players see their own consignments; stores see the consignments addressed to their store. Each consignment includes its items with card details.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "consignments"
                 ],
-                "summary": "Create a new consignment request",
+                "summary": "List consignments",
                 "parameters": [
                     {
-                        "description": "Consignment Request Information",
-                        "name": "consignment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.CreateConsignmentRequest"
-                        }
+                        "enum": [
+                            "PROCESSING",
+                            "COMPLETED",
+                            "PARTIALLY_APPROVED",
+                            "ALL_REJECTED",
+                            "CLOSED"
+                        ],
+                        "type": "string",
+                        "description": "Filter by request status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "APPROVED",
+                            "REJECTED",
+                            "SOLD",
+                            "CLEARED",
+                            "CANCELLED",
+                            "WITHDRAWN",
+                            "RETURNED",
+                            "EXPIRED"
+                        ],
+                        "type": "string",
+                        "description": "Only consignments with at least one item in this status",
+                        "name": "item_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Consignment"
+                            "$ref": "#/definitions/api.ConsignmentListResponse"
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"bad request\"}",
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -414,7 +762,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"failed to create consignment request\"}",
+                        "description": "{\"error\": \"failed to list consignments\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -423,16 +771,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/consignments/items/{itemId}": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Store approves or rejects a consignment item.",
+                "description": "Player creates a consignment request for one or more cards to a store. Cards can be given as plain card_ids or as items with a quantity and an optional minimum price.",
                 "consumes": [
                     "application/json"
                 ],
@@ -442,34 +788,27 @@ const docTemplate = `{
                 "tags": [
                     "consignments"
                 ],
-                "summary": "Update a consignment item's status",
+                "summary": "Create a new consignment request",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Consignment Item ID",
-                        "name": "itemId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New Status",
-                        "name": "status",
+                        "description": "Consignment Request Information",
+                        "name": "consignment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateConsignmentItemStatusRequest"
+                            "$ref": "#/definitions/api.CreateConsignmentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.ConsignmentItem"
+                            "$ref": "#/definitions/model.Consignment"
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"invalid item ID or bad request\"}",
+                        "description": "{\"error\": \"bad request\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -477,8 +816,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "{\"error\": \"permission denied\"}",
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -486,8 +825,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "{\"error\": \"item not found\"}",
+                    "409": {
+                        "description": "{\"error\": \"store is not accepting consignments\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -495,8 +834,14 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.InvalidCardsResponse"
+                        }
+                    },
                     "500": {
-                        "description": "{\"error\": \"failed to update item status\"}",
+                        "description": "{\"error\": \"failed to create consignment request\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -507,44 +852,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/settlements": {
-            "post": {
+        "/api/consignments/expiring": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Player creates a settlement request to clear their earnings from a store.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Store lists its items on sale whose agreement period ends within the given number of days, soonest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "consignments"
                 ],
-                "summary": "Create a new settlement request",
+                "summary": "List items expiring soon",
                 "parameters": [
                     {
-                        "description": "Settlement Request Information",
-                        "name": "settlement",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.CreateSettlementRequest"
-                        }
+                        "type": "integer",
+                        "default": 7,
+                        "description": "Look-ahead in days",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Settlement"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsignmentItem"
+                            }
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"bad request\"}",
+                        "description": "{\"error\": \"invalid days\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -552,8 +895,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "{\"error\": \"conflict (e.g., no unsettled transactions)\"}",
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -562,7 +905,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"failed to create settlement request\"}",
+                        "description": "{\"error\": \"failed to retrieve expiring items\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -573,14 +916,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transactions": {
+        "/api/consignments/items/review": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Store creates a transaction for a sold consignment item.",
+                "description": "Store approves or rejects many items in one request, each decision taking the same fields as the single-item update. All decisions are applied in one database transaction: if any is refused, none is applied and the refused decisions carry an error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -588,25 +931,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "consignments"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Review many consignment items at once",
                 "parameters": [
                     {
-                        "description": "Transaction Information",
-                        "name": "transaction",
+                        "description": "Item Decisions",
+                        "name": "decisions",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateTransactionRequest"
+                            "$ref": "#/definitions/api.ReviewItemsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Transaction"
+                            "$ref": "#/definitions/api.ReviewItemsResponse"
                         }
                     },
                     "400": {
@@ -618,35 +961,14 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "{\"error\": \"permission denied\"}",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "{\"error\": \"item not found\"}",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "{\"error\": \"conflict (e.g., item not approved or already sold)\"}",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.ReviewItemsResponse"
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"failed to create transaction\"}",
+                        "description": "{\"error\": \"failed to review items\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -657,9 +979,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Authenticates a user and returns a JWT token.",
+        "/api/consignments/items/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store approves or rejects a consignment item. Approving records the copy's condition (NM/LP/MP/HP/DMG), plus the grade company and score for graded copies. With a quantity below the item's, only that many units get the status and the rest are split off into a new item with the opposite decision (e.g. approve 10 of 12, reject 2). A price is sent to the player as the store's listed price proposal.",
                 "consumes": [
                     "application/json"
                 ],
@@ -667,23 +994,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "consignments"
                 ],
-                "summary": "User Login",
+                "summary": "Update a consignment item's status",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Status",
+                        "name": "status",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.LoginRequest"
+                            "$ref": "#/definitions/api.UpdateConsignmentItemStatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{\"token\": \"your_jwt_token\"}",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID or bad request\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -691,8 +1031,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "{\"error\": \"bad_request_error\"}",
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -700,8 +1040,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "{\"error\": \"invalid email or password\"}",
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -710,7 +1050,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"internal_server_error\"}",
+                        "description": "{\"error\": \"failed to update item status\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -721,40 +1061,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
+        "/api/consignments/items/{itemId}/cancel": {
             "post": {
-                "description": "Creates a new user account.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Player cancels one of their items before the store has reviewed it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "consignments"
                 ],
-                "summary": "User Registration",
+                "summary": "Cancel a pending consignment item",
                 "parameters": [
                     {
-                        "description": "User Registration Info",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.RegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "{\"message\": \"user created successfully\", \"user_id\": 1}",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ConsignmentItem"
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"bad_request_error\"}",
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -763,7 +1120,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "{\"error\": \"email already exists\"}",
+                        "description": "{\"error\": \"status cannot be updated to the desired value: ...\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -772,7 +1129,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"failed to register user\"}",
+                        "description": "{\"error\": \"failed to cancel item\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -783,29 +1140,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/api/consignments/items/{itemId}/history": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get user details by ID. Requires authentication and ADMIN role.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists every status change of a consignment item, oldest first, with the acting user and reason. Only the submitting player and the receiving store may view it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "consignments"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get a consignment item's status history",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
                         "in": "path",
                         "required": true
                     }
@@ -814,11 +1168,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsignmentItemEvent"
+                            }
                         }
                     },
                     "400": {
-                        "description": "{\"error\": \"invalid user ID\"}",
+                        "description": "{\"error\": \"invalid item ID\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -826,8 +1183,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "{\"error\": \"unauthorized\"}",
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -835,8 +1192,72 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "{\"error\": \"forbidden\"}",
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to retrieve item history\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/items/{itemId}/photos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the photos the store took of the item, oldest first. Only the submitting player and the receiving store may view them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "List the intake photos of a consignment item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ConsignmentItemPhoto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -845,7 +1266,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "{\"error\": \"user not found\"}",
+                        "description": "{\"error\": \"item not found\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -854,7 +1275,94 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "{\"error\": \"database error\"}",
+                        "description": "{\"error\": \"failed to retrieve item photos\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store attaches a JPEG, PNG or WebP photo of the physical copy while the item is under review or on sale.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Upload an intake photo of a consignment item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItemPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"image must be a JPEG, PNG or WebP file\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"photos can only be added to items under review or on sale\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to upload photo\"}",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -864,323 +1372,5396 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.CreateConsignmentRequest": {
-            "type": "object",
-            "required": [
-                "card_ids",
-                "store_id"
+        },
+        "/api/consignments/items/{itemId}/price-proposals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the price negotiation of a consignment item, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "List an item's price proposals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PriceProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to retrieve price proposals\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The store proposes a listed price for an item; the player can counter an open store proposal with their own price. A new proposal replaces the open one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Propose or counter a price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proposed price",
+                        "name": "proposal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ProposePriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PriceProposal"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"there is no open price proposal from the other party\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to propose price\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/items/{itemId}/price-proposals/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts the other party's open price proposal; its price becomes the item's listed price.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Accept the open price proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"there is no open price proposal from the other party\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to accept price\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/items/{itemId}/relist": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player puts an expired item back on sale for a new agreement period.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Re-list an expired item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"status cannot be updated to the desired value: ...\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to re-list item\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/items/{itemId}/return": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store confirms that a withdrawn or expired card was handed back to the player. For withdrawals, the store's withdrawal fee, if any, is deducted from the player's next settlement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Confirm a withdrawn or expired item was returned",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"status cannot be updated to the desired value: ...\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to confirm return\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/items/{itemId}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player takes an approved item off sale and asks the store to hand it back. The item stays WITHDRAWN until the store confirms the return.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Request withdrawal of an approved item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid item ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"status cannot be updated to the desired value: ...\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to withdraw item\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/consignments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a consignment with its items and card details. Only the submitting player and the receiving store may view it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consignments"
+                ],
+                "summary": "Get a consignment by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consignment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Consignment"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid consignment ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"consignment not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to retrieve consignment\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/credits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player lists their store credit balance at every store they have credit history with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credits"
+                ],
+                "summary": "List my store credit balances",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CreditBalance"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/credits/accounts/{userId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store retrieves a user's store credit balance at the store with the ledger entries behind it, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credits"
+                ],
+                "summary": "Get a customer's store credit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid user ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/credits/stores/{storeId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player retrieves their store credit balance at one store with the ledger entries behind it, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credits"
+                ],
+                "summary": "Get my store credit at a store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "storeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid store ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/credits/top-ups": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store adds credit to a user's balance at the store after taking payment for it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credits"
+                ],
+                "summary": "Top up store credit",
+                "parameters": [
+                    {
+                        "description": "Account and amount",
+                        "name": "top_up",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TopUpCreditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"user not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to top up store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me/earnings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player sees, for every store they have consigned to, the units awaiting intake and on sale, the sold but unsettled sales with their share, and what has been settled and paid out; followed by a page of their sales, newest first, with card name, price, commission and net share. The date range filters the sales only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get my earnings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only this store",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.EarningsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get earnings\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/commission": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store sums up the commission it earned per payment method. Settled sales count what the settlement kept, the others the commission at the rate recorded at sale time; refunds give their sale's commission back. Add format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Commission by payment method",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sold on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CommissionReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to build report\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/liabilities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store lists what it owes each player as of now: the share of sold but unsettled sales at the rate recorded at sale time, less outstanding withdrawal fees and refund adjustments, plus requested settlements not yet paid out. Add format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Liability to players",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PlayerLiability"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to build report\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/sales": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store sums up its sales per day, week (starting Monday) or month: number of sales and refunds, units, sales total, commission and the players' share. Refunds count against the period they were made in; voided sales are left out. Add format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Sales by period",
+                "parameters": [
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket length",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SalesPeriodReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to build report\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/top-sellers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store ranks its best-selling cards, or series, by units sold and then by sales total. Refunded and voided sales are left out. Add format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Top sellers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "card",
+                            "series"
+                        ],
+                        "type": "string",
+                        "default": "card",
+                        "description": "What to rank",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of entries (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TopSeller"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to build report\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sales": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store sells several consignment items under one receipt number, paid with one or more tenders. Each line's commission uses the rate of the payment method assigned to it; when tenders use several methods every line must name one, and the tenders of each method must add up to its lines. CREDIT tenders are paid from the buyer's store credit at this store. The sale is all-or-nothing: if any line cannot be sold, nothing is recorded and the error names the line.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Check out a sale",
+                "parameters": [
+                    {
+                        "description": "Sale lines and tenders",
+                        "name": "sale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateSaleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request (e.g., tenders do not add up)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"line 1: consignment item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"line 1: conflict (e.g., item not approved, already sold or not enough units)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "{\"error\": \"line 1: sale price is below the player's minimum price, or not enough store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to create sale\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sales/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store retrieves one of its sales with its lines and tenders.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Get a sale",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid sale ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"sale not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get sale\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settlements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Players see the settlements they requested; stores see the settlement requests addressed to their store.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlements",
+                "parameters": [
+                    {
+                        "enum": [
+                            "REQUESTED",
+                            "COMPLETED"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requested on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requested on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Settlement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid filter\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"user does not have a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list settlements\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Player creates a settlement request to clear their earnings from a store, choosing how to be paid. The earnings are worked out at the store's commission rate for the payout method, and the settlement records the gross, commission and net. With payout_method CREDIT the payout goes into the player's store credit at once, completing the settlement. transaction_ids settles only the chosen sales; the player's share must reach the store's minimum payout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Create a new settlement request",
+                "parameters": [
+                    {
+                        "description": "Settlement Request Information",
+                        "name": "settlement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateSettlementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"conflict (e.g., no unsettled transactions)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "{\"error\": \"settlement amount is below the store's minimum payout\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to create settlement request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settlements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a settlement with the sales it paid, each split at the settlement's commission rate. Only the requesting player and the receiving store can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get a settlement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SettlementDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid settlement ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"settlement not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get settlement\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store marks a settlement request as COMPLETED after paying the player out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Complete a settlement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Status",
+                        "name": "settlement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateSettlementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid settlement ID or bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"settlement not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"settlement has already been completed\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to complete settlement\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/store-invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store account lists the invitations to join a store's staff it has not answered yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "List my store invitations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StoreMember"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list invitations\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/store-invitations/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store account joins the staff of the store that invited it. An account already working at a store has to leave it first (DELETE /api/stores/me/membership); a store owner cannot leave their own store.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Accept a store invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreMember"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid invitation ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"invitation not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"user already works at a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to accept invitation\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/store-invitations/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store account turns down an invitation to join a store's staff.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Decline a store invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"invitation declined\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid invitation ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"invitation not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to decline invitation\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists active stores by name, with their location, contact details and consignment terms, so players can find where to consign.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Search the store directory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the store name (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City (case-insensitive)",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreDirectoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid page\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to search stores\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner registers their store. It starts as PENDING_REVIEW and opens once an admin approves it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Create my store",
+                "parameters": [
+                    {
+                        "description": "Store details",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"user already works at a store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to create store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store staff retrieve the store they work at, including its review status and the reason it was rejected or suspended.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Get my store",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner replaces their store's profile, commission rates and policies. New rates apply to sales made from now on.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Update my store",
+                "parameters": [
+                    {
+                        "description": "Store details",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Store"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to update store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me/commission-schedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store staff list every version of their store's commission terms, past, current and upcoming, latest effective date first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "List my commission schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CommissionSchedule"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list commission schedules\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner adds a new version of their commission terms, with rates by unit price band and optional overrides per card rarity or series. The latest schedule that has taken effect applies to new sales; sales no rule covers are charged the store's base rates. The most specific matching rule wins: series, then rarity, then price band alone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Add a commission schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule and its rules",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateCommissionScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.CommissionSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"commission rules for the same cards cannot have overlapping price bands\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to create commission schedule\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me/commission-schedules/{scheduleId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner withdraws a commission schedule before it takes effect. Schedules already in force are kept, as sales refer to them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Delete an upcoming commission schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"commission schedule deleted successfully\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid schedule ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"commission schedule not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"commission schedule has already taken effect\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to delete commission schedule\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me/membership": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Staff member leaves the store they work at, e.g. to join another. What they did while on the staff stays recorded against them. The store owner cannot leave their own store.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Leave my store",
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"left the store successfully\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"the store owner cannot be changed or removed\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to leave store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me/staff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store staff list everyone working at their store, including its owner, and the invitations not yet answered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "List my store's staff",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StoreMember"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found for the current user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to list staff\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner invites another store account to their staff with a role. Managers can do everything but edit the store and its staff; cashiers can sell and top up store credit; intake clerks can manage cards and take in consignments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Invite a staff member",
+                "parameters": [
+                    {
+                        "description": "Email of the store account and its role",
+                        "name": "invite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.InviteStaffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreMember"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"staff role must be MANAGER, CASHIER or INTAKE_CLERK\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"your store role does not allow this action\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"user not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"user is already invited to or working at this store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to invite staff member\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/me/staff/{memberId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner changes the role of a staff member or of a pending invitation. The owner's own role cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Change a staff member's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Staff member ID",
+                        "name": "memberId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateStaffRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreMember"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid staff member ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"your store role does not allow this action\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"staff member not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"the store owner cannot be changed or removed\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to update staff member\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store owner takes a member off the staff, or withdraws an invitation. What they did while on the staff stays recorded against them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Remove a staff member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Staff member ID",
+                        "name": "memberId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"staff member removed successfully\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid staff member ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"your store role does not allow this action\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"staff member not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"the store owner cannot be changed or removed\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to remove staff member\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the public profile of an active store.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Get a store's profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StoreProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid store ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"store not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to get store\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store creates a transaction for a sold consignment item. The price is per unit; selling fewer units than the item holds leaves the rest on sale. Sales below the player's minimum price are refused; sales below the listed price succeed with a warning. CREDIT sales are paid from the buyer's store credit at this store.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction Information",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"item not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"conflict (e.g., item not approved, already sold or not enough units)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "{\"error\": \"sale price is below the player's minimum price, or not enough store credit\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to create transaction\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store refunds a sale when the buyer returns the card; a CREDIT sale goes back to the buyer's store credit. A reversing REFUND transaction (same unit price, negative quantity) is recorded and the item goes back on sale. Refunding an item that has already been settled requires adjust_settlement; the player's share is then taken back from their next settlement with the store.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Refund a transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the sale is refunded",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RefundTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"transaction not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"conflict (e.g., already voided or refunded, or settled without adjust_settlement)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to refund transaction\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store voids a sale rung up in error. Only sales made today whose item has not been settled can be voided; the item goes back on sale and a CREDIT sale goes back to the buyer's store credit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Void a transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the sale is voided",
+                        "name": "void",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.VoidTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad request\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"permission denied\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"transaction not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"conflict (e.g., already voided or refunded, not from today, or already settled)\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to void transaction\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/sessions": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends every session of a user, e.g. when their account was compromised. Their access tokens stop working straight away and they have to log in again. Requires ADMIN role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke a user's sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"sessions revoked\", \"revoked\": 2}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid user ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "{\"error\": \"unauthorized\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"forbidden\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"user not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to revoke sessions\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticates a user and starts a session. Returns a short-lived JWT access token and a refresh token to get new ones with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad_request_error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "{\"error\": \"invalid email or password\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"internal_server_error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "description": "Ends the session the refresh token belongs to. Its access tokens stop working straight away.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"message\": \"logged out successfully\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad_request_error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "{\"error\": \"invalid or expired refresh token\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to logout\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and refresh token. Each refresh token works once; presenting one that has already been used ends its session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad_request_error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "{\"error\": \"invalid or expired refresh token\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to refresh token\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Creates a new user account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "User Registration",
+                "parameters": [
+                    {
+                        "description": "User Registration Info",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "{\"message\": \"user created successfully\", \"user_id\": 1}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"bad_request_error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "{\"error\": \"email already exists\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"failed to register user\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user details by ID. Requires authentication and ADMIN role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "{\"error\": \"invalid user ID\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "{\"error\": \"unauthorized\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "{\"error\": \"forbidden\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "{\"error\": \"user not found\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "{\"error\": \"database error\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CommissionRuleRequest": {
+            "type": "object",
+            "properties": {
+                "commission_cash": {
+                    "description": "Percentage, 0-100",
+                    "type": "number"
+                },
+                "commission_credit": {
+                    "description": "Percentage, 0-100",
+                    "type": "number"
+                },
+                "max_price": {
+                    "description": "Exclusive upper bound, omit for none",
+                    "type": "number"
+                },
+                "min_price": {
+                    "description": "Inclusive lower bound on the unit price",
+                    "type": "number"
+                },
+                "rarity": {
+                    "description": "Only cards of this rarity",
+                    "type": "string"
+                },
+                "series": {
+                    "description": "Only cards of this series",
+                    "type": "string"
+                }
+            }
+        },
+        "api.ConsignmentItemRequest": {
+            "type": "object",
+            "required": [
+                "card_id"
+            ],
+            "properties": {
+                "card_id": {
+                    "type": "integer"
+                },
+                "min_price": {
+                    "description": "Optional per-unit floor; the store cannot sell below it",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Copies of the card, defaults to 1",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.ConsignmentListResponse": {
+            "type": "object",
+            "properties": {
+                "consignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Consignment"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CreateCommissionScheduleRequest": {
+            "type": "object",
+            "required": [
+                "rules"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "RFC 3339; omit to take effect straight away",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.CommissionRuleRequest"
+                    }
+                }
+            }
+        },
+        "api.CreateConsignmentRequest": {
+            "type": "object",
+            "required": [
+                "store_id"
+            ],
+            "properties": {
+                "card_ids": {
+                    "description": "Cards consigned without a minimum price",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "items": {
+                    "description": "Cards with a quantity and an optional minimum price",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ConsignmentItemRequest"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CreateSaleRequest": {
+            "type": "object",
+            "required": [
+                "lines",
+                "tenders"
+            ],
+            "properties": {
+                "buyer_id": {
+                    "description": "Store credit account to charge, required for CREDIT tenders",
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.SaleLineRequest"
+                    }
+                },
+                "tenders": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.SaleTenderRequest"
+                    }
+                }
+            }
+        },
+        "api.CreateSettlementRequest": {
+            "type": "object",
+            "required": [
+                "store_id"
+            ],
+            "properties": {
+                "payout_method": {
+                    "description": "Defaults to CASH",
+                    "enum": [
+                        "CASH",
+                        "CREDIT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ]
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "transaction_ids": {
+                    "description": "Sales to settle; every unsettled sale when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.CreateTransactionRequest": {
+            "type": "object",
+            "required": [
+                "consignment_item_id",
+                "payment_method",
+                "price"
+            ],
+            "properties": {
+                "buyer_id": {
+                    "description": "Store credit account to charge, required for CREDIT",
+                    "type": "integer"
+                },
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "enum": [
+                        "CASH",
+                        "CREDIT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ]
+                },
+                "price": {
+                    "description": "Unit price",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Units sold, defaults to 1",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.EarningsResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "sales": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SaleEarning"
+                    }
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StoreEarnings"
+                    }
+                },
+                "total": {
+                    "description": "Matching sales across all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.InvalidCardsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "invalid_card_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.InviteStaffRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "MANAGER",
+                        "CASHIER",
+                        "INTAKE_CLERK"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StoreRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.ItemDecisionRequest": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "status"
+            ],
+            "properties": {
+                "condition": {
+                    "description": "Required when approving",
+                    "enum": [
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CardCondition"
+                        }
+                    ]
+                },
+                "grade_company": {
+                    "description": "Only for third-party graded copies",
+                    "type": "string"
+                },
+                "grade_score": {
+                    "type": "number"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "Optional listed price offered to the player",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Units the status applies to, 0 for all; the rest get the opposite decision",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "APPROVED",
+                        "REJECTED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ConsignmentItemStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ProposePriceRequest": {
+            "type": "object",
+            "required": [
+                "price"
+            ],
+            "properties": {
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RefundTransactionRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "adjust_settlement": {
+                    "description": "Required to refund an item that has already been settled",
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "role": {
+                    "description": "ADMIN role cannot be self-assigned",
+                    "type": "string",
+                    "enum": [
+                        "PLAYER",
+                        "STORE"
+                    ]
+                }
+            }
+        },
+        "api.ReviewItemsRequest": {
+            "type": "object",
+            "required": [
+                "decisions"
+            ],
+            "properties": {
+                "decisions": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.ItemDecisionRequest"
+                    }
+                }
+            }
+        },
+        "api.ReviewItemsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ItemReviewResult"
+                    }
+                }
+            }
+        },
+        "api.SaleLineRequest": {
+            "type": "object",
+            "required": [
+                "consignment_item_id",
+                "price"
+            ],
+            "properties": {
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "description": "Required when tenders use several methods",
+                    "enum": [
+                        "CASH",
+                        "CREDIT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ]
+                },
+                "price": {
+                    "description": "Unit price",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Units sold, defaults to 1",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.SaleTenderRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payment_method"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "enum": [
+                        "CASH",
+                        "CREDIT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.StoreDirectoryResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StoreProfile"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.StoreReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.StoreSettingsRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "agreement_days": {
+                    "description": "Days an approved item stays on sale, 0 for no expiry",
+                    "type": "integer"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "commission_cash": {
+                    "description": "Percentage, 0-100",
+                    "type": "number"
+                },
+                "commission_credit": {
+                    "description": "Percentage, 0-100",
+                    "type": "number"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "min_settlement_amount": {
+                    "description": "Smallest settlement payout, 0 for none",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "description": "Free text, e.g. \"Mon-Fri 12:00-21:00\"",
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "price_drop_days": {
+                    "description": "Lower listed prices every N days, 0 for never",
+                    "type": "integer"
+                },
+                "price_drop_rate": {
+                    "description": "Percentage taken off at each drop",
+                    "type": "number"
+                },
+                "withdrawal_fee": {
+                    "description": "Optional fee per withdrawn item",
+                    "type": "number"
+                }
+            }
+        },
+        "api.TopUpCreditRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UpdateCardRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "card_number": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rarity": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateConsignmentItemStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "condition": {
+                    "description": "Required when approving",
+                    "enum": [
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CardCondition"
+                        }
+                    ]
+                },
+                "grade_company": {
+                    "description": "Only for third-party graded copies",
+                    "type": "string"
+                },
+                "grade_score": {
+                    "type": "number"
+                },
+                "price": {
+                    "description": "Optional listed price offered to the player",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Units the status applies to, 0 for all; the rest get the opposite decision",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "APPROVED",
+                        "REJECTED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ConsignmentItemStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.UpdateSettlementRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "enum": [
+                        "COMPLETED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SettlementStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.UpdateStaffRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "MANAGER",
+                        "CASHIER",
+                        "INTAKE_CLERK"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StoreRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.VoidTransactionRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Card": {
+            "type": "object",
+            "properties": {
+                "card_number": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Staff member who added the card",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rarity": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "description": "Staff member who last edited it",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CardCondition": {
+            "type": "string",
+            "enum": [
+                "NM",
+                "LP",
+                "MP",
+                "HP",
+                "DMG"
+            ],
+            "x-enum-comments": {
+                "ConditionDamaged": "Damaged",
+                "ConditionHeavilyPlayed": "Heavily Played",
+                "ConditionLightlyPlayed": "Lightly Played",
+                "ConditionModeratelyPlayed": "Moderately Played",
+                "ConditionNearMint": "Near Mint"
+            },
+            "x-enum-descriptions": [
+                "Near Mint",
+                "Lightly Played",
+                "Moderately Played",
+                "Heavily Played",
+                "Damaged"
+            ],
+            "x-enum-varnames": [
+                "ConditionNearMint",
+                "ConditionLightlyPlayed",
+                "ConditionModeratelyPlayed",
+                "ConditionHeavilyPlayed",
+                "ConditionDamaged"
+            ]
+        },
+        "model.CommissionReport": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/model.PaymentMethod"
+                },
+                "sales": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CommissionRule": {
+            "type": "object",
+            "properties": {
+                "commission_cash": {
+                    "type": "number"
+                },
+                "commission_credit": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_price": {
+                    "description": "Exclusive upper bound, none when nil",
+                    "type": "number"
+                },
+                "min_price": {
+                    "description": "Inclusive lower bound on the unit price",
+                    "type": "number"
+                },
+                "rarity": {
+                    "description": "Only cards of this rarity, case-insensitive",
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "Only cards of this series, case-insensitive",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CommissionSchedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CommissionRule"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Consignment": {
+            "type": "object",
+            "properties": {
+                "agreement_days": {
+                    "description": "Days an approved item stays on sale; 0 means no expiry",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Used for API responses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ConsignmentItem"
+                    }
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ConsignmentRequestStatus"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConsignmentItem": {
+            "type": "object",
+            "properties": {
+                "card": {
+                    "description": "Joined card details, used for API responses",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Card"
+                        }
+                    ]
+                },
+                "card_id": {
+                    "type": "integer"
+                },
+                "condition": {
+                    "enum": [
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CardCondition"
+                        }
+                    ]
+                },
+                "consignment_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "End of the agreement period",
+                    "type": "string"
+                },
+                "grade_company": {
+                    "description": "Third-party grader, e.g. PSA, BGS or CGC",
+                    "type": "string"
+                },
+                "grade_score": {
+                    "description": "Grader's score, 1–10 in steps of 0.5",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "listed_at": {
+                    "description": "When the item last went on sale or had a price agreed; automatic price drops count from here",
+                    "type": "string"
+                },
+                "listed_price": {
+                    "description": "Per-unit price agreed through negotiation",
+                    "type": "number"
+                },
+                "min_price": {
+                    "description": "Player's per-unit price floor; sales below it are refused",
+                    "type": "number"
+                },
+                "photos": {
+                    "description": "Intake photos, used for API responses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ConsignmentItemPhoto"
+                    }
+                },
+                "price_drops": {
+                    "description": "Automatic price drops applied since listing",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Copies of the card this item stands for",
+                    "type": "integer"
+                },
+                "rejection_reason": {
+                    "type": "string"
+                },
+                "split_from_id": {
+                    "description": "Item these units were split off by a partial approval or sale",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ConsignmentItemStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConsignmentItemEvent": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "User who caused the change; empty for system changes",
+                    "type": "integer"
+                },
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "description": "Empty for the submission event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ConsignmentItemStatus"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/model.ConsignmentItemStatus"
+                }
+            }
+        },
+        "model.ConsignmentItemPhoto": {
+            "type": "object",
+            "properties": {
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConsignmentItemStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "APPROVED",
+                "REJECTED",
+                "SOLD",
+                "CLEARED",
+                "CANCELLED",
+                "WITHDRAWN",
+                "RETURNED",
+                "EXPIRED"
+            ],
+            "x-enum-comments": {
+                "ItemStatusCancelled": "Player cancelled the item before review",
+                "ItemStatusReturned": "Store handed a withdrawn or expired item back to the player",
+                "ItemStatusWithdrawn": "Player asked for an approved item back; off sale until returned"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "Player cancelled the item before review",
+                "Player asked for an approved item back; off sale until returned",
+                "Store handed a withdrawn or expired item back to the player",
+                ""
+            ],
+            "x-enum-varnames": [
+                "ItemStatusPending",
+                "ItemStatusApproved",
+                "ItemStatusRejected",
+                "ItemStatusSold",
+                "ItemStatusCleared",
+                "ItemStatusCancelled",
+                "ItemStatusWithdrawn",
+                "ItemStatusReturned",
+                "ItemStatusExpired"
+            ]
+        },
+        "model.ConsignmentRequestStatus": {
+            "type": "string",
+            "enum": [
+                "PROCESSING",
+                "COMPLETED",
+                "PARTIALLY_APPROVED",
+                "ALL_REJECTED",
+                "CLOSED"
+            ],
+            "x-enum-comments": {
+                "ConsignmentRequestStatusAllRejected": "Intake finished, every item rejected",
+                "ConsignmentRequestStatusClosed": "Every item is settled or rejected",
+                "ConsignmentRequestStatusCompleted": "Intake finished, every item accepted",
+                "ConsignmentRequestStatusPartiallyApproved": "Intake finished, some items rejected",
+                "ConsignmentRequestStatusProcessing": "Some items still await intake"
+            },
+            "x-enum-descriptions": [
+                "Some items still await intake",
+                "Intake finished, every item accepted",
+                "Intake finished, some items rejected",
+                "Intake finished, every item rejected",
+                "Every item is settled or rejected"
+            ],
+            "x-enum-varnames": [
+                "ConsignmentRequestStatusProcessing",
+                "ConsignmentRequestStatusCompleted",
+                "ConsignmentRequestStatusPartiallyApproved",
+                "ConsignmentRequestStatusAllRejected",
+                "ConsignmentRequestStatusClosed"
+            ]
+        },
+        "model.CreditAccount": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CreditEntry"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreditBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreditEntry": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "User who made the change",
+                    "type": "integer"
+                },
+                "amount": {
+                    "description": "Positive adds credit, negative spends it",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "enum": [
+                        "TOP_UP",
+                        "SPEND",
+                        "REFUND",
+                        "SETTLEMENT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CreditEntryKind"
+                        }
+                    ]
+                },
+                "note": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "description": "Basket paid with credit",
+                    "type": "integer"
+                },
+                "settlement_id": {
+                    "description": "Settlement paid out as credit",
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "description": "Sale, void or refund the entry belongs to",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreditEntryKind": {
+            "type": "string",
+            "enum": [
+                "TOP_UP",
+                "SPEND",
+                "REFUND",
+                "SETTLEMENT"
+            ],
+            "x-enum-comments": {
+                "CreditEntryRefund": "A CREDIT sale was voided or refunded",
+                "CreditEntrySettlement": "A settlement was paid out as store credit",
+                "CreditEntrySpend": "Credit paid for a CREDIT sale",
+                "CreditEntryTopUp": "The store took payment and added credit"
+            },
+            "x-enum-descriptions": [
+                "The store took payment and added credit",
+                "Credit paid for a CREDIT sale",
+                "A CREDIT sale was voided or refunded",
+                "A settlement was paid out as store credit"
+            ],
+            "x-enum-varnames": [
+                "CreditEntryTopUp",
+                "CreditEntrySpend",
+                "CreditEntryRefund",
+                "CreditEntrySettlement"
+            ]
+        },
+        "model.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "CASH",
+                "CREDIT"
+            ],
+            "x-enum-varnames": [
+                "PaymentMethodCash",
+                "PaymentMethodCredit"
+            ]
+        },
+        "model.PlayerLiability": {
+            "type": "object",
+            "properties": {
+                "deductions": {
+                    "description": "Outstanding withdrawal fees and refund adjustments",
+                    "type": "number"
+                },
+                "owed": {
+                    "type": "number"
+                },
+                "pending_payouts": {
+                    "description": "Requested settlements not yet completed",
+                    "type": "number"
+                },
+                "player_email": {
+                    "type": "string"
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "unsettled_sales": {
+                    "type": "number"
+                },
+                "unsettled_share": {
+                    "description": "At the rate recorded at sale time",
+                    "type": "number"
+                }
+            }
+        },
+        "model.PriceParty": {
+            "type": "string",
+            "enum": [
+                "STORE",
+                "PLAYER"
+            ],
+            "x-enum-varnames": [
+                "PricePartyStore",
+                "PricePartyPlayer"
+            ]
+        },
+        "model.PriceProposal": {
+            "type": "object",
+            "properties": {
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "proposed_by": {
+                    "$ref": "#/definitions/model.PriceParty"
+                },
+                "proposer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PriceProposalStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PriceProposalStatus": {
+            "type": "string",
+            "enum": [
+                "OPEN",
+                "ACCEPTED",
+                "SUPERSEDED"
+            ],
+            "x-enum-comments": {
+                "ProposalStatusAccepted": "Became the item's listed price",
+                "ProposalStatusOpen": "Waiting for the other side to accept or counter",
+                "ProposalStatusSuperseded": "Replaced by a newer proposal or counter-offer"
+            },
+            "x-enum-descriptions": [
+                "Waiting for the other side to accept or counter",
+                "Became the item's listed price",
+                "Replaced by a newer proposal or counter-offer"
             ],
+            "x-enum-varnames": [
+                "ProposalStatusOpen",
+                "ProposalStatusAccepted",
+                "ProposalStatusSuperseded"
+            ]
+        },
+        "model.Sale": {
+            "type": "object",
             "properties": {
-                "card_ids": {
+                "buyer_id": {
+                    "description": "Store credit account charged for the CREDIT tenders",
+                    "type": "integer"
+                },
+                "cashier_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "Used for API responses",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/model.Transaction"
                     }
                 },
+                "receipt_number": {
+                    "type": "string"
+                },
                 "store_id": {
                     "type": "integer"
+                },
+                "tenders": {
+                    "description": "Used for API responses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SaleTender"
+                    }
+                },
+                "total": {
+                    "type": "number"
                 }
             }
         },
-        "api.CreateSettlementRequest": {
+        "model.SaleEarning": {
             "type": "object",
-            "required": [
-                "store_id"
-            ],
             "properties": {
+                "card_name": {
+                    "type": "string"
+                },
+                "commission_amount": {
+                    "type": "number"
+                },
+                "commission_rate": {
+                    "type": "number"
+                },
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "gross_amount": {
+                    "type": "number"
+                },
+                "net_amount": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/model.PaymentMethod"
+                },
+                "price": {
+                    "description": "Unit price",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "settlement_id": {
+                    "description": "Set once the sale has been settled",
+                    "type": "integer"
+                },
+                "sold_at": {
+                    "type": "string"
+                },
                 "store_id": {
                     "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api.CreateTransactionRequest": {
+        "model.SaleTender": {
             "type": "object",
-            "required": [
-                "consignment_item_id",
-                "payment_method",
-                "price"
-            ],
             "properties": {
-                "consignment_item_id": {
+                "amount": {
+                    "type": "number"
+                },
+                "id": {
                     "type": "integer"
                 },
                 "payment_method": {
-                    "enum": [
-                        "CASH",
-                        "CREDIT"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.PaymentMethod"
-                        }
-                    ]
+                    "$ref": "#/definitions/model.PaymentMethod"
                 },
-                "price": {
-                    "type": "number"
+                "sale_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api.LoginRequest": {
+        "model.SalesPeriodReport": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "commission": {
+                    "description": "Kept by the store",
+                    "type": "number"
                 },
-                "password": {
+                "gross": {
+                    "type": "number"
+                },
+                "period_start": {
                     "type": "string"
+                },
+                "player_share": {
+                    "description": "Owed or paid to the players",
+                    "type": "number"
+                },
+                "refunds": {
+                    "description": "Number of refunds",
+                    "type": "integer"
+                },
+                "sales": {
+                    "description": "Number of sales",
+                    "type": "integer"
+                },
+                "units": {
+                    "description": "Units sold less units refunded",
+                    "type": "integer"
                 }
             }
         },
-        "api.RegisterRequest": {
+        "model.Settlement": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "role"
-            ],
             "properties": {
-                "email": {
+                "adjustments_deducted": {
+                    "description": "Shares of refunded sales taken back from this payout",
+                    "type": "number"
+                },
+                "amount": {
+                    "description": "Net payout after fees",
+                    "type": "number"
+                },
+                "commission_amount": {
+                    "description": "Store's commission at CommissionRate, or at the schedule rule a sale was charged under",
+                    "type": "number"
+                },
+                "commission_rate": {
+                    "description": "Store's base rate for the payout method when requested",
+                    "type": "number"
+                },
+                "completed_at": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "completed_by": {
+                    "description": "Staff member who paid it out; none for credit payouts",
+                    "type": "integer"
                 },
-                "role": {
-                    "description": "ADMIN role cannot be self-assigned",
-                    "type": "string",
-                    "enum": [
-                        "PLAYER",
-                        "STORE"
+                "created_at": {
+                    "type": "string"
+                },
+                "fees_deducted": {
+                    "description": "Withdrawal fees taken out of this payout",
+                    "type": "number"
+                },
+                "gross_amount": {
+                    "description": "Total of the sales settled",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "net_amount": {
+                    "description": "Player's share: gross less commission, before deductions",
+                    "type": "number"
+                },
+                "payout_method": {
+                    "description": "How the player is paid: CASH, or into their store credit",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
                     ]
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "requested_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.SettlementStatus"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "api.UpdateCardRequest": {
+        "model.SettlementDetail": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "card_number": {
+                "adjustments_deducted": {
+                    "description": "Shares of refunded sales taken back from this payout",
+                    "type": "number"
+                },
+                "amount": {
+                    "description": "Net payout after fees",
+                    "type": "number"
+                },
+                "commission_amount": {
+                    "description": "Store's commission at CommissionRate, or at the schedule rule a sale was charged under",
+                    "type": "number"
+                },
+                "commission_rate": {
+                    "description": "Store's base rate for the payout method when requested",
+                    "type": "number"
+                },
+                "completed_at": {
                     "type": "string"
                 },
-                "name": {
+                "completed_by": {
+                    "description": "Staff member who paid it out; none for credit payouts",
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "rarity": {
+                "fees_deducted": {
+                    "description": "Withdrawal fees taken out of this payout",
+                    "type": "number"
+                },
+                "gross_amount": {
+                    "description": "Total of the sales settled",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SettlementLine"
+                    }
+                },
+                "net_amount": {
+                    "description": "Player's share: gross less commission, before deductions",
+                    "type": "number"
+                },
+                "payout_method": {
+                    "description": "How the player is paid: CASH, or into their store credit",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ]
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "requested_at": {
                     "type": "string"
                 },
-                "series": {
+                "status": {
+                    "$ref": "#/definitions/model.SettlementStatus"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "api.UpdateConsignmentItemStatusRequest": {
+        "model.SettlementLine": {
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
-                "reason": {
+                "commission_amount": {
+                    "type": "number"
+                },
+                "consignment_item_id": {
+                    "type": "integer"
+                },
+                "gross_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "net_amount": {
+                    "description": "Player's share of the sale",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "Unit price of the sale",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "settlement_id": {
+                    "type": "integer"
+                },
+                "sold_at": {
                     "type": "string"
                 },
-                "status": {
-                    "enum": [
-                        "APPROVED",
-                        "REJECTED"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.ConsignmentItemStatus"
-                        }
-                    ]
+                "transaction_id": {
+                    "type": "integer"
                 }
             }
         },
-        "model.Card": {
+        "model.SettlementStatus": {
+            "type": "string",
+            "enum": [
+                "REQUESTED",
+                "COMPLETED"
+            ],
+            "x-enum-varnames": [
+                "StatusRequested",
+                "StatusCompleted"
+            ]
+        },
+        "model.Store": {
             "type": "object",
             "properties": {
-                "card_number": {
+                "address": {
+                    "type": "string"
+                },
+                "agreement_days": {
+                    "description": "Days an approved item stays on sale, 0 for no expiry",
+                    "type": "integer"
+                },
+                "city": {
                     "type": "string"
                 },
+                "commission_cash": {
+                    "type": "number"
+                },
+                "commission_credit": {
+                    "type": "number"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
+                "min_settlement_amount": {
+                    "description": "Smallest player share a settlement may pay out, 0 for none",
+                    "type": "number"
                 },
                 "name": {
                     "type": "string"
                 },
-                "rarity": {
+                "opening_hours": {
+                    "description": "Free text, e.g. \"Mon-Fri 12:00-21:00\"",
                     "type": "string"
                 },
-                "series": {
+                "phone": {
                     "type": "string"
                 },
-                "store_id": {
+                "price_drop_days": {
+                    "description": "Lower the listed price every N days on sale, 0 for never",
+                    "type": "integer"
+                },
+                "price_drop_rate": {
+                    "description": "Percentage taken off the listed price at each drop",
+                    "type": "number"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "description": "Admin who last changed the status",
                     "type": "integer"
                 },
+                "status": {
+                    "$ref": "#/definitions/model.StoreStatus"
+                },
+                "status_reason": {
+                    "description": "Why the store was rejected or suspended",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "withdrawal_fee": {
+                    "description": "Charged per withdrawn item, 0 for none",
+                    "type": "number"
                 }
             }
         },
-        "model.Consignment": {
+        "model.StoreEarnings": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "listed_units": {
+                    "description": "Units on sale",
                     "type": "integer"
                 },
-                "items": {
-                    "description": "Used for API responses",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.ConsignmentItem"
-                    }
+                "listed_value": {
+                    "description": "Units on sale at their listed price; units without one count as 0",
+                    "type": "number"
                 },
-                "player_id": {
+                "paid_out": {
+                    "description": "Paid out by settlements, after fees and adjustments",
+                    "type": "number"
+                },
+                "pending_units": {
+                    "description": "Units awaiting the store's intake review",
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.ConsignmentRequestStatus"
+                "settled_share": {
+                    "description": "Player's share of the settled sales",
+                    "type": "number"
                 },
                 "store_id": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "store_name": {
                     "type": "string"
+                },
+                "unsettled_sales": {
+                    "description": "Total of the sales not yet settled",
+                    "type": "number"
+                },
+                "unsettled_share": {
+                    "description": "Player's share of those sales at the rate recorded at sale time",
+                    "type": "number"
                 }
             }
         },
-        "model.ConsignmentItem": {
+        "model.StoreMember": {
             "type": "object",
             "properties": {
-                "card_id": {
-                    "type": "integer"
-                },
-                "consignment_id": {
-                    "type": "integer"
+                "accepted_at": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "email": {
+                    "description": "Joined from the user, used for API responses",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "rejection_reason": {
-                    "type": "string"
+                "invited_by": {
+                    "type": "integer"
+                },
+                "role": {
+                    "enum": [
+                        "OWNER",
+                        "MANAGER",
+                        "CASHIER",
+                        "INTAKE_CLERK"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StoreRole"
+                        }
+                    ]
                 },
                 "status": {
-                    "$ref": "#/definitions/model.ConsignmentItemStatus"
+                    "enum": [
+                        "INVITED",
+                        "ACTIVE",
+                        "DECLINED",
+                        "REMOVED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StoreMemberStatus"
+                        }
+                    ]
+                },
+                "store_id": {
+                    "type": "integer"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "model.ConsignmentItemStatus": {
+        "model.StoreMemberStatus": {
             "type": "string",
             "enum": [
-                "PENDING",
-                "APPROVED",
-                "REJECTED",
-                "SOLD",
-                "CLEARED"
+                "INVITED",
+                "ACTIVE",
+                "DECLINED",
+                "REMOVED"
+            ],
+            "x-enum-comments": {
+                "MemberStatusInvited": "Waiting for the user to accept",
+                "MemberStatusRemoved": "Taken off the staff by the owner, or left"
+            },
+            "x-enum-descriptions": [
+                "Waiting for the user to accept",
+                "",
+                "",
+                "Taken off the staff by the owner, or left"
             ],
             "x-enum-varnames": [
-                "ItemStatusPending",
-                "ItemStatusApproved",
-                "ItemStatusRejected",
-                "ItemStatusSold",
-                "ItemStatusCleared"
+                "MemberStatusInvited",
+                "MemberStatusActive",
+                "MemberStatusDeclined",
+                "MemberStatusRemoved"
             ]
         },
-        "model.ConsignmentRequestStatus": {
+        "model.StoreProfile": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "agreement_days": {
+                    "type": "integer"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "commission_cash": {
+                    "type": "number"
+                },
+                "commission_credit": {
+                    "type": "number"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_settlement_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "price_drop_days": {
+                    "type": "integer"
+                },
+                "price_drop_rate": {
+                    "type": "number"
+                },
+                "withdrawal_fee": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.StoreRole": {
             "type": "string",
             "enum": [
-                "PROCESSING",
-                "COMPLETED"
+                "OWNER",
+                "MANAGER",
+                "CASHIER",
+                "INTAKE_CLERK"
+            ],
+            "x-enum-comments": {
+                "StoreRoleOwner": "The user who registered the store"
+            },
+            "x-enum-descriptions": [
+                "The user who registered the store",
+                "",
+                "",
+                ""
             ],
             "x-enum-varnames": [
-                "ConsignmentRequestStatusProcessing",
-                "ConsignmentRequestStatusCompleted"
+                "StoreRoleOwner",
+                "StoreRoleManager",
+                "StoreRoleCashier",
+                "StoreRoleIntakeClerk"
             ]
         },
-        "model.PaymentMethod": {
+        "model.StoreStatus": {
             "type": "string",
             "enum": [
-                "CASH",
-                "CREDIT"
+                "PENDING_REVIEW",
+                "ACTIVE",
+                "INACTIVE",
+                "REJECTED",
+                "SUSPENDED"
+            ],
+            "x-enum-comments": {
+                "StoreStatusPendingReview": "Registered, waiting for an admin"
+            },
+            "x-enum-descriptions": [
+                "Registered, waiting for an admin",
+                "",
+                "",
+                "",
+                ""
             ],
             "x-enum-varnames": [
-                "PaymentMethodCash",
-                "PaymentMethodCredit"
+                "StoreStatusPendingReview",
+                "StoreStatusActive",
+                "StoreStatusInactive",
+                "StoreStatusRejected",
+                "StoreStatusSuspended"
             ]
         },
-        "model.Settlement": {
+        "model.TopSeller": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "card_id": {
+                    "description": "Not set when ranking series",
+                    "type": "integer"
                 },
-                "created_at": {
+                "card_name": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "gross": {
+                    "type": "number"
                 },
-                "player_id": {
+                "sales": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.SettlementStatus"
+                "series": {
+                    "type": "string"
                 },
-                "store_id": {
+                "units": {
                     "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "model.SettlementStatus": {
-            "type": "string",
-            "enum": [
-                "REQUESTED",
-                "COMPLETED"
-            ],
-            "x-enum-varnames": [
-                "StatusRequested",
-                "StatusCompleted"
-            ]
-        },
         "model.Transaction": {
             "type": "object",
             "properties": {
+                "actor_id": {
+                    "description": "User who rang up the sale or the refund",
+                    "type": "integer"
+                },
+                "buyer_id": {
+                    "description": "Store credit account charged, for CREDIT sales",
+                    "type": "integer"
+                },
                 "commission_rate": {
                     "type": "number"
                 },
+                "commission_rule_id": {
+                    "description": "Schedule rule the rate came from; nil for the store's base rate",
+                    "type": "integer"
+                },
+                "condition": {
+                    "enum": [
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CardCondition"
+                        }
+                    ]
+                },
                 "consignment_item_id": {
                     "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "grade_company": {
+                    "description": "Third-party grader, e.g. PSA, BGS or CGC",
+                    "type": "string"
+                },
+                "grade_score": {
+                    "description": "Grader's score, 1–10 in steps of 0.5",
+                    "type": "number"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1188,13 +6769,79 @@ const docTemplate = `{
                     "$ref": "#/definitions/model.PaymentMethod"
                 },
                 "price": {
+                    "description": "Unit price",
                     "type": "number"
                 },
+                "quantity": {
+                    "description": "Negative for refunds",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Why a refund was given",
+                    "type": "string"
+                },
+                "refund_id": {
+                    "description": "For refunded sales, the reversing transaction",
+                    "type": "integer"
+                },
+                "reverses_id": {
+                    "description": "For refunds, the sale being reversed",
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "description": "The basket the line was sold in, if any",
+                    "type": "integer"
+                },
                 "store_id": {
                     "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "SALE",
+                        "REFUND"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TransactionType"
+                        }
+                    ]
+                },
+                "void_reason": {
+                    "type": "string"
+                },
+                "voided_at": {
+                    "type": "string"
+                },
+                "voided_by": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Not persisted; set on the sale response, e.g. when sold below the listed price",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
+        "model.TransactionType": {
+            "type": "string",
+            "enum": [
+                "SALE",
+                "REFUND"
+            ],
+            "x-enum-comments": {
+                "TransactionTypeRefund": "Reverses a sale: same unit price, negative quantity"
+            },
+            "x-enum-descriptions": [
+                "",
+                "Reverses a sale: same unit price, negative quantity"
+            ],
+            "x-enum-varnames": [
+                "TransactionTypeSale",
+                "TransactionTypeRefund"
+            ]
+        },
         "model.User": {
             "type": "object",
             "properties": {
@@ -1214,6 +6861,44 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "service.ItemReviewResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Why this decision was refused",
+                    "type": "string"
+                },
+                "item": {
+                    "description": "The reviewed item, set once the batch is applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ConsignmentItem"
+                        }
+                    ]
+                },
+                "item_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "Seconds until the access token expires",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -14,8 +14,9 @@ const (
 	AuthorizationPayloadKey = "authorization_payload"
 )
 
-// AuthMiddleware creates a gin middleware for authorization
-func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
+// AuthMiddleware creates a gin middleware for authorization. Besides validating the access token it
+// checks that the session the token was issued for has not been logged out or revoked.
+func AuthMiddleware(jwtService *service.JWTService, sessionService *service.SessionService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authorizationHeader := c.GetHeader(AuthorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -42,6 +43,15 @@ func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		if err := sessionService.CheckSession(payload.SessionID); err != nil {
+			if err == service.ErrSessionRevoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check session"})
+			return
+		}
+
 		c.Set(AuthorizationPayloadKey, payload)
 		c.Next()
 	}
@@ -19,7 +19,7 @@ func NewCardHandler(cardService *service.CardService) *CardHandler {
 }
 
 // @Summary Create a new card
-// @Description Adds a new card to the store associated with the user, with an optional image upload.
+// @Description Adds a new card to the store associated with the user, with an optional JPEG, PNG or WebP image upload.
 // @Tags cards
 // @Accept  mpfd
 // @Produce  json
@@ -30,7 +30,7 @@ func NewCardHandler(cardService *service.CardService) *CardHandler {
 // @Param   card_number formData string false "Card Number"
 // @Param   image formData file false "Card Image"
 // @Success 201 {object} model.Card
-// @Failure 400 {object} map[string]string "{"error": "bad_request_error"}"
+// @Failure 400 {object} map[string]string "{"error": "image must be a JPEG, PNG or WebP file"}"
 // @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
 // @Failure 500 {object} map[string]string "{"error": "failed to create card"}"
 // @Router /api/cards [post]
@@ -62,6 +62,14 @@ func (h *CardHandler) CreateCard(c *gin.Context) {
 
 	card, err := h.cardService.CreateCard(claims.UserID, name, series, rarity, cardNumber, imageContent, imageExtension)
 	if err != nil {
+		if err == service.ErrInvalidImage {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err == service.ErrStoreNotFound {
 			c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
 			return
@@ -100,6 +108,10 @@ func (h *CardHandler) GetCard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "card not found"})
 			return
 		}
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err == service.ErrForbidden {
 			c.JSON(http.StatusForbidden, gin.H{"error": "you do not have permission to view this card"})
 			return
@@ -173,6 +185,10 @@ func (h *CardHandler) UpdateCard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "card not found"})
 			return
 		}
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err == service.ErrForbidden {
 			c.JSON(http.StatusForbidden, gin.H{"error": "you do not have permission to update this card"})
 			return
@@ -211,6 +227,10 @@ func (h *CardHandler) DeleteCard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "card not found"})
 			return
 		}
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err == service.ErrForbidden {
 			c.JSON(http.StatusForbidden, gin.H{"error": "you do not have permission to delete this card"})
 			return
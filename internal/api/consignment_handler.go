@@ -2,9 +2,13 @@ package api
 
 import (
 	"card_manage/internal/model"
+	"card_manage/internal/repository"
 	"card_manage/internal/service"
+	"errors"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,12 +22,38 @@ func NewConsignmentHandler(consignmentService *service.ConsignmentService) *Cons
 }
 
 type CreateConsignmentRequest struct {
-	StoreID int64   `json:"store_id" binding:"required"`
-	CardIDs []int64 `json:"card_ids" binding:"required,gt=0"`
+	StoreID int64                    `json:"store_id" binding:"required"`
+	CardIDs []int64                  `json:"card_ids"`                       // Cards consigned without a minimum price
+	Items   []ConsignmentItemRequest `json:"items" binding:"omitempty,dive"` // Cards with a quantity and an optional minimum price
+}
+
+// ConsignmentItemRequest is one card submitted for consignment.
+type ConsignmentItemRequest struct {
+	CardID   int64       `json:"card_id" binding:"required"`
+	Quantity int         `json:"quantity" binding:"min=0"`       // Copies of the card, defaults to 1
+	MinPrice model.Money `json:"min_price" swaggertype:"number"` // Optional per-unit floor; the store cannot sell below it
+}
+
+// itemInputs merges the card_ids shorthand and the detailed items into one list.
+func (r *CreateConsignmentRequest) itemInputs() []service.ConsignmentItemInput {
+	inputs := make([]service.ConsignmentItemInput, 0, len(r.CardIDs)+len(r.Items))
+	for _, cardID := range r.CardIDs {
+		inputs = append(inputs, service.ConsignmentItemInput{CardID: cardID})
+	}
+	for _, item := range r.Items {
+		inputs = append(inputs, service.ConsignmentItemInput{CardID: item.CardID, Quantity: item.Quantity, MinPrice: item.MinPrice})
+	}
+	return inputs
+}
+
+// InvalidCardsResponse is returned when some of the submitted cards cannot be consigned to the store.
+type InvalidCardsResponse struct {
+	Error          string  `json:"error"`
+	InvalidCardIDs []int64 `json:"invalid_card_ids"`
 }
 
 // @Summary Create a new consignment request
-// @Description Player creates a consignment request for one or more cards to a store.
+// @Description Player creates a consignment request for one or more cards to a store. Cards can be given as plain card_ids or as items with a quantity and an optional minimum price.
 // @Tags consignments
 // @Accept  json
 // @Produce  json
@@ -31,6 +61,9 @@ type CreateConsignmentRequest struct {
 // @Param   consignment body CreateConsignmentRequest true "Consignment Request Information"
 // @Success 201 {object} model.Consignment
 // @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 409 {object} map[string]string "{"error": "store is not accepting consignments"}"
+// @Failure 422 {object} InvalidCardsResponse
 // @Failure 500 {object} map[string]string "{"error": "failed to create consignment request"}"
 // @Router /api/consignments [post]
 func (h *ConsignmentHandler) CreateConsignment(c *gin.Context) {
@@ -42,22 +75,165 @@ func (h *ConsignmentHandler) CreateConsignment(c *gin.Context) {
 
 	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
 
-	consignment, err := h.consignmentService.CreateConsignment(claims.UserID, req.StoreID, req.CardIDs)
+	consignment, err := h.consignmentService.CreateConsignment(claims.UserID, req.StoreID, req.itemInputs())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create consignment request"})
+		var invalidCards *service.InvalidCardsError
+		switch {
+		case errors.As(err, &invalidCards):
+			c.JSON(http.StatusUnprocessableEntity, InvalidCardsResponse{
+				Error:          service.ErrInvalidCardForStore.Error(),
+				InvalidCardIDs: invalidCards.CardIDs,
+			})
+		case errors.Is(err, service.ErrEmptyConsignment), errors.Is(err, service.ErrInvalidPrice), errors.Is(err, service.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrTargetStoreNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "store not found"})
+		case errors.Is(err, service.ErrStoreNotActive):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create consignment request"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, consignment)
 }
 
+// ConsignmentListResponse is one page of consignments.
+type ConsignmentListResponse struct {
+	Consignments []model.Consignment `json:"consignments"`
+	Total        int                 `json:"total"`
+	Page         int                 `json:"page"`
+	PageSize     int                 `json:"page_size"`
+}
+
+// @Summary List consignments
+// @Description Players see their own consignments; stores see the consignments addressed to their store. Each consignment includes its items with card details.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   status query string false "Filter by request status" Enums(PROCESSING, COMPLETED, PARTIALLY_APPROVED, ALL_REJECTED, CLOSED)
+// @Param   item_status query string false "Only consignments with at least one item in this status" Enums(PENDING, APPROVED, REJECTED, SOLD, CLEARED, CANCELLED, WITHDRAWN, RETURNED, EXPIRED)
+// @Param   from query string false "Created on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Created on or before this date (YYYY-MM-DD)"
+// @Param   page query int false "Page number, starting at 1" default(1)
+// @Param   page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} ConsignmentListResponse
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to list consignments"}"
+// @Router /api/consignments [get]
+func (h *ConsignmentHandler) ListConsignments(c *gin.Context) {
+	filter := repository.ConsignmentFilter{
+		Status:     model.ConsignmentRequestStatus(c.Query("status")),
+		ItemStatus: model.ConsignmentItemStatus(c.Query("item_status")),
+	}
+
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.From, filter.To = from, to
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Limit, filter.Offset = pageSize, (page-1)*pageSize
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	var consignments []model.Consignment
+	var total int
+	if claims.Role == "STORE" {
+		consignments, total, err = h.consignmentService.ListStoreConsignments(claims.UserID, filter)
+	} else {
+		consignments, total, err = h.consignmentService.ListPlayerConsignments(claims.UserID, filter)
+	}
+	if err != nil {
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list consignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ConsignmentListResponse{
+		Consignments: consignments,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+	})
+}
+
+// @Summary Get a consignment by ID
+// @Description Retrieves a consignment with its items and card details. Only the submitting player and the receiving store may view it.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Consignment ID"
+// @Success 200 {object} model.Consignment
+// @Failure 400 {object} map[string]string "{"error": "invalid consignment ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "consignment not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to retrieve consignment"}"
+// @Router /api/consignments/{id} [get]
+func (h *ConsignmentHandler) GetConsignment(c *gin.Context) {
+	consignmentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid consignment ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	consignment, err := h.consignmentService.GetConsignment(claims.UserID, consignmentID)
+	if err != nil {
+		switch err {
+		case service.ErrConsignmentNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "consignment not found"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve consignment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, consignment)
+}
+
 type UpdateConsignmentItemStatusRequest struct {
-	Status model.ConsignmentItemStatus `json:"status" binding:"required,oneof=APPROVED REJECTED"`
-	Reason string                      `json:"reason"`
+	Status       model.ConsignmentItemStatus `json:"status" binding:"required,oneof=APPROVED REJECTED"`
+	Reason       string                      `json:"reason"`
+	Condition    model.CardCondition         `json:"condition" enums:"NM,LP,MP,HP,DMG"` // Required when approving
+	GradeCompany string                      `json:"grade_company"`                     // Only for third-party graded copies
+	GradeScore   *float64                    `json:"grade_score"`
+	Price        model.Money                 `json:"price" swaggertype:"number"` // Optional listed price offered to the player
+	Quantity     int                         `json:"quantity" binding:"min=0"`   // Units the status applies to, 0 for all; the rest get the opposite decision
+}
+
+func (r *UpdateConsignmentItemStatusRequest) review() service.ItemReview {
+	return service.ItemReview{
+		Status:   r.Status,
+		Reason:   r.Reason,
+		Grade:    model.ConditionGrade{Condition: r.Condition, GradeCompany: r.GradeCompany, GradeScore: r.GradeScore},
+		Price:    r.Price,
+		Quantity: r.Quantity,
+	}
 }
 
 // @Summary Update a consignment item's status
-// @Description Store approves or rejects a consignment item.
+// @Description Store approves or rejects a consignment item. Approving records the copy's condition (NM/LP/MP/HP/DMG), plus the grade company and score for graded copies. With a quantity below the item's, only that many units get the status and the rest are split off into a new item with the opposite decision (e.g. approve 10 of 12, reject 2). A price is sent to the player as the store's listed price proposal.
 // @Tags consignments
 // @Accept  json
 // @Produce  json
@@ -85,20 +261,482 @@ func (h *ConsignmentHandler) UpdateConsignmentItemStatus(c *gin.Context) {
 
 	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
 
-	item, err := h.consignmentService.UpdateConsignmentItemStatus(claims.UserID, itemID, req.Status, req.Reason)
+	item, err := h.consignmentService.UpdateConsignmentItemStatus(claims.UserID, itemID, req.review())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCondition), errors.Is(err, service.ErrInvalidQuantity), errors.Is(err, service.ErrInvalidPrice):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrConsignmentItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case errors.Is(err, service.ErrStoreNotActive), errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		case errors.Is(err, service.ErrCannotUpdateStatus):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update item status"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// ReviewItemsRequest carries a store's decisions on many consignment items.
+type ReviewItemsRequest struct {
+	Decisions []ItemDecisionRequest `json:"decisions" binding:"required,min=1,max=200,dive"`
+}
+
+// ItemDecisionRequest is the decision on one item of a batch review.
+type ItemDecisionRequest struct {
+	ItemID int64 `json:"item_id" binding:"required"`
+	UpdateConsignmentItemStatusRequest
+}
+
+// ReviewItemsResponse lists the outcome of every decision of a batch review, in request order.
+type ReviewItemsResponse struct {
+	Error   string                     `json:"error,omitempty"`
+	Results []service.ItemReviewResult `json:"results"`
+}
+
+// @Summary Review many consignment items at once
+// @Description Store approves or rejects many items in one request, each decision taking the same fields as the single-item update. All decisions are applied in one database transaction: if any is refused, none is applied and the refused decisions carry an error.
+// @Tags consignments
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   decisions body ReviewItemsRequest true "Item Decisions"
+// @Success 200 {object} ReviewItemsResponse
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 422 {object} ReviewItemsResponse
+// @Failure 500 {object} map[string]string "{"error": "failed to review items"}"
+// @Router /api/consignments/items/review [post]
+func (h *ConsignmentHandler) ReviewItems(c *gin.Context) {
+	var req ReviewItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	decisions := make([]service.ItemDecision, len(req.Decisions))
+	for i, decision := range req.Decisions {
+		decisions[i] = service.ItemDecision{ItemID: decision.ItemID, ItemReview: decision.review()}
+	}
+
+	results, err := h.consignmentService.ReviewItems(claims.UserID, decisions)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBatchReviewRefused):
+			c.JSON(http.StatusUnprocessableEntity, ReviewItemsResponse{Error: service.ErrBatchReviewRefused.Error(), Results: results})
+		case errors.Is(err, service.ErrEmptyBatch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review items"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ReviewItemsResponse{Results: results})
+}
+
+// @Summary Get a consignment item's status history
+// @Description Lists every status change of a consignment item, oldest first, with the acting user and reason. Only the submitting player and the receiving store may view it.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {array} model.ConsignmentItemEvent
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to retrieve item history"}"
+// @Router /api/consignments/items/{itemId}/history [get]
+func (h *ConsignmentHandler) GetConsignmentItemHistory(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	events, err := h.consignmentService.GetConsignmentItemHistory(claims.UserID, itemID)
 	if err != nil {
 		switch err {
-		case service.ErrConsignmentItemNotFound:
+		case service.ErrConsignmentItemNotFound, service.ErrConsignmentNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		case service.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
-		case service.ErrCannotUpdateStatus:
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve item history"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary Upload an intake photo of a consignment item
+// @Description Store attaches a JPEG, PNG or WebP photo of the physical copy while the item is under review or on sale.
+// @Tags consignments
+// @Accept  mpfd
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Param   image formData file true "Photo"
+// @Success 201 {object} model.ConsignmentItemPhoto
+// @Failure 400 {object} map[string]string "{"error": "image must be a JPEG, PNG or WebP file"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "photos can only be added to items under review or on sale"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to upload photo"}"
+// @Router /api/consignments/items/{itemId}/photos [post]
+func (h *ConsignmentHandler) AddItemPhoto(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "image is a required field"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to process image file"})
+		return
+	}
+	defer file.Close()
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	photo, err := h.consignmentService.AddItemPhoto(claims.UserID, itemID, file, filepath.Ext(header.Filename))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidImage:
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrConsignmentItemNotFound, service.ErrConsignmentNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		case service.ErrPhotoNotAllowed:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update item status"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload photo"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// @Summary List the intake photos of a consignment item
+// @Description Lists the photos the store took of the item, oldest first. Only the submitting player and the receiving store may view them.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {array} model.ConsignmentItemPhoto
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to retrieve item photos"}"
+// @Router /api/consignments/items/{itemId}/photos [get]
+func (h *ConsignmentHandler) ListItemPhotos(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	photos, err := h.consignmentService.ListItemPhotos(claims.UserID, itemID)
+	if err != nil {
+		switch err {
+		case service.ErrConsignmentItemNotFound, service.ErrConsignmentNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve item photos"})
 		}
 		return
 	}
 
+	c.JSON(http.StatusOK, photos)
+}
+
+// @Summary Cancel a pending consignment item
+// @Description Player cancels one of their items before the store has reviewed it.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {object} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "status cannot be updated to the desired value: ..."}"
+// @Failure 500 {object} map[string]string "{"error": "failed to cancel item"}"
+// @Router /api/consignments/items/{itemId}/cancel [post]
+func (h *ConsignmentHandler) CancelConsignmentItem(c *gin.Context) {
+	h.handleItemAction(c, h.consignmentService.CancelConsignmentItem, "failed to cancel item")
+}
+
+// @Summary Request withdrawal of an approved item
+// @Description Player takes an approved item off sale and asks the store to hand it back. The item stays WITHDRAWN until the store confirms the return.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {object} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "status cannot be updated to the desired value: ..."}"
+// @Failure 500 {object} map[string]string "{"error": "failed to withdraw item"}"
+// @Router /api/consignments/items/{itemId}/withdraw [post]
+func (h *ConsignmentHandler) WithdrawConsignmentItem(c *gin.Context) {
+	h.handleItemAction(c, h.consignmentService.RequestItemWithdrawal, "failed to withdraw item")
+}
+
+// @Summary Confirm a withdrawn or expired item was returned
+// @Description Store confirms that a withdrawn or expired card was handed back to the player. For withdrawals, the store's withdrawal fee, if any, is deducted from the player's next settlement.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {object} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "status cannot be updated to the desired value: ..."}"
+// @Failure 500 {object} map[string]string "{"error": "failed to confirm return"}"
+// @Router /api/consignments/items/{itemId}/return [post]
+func (h *ConsignmentHandler) ConfirmItemReturn(c *gin.Context) {
+	h.handleItemAction(c, h.consignmentService.ConfirmItemReturn, "failed to confirm return")
+}
+
+// @Summary Re-list an expired item
+// @Description Player puts an expired item back on sale for a new agreement period.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {object} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "status cannot be updated to the desired value: ..."}"
+// @Failure 500 {object} map[string]string "{"error": "failed to re-list item"}"
+// @Router /api/consignments/items/{itemId}/relist [post]
+func (h *ConsignmentHandler) RelistItem(c *gin.Context) {
+	h.handleItemAction(c, h.consignmentService.RelistItem, "failed to re-list item")
+}
+
+// handleItemAction runs a single-item status action for the authenticated user and maps its errors.
+func (h *ConsignmentHandler) handleItemAction(c *gin.Context, action func(userID, itemID int64) (*model.ConsignmentItem, error), failureMessage string) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	item, err := action(claims.UserID, itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrConsignmentItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case errors.Is(err, service.ErrStoreNotActive), errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		case errors.Is(err, service.ErrCannotUpdateStatus):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": failureMessage})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// ProposePriceRequest is a price offer for a consignment item.
+type ProposePriceRequest struct {
+	Price model.Money `json:"price" swaggertype:"number" binding:"required,gt=0"`
+}
+
+// @Summary Propose or counter a price
+// @Description The store proposes a listed price for an item; the player can counter an open store proposal with their own price. A new proposal replaces the open one.
+// @Tags consignments
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Param   proposal body ProposePriceRequest true "Proposed price"
+// @Success 201 {object} model.PriceProposal
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "there is no open price proposal from the other party"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to propose price"}"
+// @Router /api/consignments/items/{itemId}/price-proposals [post]
+func (h *ConsignmentHandler) ProposePrice(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	var req ProposePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	proposal, err := h.consignmentService.ProposePrice(claims.UserID, itemID, req.Price)
+	if err != nil {
+		respondPriceError(c, err, "failed to propose price")
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// @Summary Accept the open price proposal
+// @Description Accepts the other party's open price proposal; its price becomes the item's listed price.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {object} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "there is no open price proposal from the other party"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to accept price"}"
+// @Router /api/consignments/items/{itemId}/price-proposals/accept [post]
+func (h *ConsignmentHandler) AcceptPriceProposal(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	item, err := h.consignmentService.AcceptPriceProposal(claims.UserID, itemID)
+	if err != nil {
+		respondPriceError(c, err, "failed to accept price")
+		return
+	}
+
 	c.JSON(http.StatusOK, item)
 }
+
+// @Summary List an item's price proposals
+// @Description Lists the price negotiation of a consignment item, oldest first.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   itemId path int true "Consignment Item ID"
+// @Success 200 {array} model.PriceProposal
+// @Failure 400 {object} map[string]string "{"error": "invalid item ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "item not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to retrieve price proposals"}"
+// @Router /api/consignments/items/{itemId}/price-proposals [get]
+func (h *ConsignmentHandler) ListPriceProposals(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	proposals, err := h.consignmentService.ListPriceProposals(claims.UserID, itemID)
+	if err != nil {
+		respondPriceError(c, err, "failed to retrieve price proposals")
+		return
+	}
+
+	c.JSON(http.StatusOK, proposals)
+}
+
+func respondPriceError(c *gin.Context, err error, failureMessage string) {
+	switch err {
+	case service.ErrConsignmentItemNotFound, service.ErrConsignmentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+	case service.ErrStoreNotActive, service.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case service.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+	case service.ErrInvalidPrice:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrPriceNotNegotiable, service.ErrNoOpenProposal:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failureMessage})
+	}
+}
+
+// defaultExpiringDays is the look-ahead of the expiring items list when no days parameter is given.
+const defaultExpiringDays = 7
+
+// @Summary List items expiring soon
+// @Description Store lists its items on sale whose agreement period ends within the given number of days, soonest first.
+// @Tags consignments
+// @Produce  json
+// @Security BearerAuth
+// @Param   days query int false "Look-ahead in days" default(7)
+// @Success 200 {array} model.ConsignmentItem
+// @Failure 400 {object} map[string]string "{"error": "invalid days"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to retrieve expiring items"}"
+// @Router /api/consignments/expiring [get]
+func (h *ConsignmentHandler) ListExpiringItems(c *gin.Context) {
+	days := defaultExpiringDays
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+			return
+		}
+		days = n
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	items, err := h.consignmentService.ListExpiringItems(claims.UserID, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		if err == service.ErrStoreNotActive || err == service.ErrPermissionDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "store not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve expiring items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreditHandler struct {
+	creditService *service.CreditService
+}
+
+func NewCreditHandler(creditService *service.CreditService) *CreditHandler {
+	return &CreditHandler{creditService: creditService}
+}
+
+type TopUpCreditRequest struct {
+	UserID int64       `json:"user_id" binding:"required"`
+	Amount model.Money `json:"amount" swaggertype:"number" binding:"required,gt=0"`
+	Note   string      `json:"note"`
+}
+
+// @Summary Top up store credit
+// @Description Store adds credit to a user's balance at the store after taking payment for it.
+// @Tags credits
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   top_up body TopUpCreditRequest true "Account and amount"
+// @Success 201 {object} model.CreditEntry
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 404 {object} map[string]string "{"error": "user not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to top up store credit"}"
+// @Router /api/credits/top-ups [post]
+func (h *CreditHandler) TopUp(c *gin.Context) {
+	var req TopUpCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	entry, err := h.creditService.TopUp(claims.UserID, req.UserID, req.Amount, req.Note)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCreditAmount:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrStoreNotFound:
+			c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to top up store credit"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary Get a customer's store credit
+// @Description Store retrieves a user's store credit balance at the store with the ledger entries behind it, newest first.
+// @Tags credits
+// @Produce  json
+// @Security BearerAuth
+// @Param   userId path int true "User ID"
+// @Success 200 {object} model.CreditAccount
+// @Failure 400 {object} map[string]string "{"error": "invalid user ID"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get store credit"}"
+// @Router /api/credits/accounts/{userId} [get]
+func (h *CreditHandler) GetStoreAccount(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	account, err := h.creditService.GetStoreAccount(claims.UserID, userID)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get store credit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// @Summary List my store credit balances
+// @Description Player lists their store credit balance at every store they have credit history with.
+// @Tags credits
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} model.CreditBalance
+// @Failure 500 {object} map[string]string "{"error": "failed to list store credit"}"
+// @Router /api/credits [get]
+func (h *CreditHandler) ListMyBalances(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	balances, err := h.creditService.ListMyBalances(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list store credit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+// @Summary Get my store credit at a store
+// @Description Player retrieves their store credit balance at one store with the ledger entries behind it, newest first.
+// @Tags credits
+// @Produce  json
+// @Security BearerAuth
+// @Param   storeId path int true "Store ID"
+// @Success 200 {object} model.CreditAccount
+// @Failure 400 {object} map[string]string "{"error": "invalid store ID"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get store credit"}"
+// @Router /api/credits/stores/{storeId} [get]
+func (h *CreditHandler) GetMyAccount(c *gin.Context) {
+	storeID, err := strconv.ParseInt(c.Param("storeId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	account, err := h.creditService.GetMyAccount(claims.UserID, storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get store credit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
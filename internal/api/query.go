@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// queryDateLayout is the date format accepted by the "from" and "to" query parameters.
+const queryDateLayout = "2006-01-02"
+
+// parseDateRange reads the optional "from" and "to" query parameters (YYYY-MM-DD).
+// Both dates are inclusive, so the returned upper bound is the start of the day after "to".
+func parseDateRange(c *gin.Context) (from, to *time.Time, err error) {
+	if raw := c.Query("from"); raw != "" {
+		t, err := time.ParseInLocation(queryDateLayout, raw, time.Local)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if raw := c.Query("to"); raw != "" {
+		t, err := time.ParseInLocation(queryDateLayout, raw, time.Local)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("from date must not be after to date")
+	}
+	return from, to, nil
+}
+
+// parsePagination reads the optional "page" (1-based) and "page_size" query parameters.
+func parsePagination(c *gin.Context) (page, pageSize int, err error) {
+	page, pageSize = 1, defaultPageSize
+	if raw := c.Query("page"); raw != "" {
+		page, err = strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+	}
+	if raw := c.Query("page_size"); raw != "" {
+		pageSize, err = strconv.Atoi(raw)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return 0, 0, fmt.Errorf("page_size must be between 1 and %d", maxPageSize)
+		}
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"card_manage/internal/service"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// @Summary Sales by period
+// @Description Store sums up its sales per day, week (starting Monday) or month: number of sales and refunds, units, sales total, commission and the players' share. Refunds count against the period they were made in; voided sales are left out. Add format=csv to download the report as CSV.
+// @Tags reports
+// @Produce  json
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param   period query string false "Bucket length" Enums(day, week, month) default(day)
+// @Param   from query string false "Sold on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Sold on or before this date (YYYY-MM-DD)"
+// @Param   format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} model.SalesPeriodReport
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to build report"}"
+// @Router /api/reports/sales [get]
+func (h *ReportHandler) SalesByPeriod(c *gin.Context) {
+	filter, ok := parseReportFilter(c)
+	if !ok {
+		return
+	}
+	period := model.ReportPeriod(c.DefaultQuery("period", string(model.ReportPeriodDay)))
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	reports, err := h.reportService.SalesByPeriod(claims.UserID, period, filter)
+	if err != nil {
+		handleReportError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		rows := make([][]string, len(reports))
+		for i, r := range reports {
+			rows[i] = []string{
+				r.PeriodStart.Format(queryDateLayout), strconv.Itoa(r.Sales), strconv.Itoa(r.Refunds), strconv.Itoa(r.Units),
+				r.Gross.String(), r.Commission.String(), r.PlayerShare.String(),
+			}
+		}
+		writeCSV(c, "sales-by-"+string(period), []string{"period_start", "sales", "refunds", "units", "gross", "commission", "player_share"}, rows)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// @Summary Commission by payment method
+// @Description Store sums up the commission it earned per payment method. Settled sales count what the settlement kept, the others the commission at the rate recorded at sale time; refunds give their sale's commission back. Add format=csv to download the report as CSV.
+// @Tags reports
+// @Produce  json
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param   from query string false "Sold on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Sold on or before this date (YYYY-MM-DD)"
+// @Param   format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} model.CommissionReport
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to build report"}"
+// @Router /api/reports/commission [get]
+func (h *ReportHandler) CommissionByPaymentMethod(c *gin.Context) {
+	filter, ok := parseReportFilter(c)
+	if !ok {
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	reports, err := h.reportService.CommissionByPaymentMethod(claims.UserID, filter)
+	if err != nil {
+		handleReportError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		rows := make([][]string, len(reports))
+		for i, r := range reports {
+			rows[i] = []string{string(r.PaymentMethod), strconv.Itoa(r.Sales), r.Gross.String(), r.Commission.String()}
+		}
+		writeCSV(c, "commission", []string{"payment_method", "sales", "gross", "commission"}, rows)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// @Summary Liability to players
+// @Description Store lists what it owes each player as of now: the share of sold but unsettled sales at the rate recorded at sale time, less outstanding withdrawal fees and refund adjustments, plus requested settlements not yet paid out. Add format=csv to download the report as CSV.
+// @Tags reports
+// @Produce  json
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param   format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} model.PlayerLiability
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to build report"}"
+// @Router /api/reports/liabilities [get]
+func (h *ReportHandler) PlayerLiabilities(c *gin.Context) {
+	if !checkReportFormat(c) {
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	liabilities, err := h.reportService.PlayerLiabilities(claims.UserID)
+	if err != nil {
+		handleReportError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		rows := make([][]string, len(liabilities))
+		for i, l := range liabilities {
+			rows[i] = []string{
+				strconv.FormatInt(l.PlayerID, 10), l.PlayerEmail, l.UnsettledSales.String(), l.UnsettledShare.String(),
+				l.Deductions.String(), l.PendingPayouts.String(), l.Owed.String(),
+			}
+		}
+		writeCSV(c, "liabilities", []string{"player_id", "player_email", "unsettled_sales", "unsettled_share", "deductions", "pending_payouts", "owed"}, rows)
+		return
+	}
+	c.JSON(http.StatusOK, liabilities)
+}
+
+// @Summary Top sellers
+// @Description Store ranks its best-selling cards, or series, by units sold and then by sales total. Refunded and voided sales are left out. Add format=csv to download the report as CSV.
+// @Tags reports
+// @Produce  json
+// @Produce  text/csv
+// @Security BearerAuth
+// @Param   group_by query string false "What to rank" Enums(card, series) default(card)
+// @Param   limit query int false "Number of entries (max 100)" default(10)
+// @Param   from query string false "Sold on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Sold on or before this date (YYYY-MM-DD)"
+// @Param   format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} model.TopSeller
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to build report"}"
+// @Router /api/reports/top-sellers [get]
+func (h *ReportHandler) TopSellers(c *gin.Context) {
+	filter, ok := parseReportFilter(c)
+	if !ok {
+		return
+	}
+	grouping := model.TopSellerGrouping(c.DefaultQuery("group_by", string(model.TopSellersByCard)))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > service.MaxTopSellers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", service.MaxTopSellers)})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	sellers, err := h.reportService.TopSellers(claims.UserID, grouping, filter, limit)
+	if err != nil {
+		handleReportError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		rows := make([][]string, len(sellers))
+		for i, s := range sellers {
+			cardID := ""
+			if s.CardID != nil {
+				cardID = strconv.FormatInt(*s.CardID, 10)
+			}
+			rows[i] = []string{cardID, s.CardName, s.Series, strconv.Itoa(s.Units), strconv.Itoa(s.Sales), s.Gross.String()}
+		}
+		writeCSV(c, "top-sellers-by-"+string(grouping), []string{"card_id", "card_name", "series", "units", "sales", "gross"}, rows)
+		return
+	}
+	c.JSON(http.StatusOK, sellers)
+}
+
+// checkReportFormat checks the optional "format" query parameter, writing the error response
+// when it is neither json nor csv.
+func checkReportFormat(c *gin.Context) bool {
+	switch c.Query("format") {
+	case "", "json", "csv":
+		return true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+	return false
+}
+
+// parseReportFilter reads the date range and checks the format of a report request. It writes
+// the error response itself and reports whether the request may go on.
+func parseReportFilter(c *gin.Context) (repository.ReportFilter, bool) {
+	if !checkReportFormat(c) {
+		return repository.ReportFilter{}, false
+	}
+
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return repository.ReportFilter{}, false
+	}
+	return repository.ReportFilter{From: from, To: to}, true
+}
+
+func handleReportError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidReportPeriod, service.ErrInvalidReportGrouping:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrStoreNotFound:
+		c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
+	case service.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+	}
+}
+
+// writeCSV sends a report as a CSV attachment named after the report and today's date.
+// The report is encoded before anything is sent so an encoding failure can still be
+// answered with an error response.
+func writeCSV(c *gin.Context, name string, header []string, rows [][]string) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write report"})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.csv", name, time.Now().Format(queryDateLayout))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
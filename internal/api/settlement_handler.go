@@ -1,8 +1,11 @@
 package api
 
 import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
 	"card_manage/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,11 +19,13 @@ func NewSettlementHandler(settlementService *service.SettlementService) *Settlem
 }
 
 type CreateSettlementRequest struct {
-	StoreID int64 `json:"store_id" binding:"required"`
+	StoreID        int64               `json:"store_id" binding:"required"`
+	PayoutMethod   model.PaymentMethod `json:"payout_method" binding:"omitempty,oneof=CASH CREDIT"` // Defaults to CASH
+	TransactionIDs []int64             `json:"transaction_ids"`                                     // Sales to settle; every unsettled sale when empty
 }
 
 // @Summary Create a new settlement request
-// @Description Player creates a settlement request to clear their earnings from a store.
+// @Description Player creates a settlement request to clear their earnings from a store, choosing how to be paid. The earnings are worked out at the store's commission rate for the payout method, and the settlement records the gross, commission and net. With payout_method CREDIT the payout goes into the player's store credit at once, completing the settlement. transaction_ids settles only the chosen sales; the player's share must reach the store's minimum payout.
 // @Tags settlements
 // @Accept  json
 // @Produce  json
@@ -28,7 +33,9 @@ type CreateSettlementRequest struct {
 // @Param   settlement body CreateSettlementRequest true "Settlement Request Information"
 // @Success 201 {object} model.Settlement
 // @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
 // @Failure 409 {object} map[string]string "{"error": "conflict (e.g., no unsettled transactions)"}"
+// @Failure 422 {object} map[string]string "{"error": "settlement amount is below the store's minimum payout"}"
 // @Failure 500 {object} map[string]string "{"error": "failed to create settlement request"}"
 // @Router /api/settlements [post]
 func (h *SettlementHandler) CreateSettlement(c *gin.Context) {
@@ -40,24 +47,232 @@ func (h *SettlementHandler) CreateSettlement(c *gin.Context) {
 
 	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
 
-	settlement, err := h.service.CreateSettlement(claims.UserID, req.StoreID)
+	payoutMethod := req.PayoutMethod
+	if payoutMethod == "" {
+		payoutMethod = model.PaymentMethodCash
+	}
+
+	settlement, err := h.service.CreateSettlement(claims.UserID, req.StoreID, payoutMethod, req.TransactionIDs)
 	if err != nil {
-		if err == service.ErrNoUnsettledTransactions {
+		switch err {
+		case service.ErrNoUnsettledTransactions:
 			c.JSON(http.StatusConflict, gin.H{"error": "no transactions available for settlement"})
-			return
+		case service.ErrTransactionNotSettleable:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrBelowMinimumSettlement:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case service.ErrStoreNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "store not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create settlement request"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create settlement request"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, settlement)
 }
 
-// Placeholder for store-side actions
+// @Summary List settlements
+// @Description Players see the settlements they requested; stores see the settlement requests addressed to their store.
+// @Tags settlements
+// @Produce  json
+// @Security BearerAuth
+// @Param   status query string false "Filter by status" Enums(REQUESTED, COMPLETED)
+// @Param   from query string false "Requested on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Requested on or before this date (YYYY-MM-DD)"
+// @Success 200 {array} model.Settlement
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 403 {object} map[string]string "{"error": "user does not have a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to list settlements"}"
+// @Router /api/settlements [get]
 func (h *SettlementHandler) ListSettlements(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "listing settlements (not implemented yet)"})
+	var filter repository.SettlementFilter
+	switch status := model.SettlementStatus(c.Query("status")); status {
+	case "", model.StatusRequested, model.StatusCompleted:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status filter"})
+		return
+	}
+
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.From, filter.To = from, to
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	var settlements []model.Settlement
+	if claims.Role == "STORE" {
+		settlements, err = h.service.ListStoreSettlements(claims.UserID, filter)
+	} else {
+		settlements, err = h.service.ListPlayerSettlements(claims.UserID, filter)
+	}
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusForbidden, gin.H{"error": "user does not have a store"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list settlements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settlements)
 }
 
+// @Summary Get a settlement
+// @Description Retrieves a settlement with the sales it paid, each split at the settlement's commission rate. Only the requesting player and the receiving store can see it.
+// @Tags settlements
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Settlement ID"
+// @Success 200 {object} model.SettlementDetail
+// @Failure 400 {object} map[string]string "{"error": "invalid settlement ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "settlement not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get settlement"}"
+// @Router /api/settlements/{id} [get]
+func (h *SettlementHandler) GetSettlement(c *gin.Context) {
+	settlementID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid settlement ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	settlement, err := h.service.GetSettlement(claims.UserID, settlementID)
+	if err != nil {
+		switch err {
+		case service.ErrSettlementNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "settlement not found"})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get settlement"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+type EarningsResponse struct {
+	Stores   []model.StoreEarnings `json:"stores"`
+	Sales    []model.SaleEarning   `json:"sales"`
+	Total    int                   `json:"total"` // Matching sales across all pages
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+// @Summary Get my earnings
+// @Description Player sees, for every store they have consigned to, the units awaiting intake and on sale, the sold but unsettled sales with their share, and what has been settled and paid out; followed by a page of their sales, newest first, with card name, price, commission and net share. The date range filters the sales only.
+// @Tags settlements
+// @Produce  json
+// @Security BearerAuth
+// @Param   store_id query int false "Only this store"
+// @Param   from query string false "Sold on or after this date (YYYY-MM-DD)"
+// @Param   to query string false "Sold on or before this date (YYYY-MM-DD)"
+// @Param   page query int false "Page number" default(1)
+// @Param   page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} EarningsResponse
+// @Failure 400 {object} map[string]string "{"error": "invalid filter"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get earnings"}"
+// @Router /api/me/earnings [get]
+func (h *SettlementHandler) GetMyEarnings(c *gin.Context) {
+	var filter repository.EarningsFilter
+	if raw := c.Query("store_id"); raw != "" {
+		storeID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || storeID < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+			return
+		}
+		filter.StoreID = storeID
+	}
+
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.From, filter.To = from, to
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Limit, filter.Offset = pageSize, (page-1)*pageSize
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	earnings, total, err := h.service.GetPlayerEarnings(claims.UserID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get earnings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, EarningsResponse{
+		Stores:   earnings.Stores,
+		Sales:    earnings.Sales,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+type UpdateSettlementRequest struct {
+	Status model.SettlementStatus `json:"status" binding:"required,oneof=COMPLETED"`
+}
+
+// @Summary Complete a settlement
+// @Description Store marks a settlement request as COMPLETED after paying the player out.
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Settlement ID"
+// @Param   settlement body UpdateSettlementRequest true "New Status"
+// @Success 200 {object} model.Settlement
+// @Failure 400 {object} map[string]string "{"error": "invalid settlement ID or bad request"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "settlement not found"}"
+// @Failure 409 {object} map[string]string "{"error": "settlement has already been completed"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to complete settlement"}"
+// @Router /api/settlements/{id} [put]
 func (h *SettlementHandler) CompleteSettlement(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "completing settlement (not implemented yet)"})
-}
\ No newline at end of file
+	settlementID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid settlement ID"})
+		return
+	}
+
+	var req UpdateSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	settlement, err := h.service.CompleteSettlement(claims.UserID, settlementID)
+	if err != nil {
+		switch err {
+		case service.ErrSettlementNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "settlement not found"})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		case service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrSettlementAlreadyCompleted:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete settlement"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
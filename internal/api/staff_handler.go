@@ -0,0 +1,263 @@
+package api
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StaffHandler struct {
+	staffService *service.StaffService
+}
+
+func NewStaffHandler(staffService *service.StaffService) *StaffHandler {
+	return &StaffHandler{staffService: staffService}
+}
+
+type InviteStaffRequest struct {
+	Email string          `json:"email" binding:"required,email"`
+	Role  model.StoreRole `json:"role" binding:"required" enums:"MANAGER,CASHIER,INTAKE_CLERK"`
+}
+
+type UpdateStaffRoleRequest struct {
+	Role model.StoreRole `json:"role" binding:"required" enums:"MANAGER,CASHIER,INTAKE_CLERK"`
+}
+
+// @Summary Invite a staff member
+// @Description Store owner invites another store account to their staff with a role. Managers can do everything but edit the store and its staff; cashiers can sell and top up store credit; intake clerks can manage cards and take in consignments.
+// @Tags staff
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   invite body InviteStaffRequest true "Email of the store account and its role"
+// @Success 201 {object} model.StoreMember
+// @Failure 400 {object} map[string]string "{"error": "staff role must be MANAGER, CASHIER or INTAKE_CLERK"}"
+// @Failure 403 {object} map[string]string "{"error": "your store role does not allow this action"}"
+// @Failure 404 {object} map[string]string "{"error": "user not found"}"
+// @Failure 409 {object} map[string]string "{"error": "user is already invited to or working at this store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to invite staff member"}"
+// @Router /api/stores/me/staff [post]
+func (h *StaffHandler) InviteStaff(c *gin.Context) {
+	var req InviteStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	member, err := h.staffService.InviteStaff(claims.UserID, req.Email, req.Role)
+	if err != nil {
+		handleStaffError(c, err, "failed to invite staff member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// @Summary List my store's staff
+// @Description Store staff list everyone working at their store, including its owner, and the invitations not yet answered.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} model.StoreMember
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to list staff"}"
+// @Router /api/stores/me/staff [get]
+func (h *StaffHandler) ListStaff(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	members, err := h.staffService.ListStaff(claims.UserID)
+	if err != nil {
+		handleStaffError(c, err, "failed to list staff")
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Change a staff member's role
+// @Description Store owner changes the role of a staff member or of a pending invitation. The owner's own role cannot be changed.
+// @Tags staff
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   memberId path int true "Staff member ID"
+// @Param   role body UpdateStaffRoleRequest true "New role"
+// @Success 200 {object} model.StoreMember
+// @Failure 400 {object} map[string]string "{"error": "invalid staff member ID"}"
+// @Failure 403 {object} map[string]string "{"error": "your store role does not allow this action"}"
+// @Failure 404 {object} map[string]string "{"error": "staff member not found"}"
+// @Failure 409 {object} map[string]string "{"error": "the store owner cannot be changed or removed"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to update staff member"}"
+// @Router /api/stores/me/staff/{memberId} [put]
+func (h *StaffHandler) UpdateStaffRole(c *gin.Context) {
+	memberID, err := strconv.ParseInt(c.Param("memberId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staff member ID"})
+		return
+	}
+
+	var req UpdateStaffRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	member, err := h.staffService.UpdateStaffRole(claims.UserID, memberID, req.Role)
+	if err != nil {
+		handleStaffError(c, err, "failed to update staff member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Remove a staff member
+// @Description Store owner takes a member off the staff, or withdraws an invitation. What they did while on the staff stays recorded against them.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Param   memberId path int true "Staff member ID"
+// @Success 200 {object} map[string]string "{"message": "staff member removed successfully"}"
+// @Failure 400 {object} map[string]string "{"error": "invalid staff member ID"}"
+// @Failure 403 {object} map[string]string "{"error": "your store role does not allow this action"}"
+// @Failure 404 {object} map[string]string "{"error": "staff member not found"}"
+// @Failure 409 {object} map[string]string "{"error": "the store owner cannot be changed or removed"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to remove staff member"}"
+// @Router /api/stores/me/staff/{memberId} [delete]
+func (h *StaffHandler) RemoveStaff(c *gin.Context) {
+	memberID, err := strconv.ParseInt(c.Param("memberId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staff member ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	if err := h.staffService.RemoveStaff(claims.UserID, memberID); err != nil {
+		handleStaffError(c, err, "failed to remove staff member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "staff member removed successfully"})
+}
+
+// @Summary Leave my store
+// @Description Staff member leaves the store they work at, e.g. to join another. What they did while on the staff stays recorded against them. The store owner cannot leave their own store.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "{"message": "left the store successfully"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 409 {object} map[string]string "{"error": "the store owner cannot be changed or removed"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to leave store"}"
+// @Router /api/stores/me/membership [delete]
+func (h *StaffHandler) LeaveStore(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	if err := h.staffService.LeaveStore(claims.UserID); err != nil {
+		handleStaffError(c, err, "failed to leave store")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "left the store successfully"})
+}
+
+// @Summary List my store invitations
+// @Description Store account lists the invitations to join a store's staff it has not answered yet.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} model.StoreMember
+// @Failure 500 {object} map[string]string "{"error": "failed to list invitations"}"
+// @Router /api/store-invitations [get]
+func (h *StaffHandler) ListMyInvitations(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	invitations, err := h.staffService.ListMyInvitations(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// @Summary Accept a store invitation
+// @Description Store account joins the staff of the store that invited it. An account already working at a store has to leave it first (DELETE /api/stores/me/membership); a store owner cannot leave their own store.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Invitation ID"
+// @Success 200 {object} model.StoreMember
+// @Failure 400 {object} map[string]string "{"error": "invalid invitation ID"}"
+// @Failure 404 {object} map[string]string "{"error": "invitation not found"}"
+// @Failure 409 {object} map[string]string "{"error": "user already works at a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to accept invitation"}"
+// @Router /api/store-invitations/{id}/accept [post]
+func (h *StaffHandler) AcceptInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	member, err := h.staffService.AcceptInvitation(claims.UserID, invitationID)
+	if err != nil {
+		handleStaffError(c, err, "failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Decline a store invitation
+// @Description Store account turns down an invitation to join a store's staff.
+// @Tags staff
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Invitation ID"
+// @Success 200 {object} map[string]string "{"message": "invitation declined"}"
+// @Failure 400 {object} map[string]string "{"error": "invalid invitation ID"}"
+// @Failure 404 {object} map[string]string "{"error": "invitation not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to decline invitation"}"
+// @Router /api/store-invitations/{id}/decline [post]
+func (h *StaffHandler) DeclineInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	if err := h.staffService.DeclineInvitation(claims.UserID, invitationID); err != nil {
+		handleStaffError(c, err, "failed to decline invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation declined"})
+}
+
+func handleStaffError(c *gin.Context, err error, message string) {
+	switch err {
+	case service.ErrInvalidStaffRole, service.ErrNotStoreUser:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case service.ErrStoreNotFound, service.ErrUserNotFound, service.ErrStaffMemberNotFound, service.ErrInvitationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrAlreadyInvited, service.ErrAlreadyStoreMember, service.ErrCannotChangeOwner:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -1,8 +1,13 @@
 package api
 
 import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
 	"card_manage/internal/service"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,14 +20,55 @@ func NewStoreHandler(storeService *service.StoreService) *StoreHandler {
 	return &StoreHandler{storeService: storeService}
 }
 
-type CreateStoreRequest struct {
-	Name             string  `json:"name" binding:"required"`
-	CommissionCash   float64 `json:"commission_cash" binding:"gte=0,lte=100"`
-	CommissionCredit float64 `json:"commission_credit" binding:"gte=0,lte=100"`
+// StoreSettingsRequest is the store details an owner sets when creating or updating their store.
+type StoreSettingsRequest struct {
+	Name             string      `json:"name" binding:"required"`
+	Address          string      `json:"address"`
+	City             string      `json:"city"`
+	Phone            string      `json:"phone"`
+	Email            string      `json:"email" binding:"omitempty,email"`
+	OpeningHours     string      `json:"opening_hours"`                              // Free text, e.g. "Mon-Fri 12:00-21:00"
+	CommissionCash   model.Rate  `json:"commission_cash" swaggertype:"number"`       // Percentage, 0-100
+	CommissionCredit model.Rate  `json:"commission_credit" swaggertype:"number"`     // Percentage, 0-100
+	WithdrawalFee    model.Money `json:"withdrawal_fee" swaggertype:"number"`        // Optional fee per withdrawn item
+	AgreementDays    int         `json:"agreement_days"`                             // Days an approved item stays on sale, 0 for no expiry
+	PriceDropDays    int         `json:"price_drop_days"`                            // Lower listed prices every N days, 0 for never
+	PriceDropRate    model.Rate  `json:"price_drop_rate" swaggertype:"number"`       // Percentage taken off at each drop
+	MinSettlement    model.Money `json:"min_settlement_amount" swaggertype:"number"` // Smallest settlement payout, 0 for none
 }
 
+func (r StoreSettingsRequest) settings() service.StoreSettings {
+	return service.StoreSettings{
+		Name:             r.Name,
+		Address:          r.Address,
+		City:             r.City,
+		Phone:            r.Phone,
+		Email:            r.Email,
+		OpeningHours:     r.OpeningHours,
+		CommissionCash:   r.CommissionCash,
+		CommissionCredit: r.CommissionCredit,
+		WithdrawalFee:    r.WithdrawalFee,
+		AgreementDays:    r.AgreementDays,
+		PriceDropDays:    r.PriceDropDays,
+		PriceDropRate:    r.PriceDropRate,
+		MinSettlement:    r.MinSettlement,
+	}
+}
+
+// @Summary Create my store
+// @Description Store owner registers their store. It starts as PENDING_REVIEW and opens once an admin approves it.
+// @Tags stores
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   store body StoreSettingsRequest true "Store details"
+// @Success 201 {object} model.Store
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 409 {object} map[string]string "{"error": "user already works at a store"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to create store"}"
+// @Router /api/stores [post]
 func (h *StoreHandler) CreateStore(c *gin.Context) {
-	var req CreateStoreRequest
+	var req StoreSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -36,8 +82,16 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 	}
 	claims := payload.(*service.CustomClaims)
 
-	store, err := h.storeService.CreateStore(claims.UserID, req.Name, req.CommissionCash, req.CommissionCredit)
+	store, err := h.storeService.CreateStore(claims.UserID, req.settings())
 	if err != nil {
+		if isStoreSettingsError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrAlreadyStoreMember {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		// In a real app, you'd want more sophisticated error handling
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create store"})
 		return
@@ -45,3 +99,419 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, store)
 }
+
+// @Summary Get my store
+// @Description Store staff retrieve the store they work at, including its review status and the reason it was rejected or suspended.
+// @Tags stores
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} model.Store
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get store"}"
+// @Router /api/stores/me [get]
+func (h *StoreHandler) GetMyStore(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	store, err := h.storeService.GetMyStore(claims.UserID)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get store"})
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+// @Summary Update my store
+// @Description Store owner replaces their store's profile, commission rates and policies. New rates apply to sales made from now on.
+// @Tags stores
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   store body StoreSettingsRequest true "Store details"
+// @Success 200 {object} model.Store
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to update store"}"
+// @Router /api/stores/me [put]
+func (h *StoreHandler) UpdateMyStore(c *gin.Context) {
+	var req StoreSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	store, err := h.storeService.UpdateStore(claims.UserID, req.settings())
+	if err != nil {
+		switch {
+		case isStoreSettingsError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err == service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case err == service.ErrStoreNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update store"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+func isStoreSettingsError(err error) bool {
+	switch err {
+	case service.ErrInvalidCommissionRate, service.ErrInvalidWithdrawalFee, service.ErrInvalidExpiryPolicy, service.ErrInvalidMinSettlement:
+		return true
+	}
+	return false
+}
+
+// CommissionRuleRequest is one rule of a commission schedule.
+type CommissionRuleRequest struct {
+	MinPrice         model.Money  `json:"min_price" swaggertype:"number"`         // Inclusive lower bound on the unit price
+	MaxPrice         *model.Money `json:"max_price" swaggertype:"number"`         // Exclusive upper bound, omit for none
+	Rarity           string       `json:"rarity"`                                 // Only cards of this rarity
+	Series           string       `json:"series"`                                 // Only cards of this series
+	CommissionCash   model.Rate   `json:"commission_cash" swaggertype:"number"`   // Percentage, 0-100
+	CommissionCredit model.Rate   `json:"commission_credit" swaggertype:"number"` // Percentage, 0-100
+}
+
+type CreateCommissionScheduleRequest struct {
+	EffectiveFrom *time.Time              `json:"effective_from"` // RFC 3339; omit to take effect straight away
+	Note          string                  `json:"note"`
+	Rules         []CommissionRuleRequest `json:"rules" binding:"required,min=1"`
+}
+
+// @Summary Add a commission schedule
+// @Description Store owner adds a new version of their commission terms, with rates by unit price band and optional overrides per card rarity or series. The latest schedule that has taken effect applies to new sales; sales no rule covers are charged the store's base rates. The most specific matching rule wins: series, then rarity, then price band alone.
+// @Tags stores
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   schedule body CreateCommissionScheduleRequest true "Schedule and its rules"
+// @Success 201 {object} model.CommissionSchedule
+// @Failure 400 {object} map[string]string "{"error": "commission rules for the same cards cannot have overlapping price bands"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to create commission schedule"}"
+// @Router /api/stores/me/commission-schedules [post]
+func (h *StoreHandler) CreateCommissionSchedule(c *gin.Context) {
+	var req CreateCommissionScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+	rules := make([]model.CommissionRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		rules[i] = model.CommissionRule{
+			MinPrice:         rule.MinPrice,
+			MaxPrice:         rule.MaxPrice,
+			Rarity:           strings.TrimSpace(rule.Rarity),
+			Series:           strings.TrimSpace(rule.Series),
+			CommissionCash:   rule.CommissionCash,
+			CommissionCredit: rule.CommissionCredit,
+		}
+	}
+
+	schedule, err := h.storeService.CreateCommissionSchedule(claims.UserID, effectiveFrom, req.Note, rules)
+	if err != nil {
+		switch err {
+		case service.ErrEmptyCommissionSchedule, service.ErrInvalidCommissionRule, service.ErrOverlappingCommissionRules, service.ErrScheduleInPast:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrStoreNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create commission schedule"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// @Summary List my commission schedules
+// @Description Store staff list every version of their store's commission terms, past, current and upcoming, latest effective date first.
+// @Tags stores
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} model.CommissionSchedule
+// @Failure 404 {object} map[string]string "{"error": "store not found for the current user"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to list commission schedules"}"
+// @Router /api/stores/me/commission-schedules [get]
+func (h *StoreHandler) ListCommissionSchedules(c *gin.Context) {
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	schedules, err := h.storeService.ListCommissionSchedules(claims.UserID)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list commission schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// @Summary Delete an upcoming commission schedule
+// @Description Store owner withdraws a commission schedule before it takes effect. Schedules already in force are kept, as sales refer to them.
+// @Tags stores
+// @Produce  json
+// @Security BearerAuth
+// @Param   scheduleId path int true "Schedule ID"
+// @Success 200 {object} map[string]string "{"message": "commission schedule deleted successfully"}"
+// @Failure 400 {object} map[string]string "{"error": "invalid schedule ID"}"
+// @Failure 404 {object} map[string]string "{"error": "commission schedule not found"}"
+// @Failure 409 {object} map[string]string "{"error": "commission schedule has already taken effect"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to delete commission schedule"}"
+// @Router /api/stores/me/commission-schedules/{scheduleId} [delete]
+func (h *StoreHandler) DeleteCommissionSchedule(c *gin.Context) {
+	scheduleID, err := strconv.ParseInt(c.Param("scheduleId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	if err := h.storeService.DeleteCommissionSchedule(claims.UserID, scheduleID); err != nil {
+		switch err {
+		case service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrStoreNotFound, service.ErrCommissionScheduleNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case service.ErrScheduleInForce:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete commission schedule"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "commission schedule deleted successfully"})
+}
+
+// StoreDirectoryResponse is one page of the store directory.
+type StoreDirectoryResponse struct {
+	Stores   []model.StoreProfile `json:"stores"`
+	Total    int                  `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+}
+
+// @Summary Search the store directory
+// @Description Lists active stores by name, with their location, contact details and consignment terms, so players can find where to consign.
+// @Tags stores
+// @Produce  json
+// @Security BearerAuth
+// @Param   name query string false "Part of the store name (case-insensitive)"
+// @Param   city query string false "City (case-insensitive)"
+// @Param   page query int false "Page number, starting at 1" default(1)
+// @Param   page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} StoreDirectoryResponse
+// @Failure 400 {object} map[string]string "{"error": "invalid page"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to search stores"}"
+// @Router /api/stores [get]
+func (h *StoreHandler) SearchStores(c *gin.Context) {
+	filter := repository.StoreFilter{
+		Name: strings.TrimSpace(c.Query("name")),
+		City: strings.TrimSpace(c.Query("city")),
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Limit, filter.Offset = pageSize, (page-1)*pageSize
+
+	stores, total, err := h.storeService.SearchStores(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search stores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StoreDirectoryResponse{
+		Stores:   stores,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// @Summary Get a store's profile
+// @Description Retrieves the public profile of an active store.
+// @Tags stores
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Store ID"
+// @Success 200 {object} model.StoreProfile
+// @Failure 400 {object} map[string]string "{"error": "invalid store ID"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get store"}"
+// @Router /api/stores/{id} [get]
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	storeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+
+	profile, err := h.storeService.GetStoreProfile(storeID)
+	if err != nil {
+		if err == service.ErrTargetStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get store"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// @Summary List stores for review
+// @Description Admin lists stores, optionally only those in one status (e.g. PENDING_REVIEW for the review queue), oldest first.
+// @Tags admin
+// @Produce  json
+// @Security BearerAuth
+// @Param   status query string false "Store status" Enums(PENDING_REVIEW, ACTIVE, INACTIVE, REJECTED, SUSPENDED)
+// @Success 200 {array} model.Store
+// @Failure 400 {object} map[string]string "{"error": "invalid store status"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to list stores"}"
+// @Router /api/admin/stores [get]
+func (h *StoreHandler) ListStores(c *gin.Context) {
+	stores, err := h.storeService.ListStores(model.StoreStatus(c.Query("status")))
+	if err != nil {
+		if err == service.ErrInvalidStoreStatus {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list stores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
+
+type StoreReviewRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// @Summary Approve a store
+// @Description Admin opens a store that is pending review, was rejected or is suspended.
+// @Tags admin
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Store ID"
+// @Success 200 {object} model.Store
+// @Failure 400 {object} map[string]string "{"error": "invalid store ID"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 409 {object} map[string]string "{"error": "store cannot be moved to that status"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to review store"}"
+// @Router /api/admin/stores/{id}/approve [post]
+func (h *StoreHandler) ApproveStore(c *gin.Context) {
+	storeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	store, err := h.storeService.ApproveStore(claims.UserID, storeID)
+	if err != nil {
+		handleStoreReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+// @Summary Reject a store
+// @Description Admin turns down a store registration with a reason the store owner can see.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Store ID"
+// @Param   review body StoreReviewRequest true "Reason for the rejection"
+// @Success 200 {object} model.Store
+// @Failure 400 {object} map[string]string "{"error": "a reason is required"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 409 {object} map[string]string "{"error": "store cannot be moved to that status"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to review store"}"
+// @Router /api/admin/stores/{id}/reject [post]
+func (h *StoreHandler) RejectStore(c *gin.Context) {
+	h.reviewWithReason(c, h.storeService.RejectStore)
+}
+
+// @Summary Suspend a store
+// @Description Admin closes an active store with a reason. A suspended store cannot manage cards, consignments or sales until it is approved again.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Store ID"
+// @Param   review body StoreReviewRequest true "Reason for the suspension"
+// @Success 200 {object} model.Store
+// @Failure 400 {object} map[string]string "{"error": "a reason is required"}"
+// @Failure 404 {object} map[string]string "{"error": "store not found"}"
+// @Failure 409 {object} map[string]string "{"error": "store cannot be moved to that status"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to review store"}"
+// @Router /api/admin/stores/{id}/suspend [post]
+func (h *StoreHandler) SuspendStore(c *gin.Context) {
+	h.reviewWithReason(c, h.storeService.SuspendStore)
+}
+
+func (h *StoreHandler) reviewWithReason(c *gin.Context, review func(adminID, storeID int64, reason string) (*model.Store, error)) {
+	storeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+
+	var req StoreReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	store, err := review(claims.UserID, storeID, req.Reason)
+	if err != nil {
+		handleStoreReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+func handleStoreReviewError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrReasonRequired:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrTargetStoreNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidStoreTransition:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review store"})
+	}
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"card_manage/internal/model"
 	"card_manage/internal/service"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,12 +20,14 @@ func NewTransactionHandler(transactionService *service.TransactionService) *Tran
 
 type CreateTransactionRequest struct {
 	ConsignmentItemID int64                 `json:"consignment_item_id" binding:"required"`
-	Price             float64               `json:"price" binding:"required,gt=0"`
+	Price             model.Money           `json:"price" swaggertype:"number" binding:"required,gt=0"` // Unit price
+	Quantity          int                   `json:"quantity" binding:"min=0"`                          // Units sold, defaults to 1
 	PaymentMethod     model.PaymentMethod `json:"payment_method" binding:"required,oneof=CASH CREDIT"`
+	BuyerID           int64                 `json:"buyer_id"` // Store credit account to charge, required for CREDIT
 }
 
 // @Summary Create a new transaction
-// @Description Store creates a transaction for a sold consignment item.
+// @Description Store creates a transaction for a sold consignment item. The price is per unit; selling fewer units than the item holds leaves the rest on sale. Sales below the player's minimum price are refused; sales below the listed price succeed with a warning. CREDIT sales are paid from the buyer's store credit at this store.
 // @Tags transactions
 // @Accept  json
 // @Produce  json
@@ -33,7 +37,8 @@ type CreateTransactionRequest struct {
 // @Failure 400 {object} map[string]string "{"error": "bad request"}"
 // @Failure 403 {object} map[string]string "{"error": "permission denied"}"
 // @Failure 404 {object} map[string]string "{"error": "item not found"}"
-// @Failure 409 {object} map[string]string "{"error": "conflict (e.g., item not approved or already sold)"}"
+// @Failure 409 {object} map[string]string "{"error": "conflict (e.g., item not approved, already sold or not enough units)"}"
+// @Failure 422 {object} map[string]string "{"error": "sale price is below the player's minimum price, or not enough store credit"}"
 // @Failure 500 {object} map[string]string "{"error": "failed to create transaction"}"
 // @Router /api/transactions [post]
 func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
@@ -45,15 +50,26 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 
 	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
 
-	tx, err := h.transactionService.CreateTransaction(claims.UserID, req.ConsignmentItemID, req.Price, req.PaymentMethod)
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	tx, err := h.transactionService.CreateTransaction(claims.UserID, req.ConsignmentItemID, quantity, req.Price, req.PaymentMethod, req.BuyerID)
 	if err != nil {
 		switch err {
 		case service.ErrConsignmentItemNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": "consignment item not found"})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		case service.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
-		case service.ErrItemNotApproved, service.ErrItemAlreadySold:
+		case service.ErrItemNotApproved, service.ErrItemAlreadySold, service.ErrInsufficientQuantity:
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case service.ErrPriceBelowFloor, service.ErrInsufficientCredit:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case service.ErrBuyerRequired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create transaction"})
 		}
@@ -62,3 +78,233 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, tx)
 }
+
+type SaleLineRequest struct {
+	ConsignmentItemID int64               `json:"consignment_item_id" binding:"required"`
+	Price             model.Money         `json:"price" swaggertype:"number" binding:"required,gt=0"` // Unit price
+	Quantity          int                 `json:"quantity" binding:"min=0"`                          // Units sold, defaults to 1
+	PaymentMethod     model.PaymentMethod `json:"payment_method" binding:"omitempty,oneof=CASH CREDIT"` // Required when tenders use several methods
+}
+
+type SaleTenderRequest struct {
+	PaymentMethod model.PaymentMethod `json:"payment_method" binding:"required,oneof=CASH CREDIT"`
+	Amount        model.Money         `json:"amount" swaggertype:"number" binding:"required,gt=0"`
+}
+
+type CreateSaleRequest struct {
+	Lines   []SaleLineRequest   `json:"lines" binding:"required,min=1,max=200,dive"`
+	Tenders []SaleTenderRequest `json:"tenders" binding:"required,min=1,dive"`
+	BuyerID int64               `json:"buyer_id"` // Store credit account to charge, required for CREDIT tenders
+}
+
+// @Summary Check out a sale
+// @Description Store sells several consignment items under one receipt number, paid with one or more tenders. Each line's commission uses the rate of the payment method assigned to it; when tenders use several methods every line must name one, and the tenders of each method must add up to its lines. CREDIT tenders are paid from the buyer's store credit at this store. The sale is all-or-nothing: if any line cannot be sold, nothing is recorded and the error names the line.
+// @Tags sales
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   sale body CreateSaleRequest true "Sale lines and tenders"
+// @Success 201 {object} model.Sale
+// @Failure 400 {object} map[string]string "{"error": "bad request (e.g., tenders do not add up)"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "line 1: consignment item not found"}"
+// @Failure 409 {object} map[string]string "{"error": "line 1: conflict (e.g., item not approved, already sold or not enough units)"}"
+// @Failure 422 {object} map[string]string "{"error": "line 1: sale price is below the player's minimum price, or not enough store credit"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to create sale"}"
+// @Router /api/sales [post]
+func (h *TransactionHandler) CreateSale(c *gin.Context) {
+	var req CreateSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	lines := make([]service.SaleLine, len(req.Lines))
+	for i, line := range req.Lines {
+		quantity := line.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		lines[i] = service.SaleLine{
+			ItemID:        line.ConsignmentItemID,
+			Quantity:      quantity,
+			Price:         line.Price,
+			PaymentMethod: line.PaymentMethod,
+		}
+	}
+	tenders := make([]model.SaleTender, len(req.Tenders))
+	for i, tender := range req.Tenders {
+		tenders[i] = model.SaleTender{PaymentMethod: tender.PaymentMethod, Amount: tender.Amount}
+	}
+
+	sale, err := h.transactionService.Checkout(claims.UserID, req.BuyerID, lines, tenders)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrConsignmentItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrStoreNotActive), errors.Is(err, service.ErrPermissionDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		case errors.Is(err, service.ErrItemNotApproved), errors.Is(err, service.ErrItemAlreadySold), errors.Is(err, service.ErrInsufficientQuantity):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPriceBelowFloor), errors.Is(err, service.ErrInsufficientCredit):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrEmptySale), errors.Is(err, service.ErrInvalidTender), errors.Is(err, service.ErrUnassignedLine),
+			errors.Is(err, service.ErrTenderMismatch), errors.Is(err, service.ErrInvalidQuantity), errors.Is(err, service.ErrInvalidPrice),
+			errors.Is(err, service.ErrBuyerRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create sale"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, sale)
+}
+
+// @Summary Get a sale
+// @Description Store retrieves one of its sales with its lines and tenders.
+// @Tags sales
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Sale ID"
+// @Success 200 {object} model.Sale
+// @Failure 400 {object} map[string]string "{"error": "invalid sale ID"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "sale not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to get sale"}"
+// @Router /api/sales/{id} [get]
+func (h *TransactionHandler) GetSale(c *gin.Context) {
+	saleID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sale ID"})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	sale, err := h.transactionService.GetSale(claims.UserID, saleID)
+	if err != nil {
+		switch err {
+		case service.ErrSaleNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case service.ErrStoreNotActive, service.ErrPermissionDenied:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case service.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get sale"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, sale)
+}
+
+type VoidTransactionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RefundTransactionRequest struct {
+	Reason           string `json:"reason" binding:"required"`
+	AdjustSettlement bool   `json:"adjust_settlement"` // Required to refund an item that has already been settled
+}
+
+// @Summary Void a transaction
+// @Description Store voids a sale rung up in error. Only sales made today whose item has not been settled can be voided; the item goes back on sale and a CREDIT sale goes back to the buyer's store credit.
+// @Tags transactions
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Transaction ID"
+// @Param   void body VoidTransactionRequest true "Why the sale is voided"
+// @Success 200 {object} model.Transaction
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "transaction not found"}"
+// @Failure 409 {object} map[string]string "{"error": "conflict (e.g., already voided or refunded, not from today, or already settled)"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to void transaction"}"
+// @Router /api/transactions/{id}/void [post]
+func (h *TransactionHandler) VoidTransaction(c *gin.Context) {
+	transactionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction ID"})
+		return
+	}
+
+	var req VoidTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	tx, err := h.transactionService.VoidTransaction(claims.UserID, transactionID, req.Reason)
+	if err != nil {
+		h.handleReversalError(c, err, "failed to void transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, tx)
+}
+
+// @Summary Refund a transaction
+// @Description Store refunds a sale when the buyer returns the card; a CREDIT sale goes back to the buyer's store credit. A reversing REFUND transaction (same unit price, negative quantity) is recorded and the item goes back on sale. Refunding an item that has already been settled requires adjust_settlement; the player's share is then taken back from their next settlement with the store.
+// @Tags transactions
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id path int true "Transaction ID"
+// @Param   refund body RefundTransactionRequest true "Why the sale is refunded"
+// @Success 201 {object} model.Transaction
+// @Failure 400 {object} map[string]string "{"error": "bad request"}"
+// @Failure 403 {object} map[string]string "{"error": "permission denied"}"
+// @Failure 404 {object} map[string]string "{"error": "transaction not found"}"
+// @Failure 409 {object} map[string]string "{"error": "conflict (e.g., already voided or refunded, or settled without adjust_settlement)"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to refund transaction"}"
+// @Router /api/transactions/{id}/refund [post]
+func (h *TransactionHandler) RefundTransaction(c *gin.Context) {
+	transactionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction ID"})
+		return
+	}
+
+	var req RefundTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet(AuthorizationPayloadKey).(*service.CustomClaims)
+
+	refund, err := h.transactionService.RefundTransaction(claims.UserID, transactionID, req.Reason, req.AdjustSettlement)
+	if err != nil {
+		h.handleReversalError(c, err, "failed to refund transaction")
+		return
+	}
+
+	c.JSON(http.StatusCreated, refund)
+}
+
+// handleReversalError maps the errors shared by voids and refunds to HTTP responses.
+func (h *TransactionHandler) handleReversalError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case service.ErrTransactionNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrStoreNotActive, service.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case service.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+	case service.ErrReasonRequired:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrNotASale, service.ErrAlreadyReversed, service.ErrVoidNotAllowed, service.ErrRefundNeedsAdjustment:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
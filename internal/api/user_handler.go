@@ -10,14 +10,14 @@ import (
 )
 
 type UserHandler struct {
-	userService *service.UserService
-	jwtService  *service.JWTService
+	userService    *service.UserService
+	sessionService *service.SessionService
 }
 
-func NewUserHandler(userService *service.UserService, jwtService *service.JWTService) *UserHandler {
+func NewUserHandler(userService *service.UserService, sessionService *service.SessionService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		jwtService:  jwtService,
+		userService:    userService,
+		sessionService: sessionService,
 	}
 }
 
@@ -32,6 +32,10 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 // @Summary User Registration
 // @Description Creates a new user account.
 // @Tags users
@@ -67,12 +71,12 @@ func (h *UserHandler) Register(c *gin.Context) {
 }
 
 // @Summary User Login
-// @Description Authenticates a user and returns a JWT token.
+// @Description Authenticates a user and starts a session. Returns a short-lived JWT access token and a refresh token to get new ones with.
 // @Tags users
 // @Accept  json
 // @Produce  json
 // @Param   credentials body LoginRequest true "Login Credentials"
-// @Success 200 {object} map[string]string "{"token": "your_jwt_token"}"
+// @Success 200 {object} service.TokenPair
 // @Failure 400 {object} map[string]string "{"error": "bad_request_error"}"
 // @Failure 401 {object} map[string]string "{"error": "invalid email or password"}"
 // @Failure 500 {object} map[string]string "{"error": "internal_server_error"}"
@@ -97,13 +101,107 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.jwtService.GenerateToken(user)
+	tokens, err := h.sessionService.StartSession(user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new access token and refresh token. Each refresh token works once; presenting one that has already been used ends its session.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   token body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} service.TokenPair
+// @Failure 400 {object} map[string]string "{"error": "bad_request_error"}"
+// @Failure 401 {object} map[string]string "{"error": "invalid or expired refresh token"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to refresh token"}"
+// @Router /refresh [post]
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.sessionService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary User Logout
+// @Description Ends the session the refresh token belongs to. Its access tokens stop working straight away.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   token body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]string "{"message": "logged out successfully"}"
+// @Failure 400 {object} map[string]string "{"error": "bad_request_error"}"
+// @Failure 401 {object} map[string]string "{"error": "invalid or expired refresh token"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to logout"}"
+// @Router /logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.sessionService.Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
+
+// @Summary Revoke a user's sessions
+// @Description Ends every session of a user, e.g. when their account was compromised. Their access tokens stop working straight away and they have to log in again. Requires ADMIN role.
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "{"message": "sessions revoked", "revoked": 2}"
+// @Failure 400 {object} map[string]string "{"error": "invalid user ID"}"
+// @Failure 401 {object} map[string]string "{"error": "unauthorized"}"
+// @Failure 403 {object} map[string]string "{"error": "forbidden"}"
+// @Failure 404 {object} map[string]string "{"error": "user not found"}"
+// @Failure 500 {object} map[string]string "{"error": "failed to revoke sessions"}"
+// @Security BearerAuth
+// @Router /api/users/{id}/sessions [delete]
+func (h *UserHandler) RevokeSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeUserSessions(id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked", "revoked": revoked})
 }
 
 // @Summary Get user by ID
@@ -12,6 +12,10 @@ type Config struct {
 	ServerAddress  string `mapstructure:"SERVER_ADDRESS"`
 	JWTSecret      string `mapstructure:"JWT_SECRET"`
 	JWTExpiresIn   string `mapstructure:"JWT_EXPIRES_IN"`
+	// How long a login session can be kept alive with refresh tokens, e.g. "720h"
+	RefreshTokenExpiresIn string `mapstructure:"REFRESH_TOKEN_EXPIRES_IN"`
+	// How often the expiry scheduler applies price drops and expires consignments, e.g. "1h"
+	ExpiryCheckInterval string `mapstructure:"EXPIRY_CHECK_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
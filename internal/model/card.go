@@ -11,6 +11,8 @@ type Card struct {
 	Rarity     string    `json:"rarity,omitempty"`
 	CardNumber string    `json:"card_number,omitempty"`
 	ImageURL   string    `json:"image_url,omitempty"`
+	CreatedBy  *int64    `json:"created_by,omitempty"` // Staff member who added the card
+	UpdatedBy  *int64    `json:"updated_by,omitempty"` // Staff member who last edited it
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
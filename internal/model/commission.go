@@ -0,0 +1,111 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// CommissionSchedule corresponds to the "commission_schedules" table: one version of a store's
+// commission terms. The schedule with the latest EffectiveFrom not after a sale is the one in force
+// for it. Schedules are never edited; a store changes its terms by adding a new one.
+type CommissionSchedule struct {
+	ID            int64            `json:"id"`
+	StoreID       int64            `json:"store_id"`
+	EffectiveFrom time.Time        `json:"effective_from"`
+	Note          string           `json:"note,omitempty"`
+	CreatedBy     *int64           `json:"created_by,omitempty"`
+	Rules         []CommissionRule `json:"rules"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// CommissionRule corresponds to the "commission_rules" table: the rates a schedule charges on
+// sales in a unit price band, optionally only for cards of one rarity and/or series.
+type CommissionRule struct {
+	ID               int64  `json:"id"`
+	ScheduleID       int64  `json:"schedule_id"`
+	MinPrice         Money  `json:"min_price" swaggertype:"number"`           // Inclusive lower bound on the unit price
+	MaxPrice         *Money `json:"max_price,omitempty" swaggertype:"number"` // Exclusive upper bound, none when nil
+	Rarity           string `json:"rarity,omitempty"`                         // Only cards of this rarity, case-insensitive
+	Series           string `json:"series,omitempty"`                         // Only cards of this series, case-insensitive
+	CommissionCash   Rate   `json:"commission_cash" swaggertype:"number"`
+	CommissionCredit Rate   `json:"commission_credit" swaggertype:"number"`
+}
+
+// RateFor returns the rule's commission rate on sales paid with the given method.
+func (r *CommissionRule) RateFor(method PaymentMethod) Rate {
+	if method == PaymentMethodCash {
+		return r.CommissionCash
+	}
+	return r.CommissionCredit
+}
+
+// Valid reports whether the rule has valid rates and a non-empty price band.
+func (r *CommissionRule) Valid() bool {
+	if !r.CommissionCash.Valid() || !r.CommissionCredit.Valid() || r.MinPrice < 0 {
+		return false
+	}
+	return r.MaxPrice == nil || *r.MaxPrice > r.MinPrice
+}
+
+// Matches reports whether the rule covers a card sold at the given unit price.
+func (r *CommissionRule) Matches(price Money, card *Card) bool {
+	if price < r.MinPrice || (r.MaxPrice != nil && price >= *r.MaxPrice) {
+		return false
+	}
+	if r.Rarity != "" && (card == nil || !strings.EqualFold(r.Rarity, card.Rarity)) {
+		return false
+	}
+	if r.Series != "" && (card == nil || !strings.EqualFold(r.Series, card.Series)) {
+		return false
+	}
+	return true
+}
+
+// specificity ranks overrides: a series beats a rarity, which beats a price band alone.
+func (r *CommissionRule) specificity() int {
+	rank := 0
+	if r.Series != "" {
+		rank += 2
+	}
+	if r.Rarity != "" {
+		rank++
+	}
+	return rank
+}
+
+// overlaps reports whether both rules apply to the same cards with overlapping price bands,
+// so that neither would be the obvious one to apply.
+func (r *CommissionRule) overlaps(other *CommissionRule) bool {
+	if !strings.EqualFold(r.Rarity, other.Rarity) || !strings.EqualFold(r.Series, other.Series) {
+		return false
+	}
+	rBelowOther := r.MaxPrice != nil && *r.MaxPrice <= other.MinPrice
+	otherBelowR := other.MaxPrice != nil && *other.MaxPrice <= r.MinPrice
+	return !rBelowOther && !otherBelowR
+}
+
+// HasOverlappingRules reports whether two of the schedule's rules cover the same sale at the same
+// level of override.
+func (s *CommissionSchedule) HasOverlappingRules() bool {
+	for i := range s.Rules {
+		for j := i + 1; j < len(s.Rules); j++ {
+			if s.Rules[i].overlaps(&s.Rules[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RuleFor returns the most specific rule covering a card sold at the given unit price, or nil
+// when no rule does and the store's base rates apply.
+func (s *CommissionSchedule) RuleFor(price Money, card *Card) *CommissionRule {
+	var best *CommissionRule
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		if rule.Matches(price, card) && (best == nil || rule.specificity() > best.specificity()) {
+			best = rule
+		}
+	}
+	return best
+}
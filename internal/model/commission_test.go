@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommissionScheduleRuleFor(t *testing.T) {
+	hundred := Money(10000)
+	schedule := &CommissionSchedule{Rules: []CommissionRule{
+		{ID: 1, MaxPrice: &hundred, CommissionCash: 1500, CommissionCredit: 1000},
+		{ID: 2, MinPrice: hundred, CommissionCash: 800, CommissionCredit: 500},
+		{ID: 3, Rarity: "SR", CommissionCash: 1200, CommissionCredit: 900},
+		{ID: 4, Series: "Base Set", MinPrice: hundred, CommissionCash: 600, CommissionCredit: 300},
+	}}
+	common := &Card{Rarity: "C", Series: "Jungle"}
+
+	assert.Equal(t, int64(1), schedule.RuleFor(9999, common).ID, "below the band boundary")
+	assert.Equal(t, int64(2), schedule.RuleFor(10000, common).ID, "the lower bound is inclusive")
+	assert.Equal(t, int64(3), schedule.RuleFor(50000, &Card{Rarity: "sr"}).ID, "rarity overrides the band, case-insensitively")
+	assert.Equal(t, int64(4), schedule.RuleFor(50000, &Card{Rarity: "SR", Series: "Base Set"}).ID, "series beats rarity")
+	assert.Equal(t, int64(3), schedule.RuleFor(5000, &Card{Rarity: "SR", Series: "Base Set"}).ID, "series rule outside its band")
+
+	assert.Nil(t, (&CommissionSchedule{Rules: []CommissionRule{{MinPrice: hundred}}}).RuleFor(100, common), "no rule covers the sale")
+
+	rule := schedule.Rules[0]
+	assert.Equal(t, Rate(1500), rule.RateFor(PaymentMethodCash))
+	assert.Equal(t, Rate(1000), rule.RateFor(PaymentMethodCredit))
+}
+
+func TestCommissionRuleValid(t *testing.T) {
+	ten, twenty := Money(1000), Money(2000)
+
+	assert.True(t, (&CommissionRule{MinPrice: ten, MaxPrice: &twenty, CommissionCash: 1000}).Valid())
+	assert.True(t, (&CommissionRule{CommissionCash: 10000, CommissionCredit: 0}).Valid())
+	assert.False(t, (&CommissionRule{MinPrice: twenty, MaxPrice: &ten}).Valid(), "empty band")
+	assert.False(t, (&CommissionRule{MinPrice: ten, MaxPrice: &ten}).Valid(), "empty band")
+	assert.False(t, (&CommissionRule{MinPrice: -1}).Valid())
+	assert.False(t, (&CommissionRule{CommissionCredit: 10001}).Valid())
+}
+
+func TestCommissionScheduleHasOverlappingRules(t *testing.T) {
+	ten, twenty := Money(1000), Money(2000)
+
+	adjacent := &CommissionSchedule{Rules: []CommissionRule{{MaxPrice: &ten}, {MinPrice: ten, MaxPrice: &twenty}, {MinPrice: twenty}}}
+	assert.False(t, adjacent.HasOverlappingRules())
+
+	overrides := &CommissionSchedule{Rules: []CommissionRule{{}, {Rarity: "SR"}, {Series: "Base Set"}, {Rarity: "SR", Series: "Base Set"}}}
+	assert.False(t, overrides.HasOverlappingRules(), "rules for different cards may cover the same prices")
+
+	overlapping := &CommissionSchedule{Rules: []CommissionRule{{Rarity: "SR", MaxPrice: &twenty}, {Rarity: "sr", MinPrice: ten}}}
+	assert.True(t, overlapping.HasOverlappingRules())
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// CardCondition is the physical condition of a consigned copy on the usual singles scale.
+type CardCondition string
+
+const (
+	ConditionNearMint         CardCondition = "NM"  // Near Mint
+	ConditionLightlyPlayed    CardCondition = "LP"  // Lightly Played
+	ConditionModeratelyPlayed CardCondition = "MP"  // Moderately Played
+	ConditionHeavilyPlayed    CardCondition = "HP"  // Heavily Played
+	ConditionDamaged          CardCondition = "DMG" // Damaged
+)
+
+// Valid reports whether c is one of the known conditions.
+func (c CardCondition) Valid() bool {
+	switch c {
+	case ConditionNearMint, ConditionLightlyPlayed, ConditionModeratelyPlayed, ConditionHeavilyPlayed, ConditionDamaged:
+		return true
+	}
+	return false
+}
+
+// MaxGradeScore is the top of the 1–10 scale used by third-party graders.
+const MaxGradeScore = 10
+
+// ConditionGrade is the condition the store assessed at intake, with the third-party grade
+// when the copy is slabbed. Items carry it from approval on and sales keep a copy of it.
+type ConditionGrade struct {
+	Condition    CardCondition `json:"condition,omitempty" enums:"NM,LP,MP,HP,DMG"`
+	GradeCompany string        `json:"grade_company,omitempty"` // Third-party grader, e.g. PSA, BGS or CGC
+	GradeScore   *float64      `json:"grade_score,omitempty"`   // Grader's score, 1–10 in steps of 0.5
+}
+
+// Valid reports whether the grade has a known condition and, if graded, both a company
+// and a score between 1 and 10 in half-point steps.
+func (g ConditionGrade) Valid() bool {
+	if !g.Condition.Valid() {
+		return false
+	}
+	if g.GradeScore == nil {
+		return g.GradeCompany == ""
+	}
+	score := *g.GradeScore
+	return g.GradeCompany != "" && score >= 1 && score <= MaxGradeScore && score*2 == float64(int(score*2))
+}
+
+// ConsignmentItemPhoto corresponds to the "consignment_item_photos" table: a picture the store
+// took of the physical copy at intake, so both sides can refer to its condition later.
+type ConsignmentItemPhoto struct {
+	ID                int64     `json:"id"`
+	ConsignmentItemID int64     `json:"consignment_item_id"`
+	URL               string    `json:"url"`
+	UploadedBy        int64     `json:"uploaded_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
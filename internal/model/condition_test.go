@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionGrade_Valid(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name  string
+		grade ConditionGrade
+		want  bool
+	}{
+		{"ungraded copy", ConditionGrade{Condition: ConditionHeavilyPlayed}, true},
+		{"graded copy", ConditionGrade{Condition: ConditionNearMint, GradeCompany: "PSA", GradeScore: score(10)}, true},
+		{"half-point score", ConditionGrade{Condition: ConditionNearMint, GradeCompany: "BGS", GradeScore: score(9.5)}, true},
+		{"missing condition", ConditionGrade{}, false},
+		{"unknown condition", ConditionGrade{Condition: "MINT"}, false},
+		{"score without company", ConditionGrade{Condition: ConditionNearMint, GradeScore: score(9)}, false},
+		{"company without score", ConditionGrade{Condition: ConditionNearMint, GradeCompany: "PSA"}, false},
+		{"score above 10", ConditionGrade{Condition: ConditionNearMint, GradeCompany: "PSA", GradeScore: score(11)}, false},
+		{"score off the half-point scale", ConditionGrade{Condition: ConditionNearMint, GradeCompany: "PSA", GradeScore: score(9.3)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.grade.Valid())
+		})
+	}
+}
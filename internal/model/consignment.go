@@ -6,39 +6,199 @@ import "time"
 type ConsignmentRequestStatus string
 
 const (
-	ConsignmentRequestStatusProcessing ConsignmentRequestStatus = "PROCESSING"
-	ConsignmentRequestStatusCompleted  ConsignmentRequestStatus = "COMPLETED"
+	ConsignmentRequestStatusProcessing        ConsignmentRequestStatus = "PROCESSING"         // Some items still await intake
+	ConsignmentRequestStatusCompleted         ConsignmentRequestStatus = "COMPLETED"          // Intake finished, every item accepted
+	ConsignmentRequestStatusPartiallyApproved ConsignmentRequestStatus = "PARTIALLY_APPROVED" // Intake finished, some items rejected
+	ConsignmentRequestStatusAllRejected       ConsignmentRequestStatus = "ALL_REJECTED"       // Intake finished, every item rejected
+	ConsignmentRequestStatusClosed            ConsignmentRequestStatus = "CLOSED"             // Every item is settled or rejected
 )
 
 // ConsignmentItemStatus represents the status of an individual item in a consignment.
 type ConsignmentItemStatus string
 
 const (
-	ItemStatusPending   ConsignmentItemStatus = "PENDING"
-	ItemStatusApproved  ConsignmentItemStatus = "APPROVED"
-	ItemStatusRejected  ConsignmentItemStatus = "REJECTED"
-	ItemStatusSold      ConsignmentItemStatus = "SOLD"
-	ItemStatusCleared   ConsignmentItemStatus = "CLEARED"
+	ItemStatusPending  ConsignmentItemStatus = "PENDING"
+	ItemStatusApproved ConsignmentItemStatus = "APPROVED"
+	ItemStatusRejected ConsignmentItemStatus = "REJECTED"
+	ItemStatusSold     ConsignmentItemStatus = "SOLD"
+	ItemStatusCleared  ConsignmentItemStatus = "CLEARED"
+	// Player-initiated exits
+	ItemStatusCancelled ConsignmentItemStatus = "CANCELLED" // Player cancelled the item before review
+	ItemStatusWithdrawn ConsignmentItemStatus = "WITHDRAWN" // Player asked for an approved item back; off sale until returned
+	ItemStatusReturned  ConsignmentItemStatus = "RETURNED"  // Store handed a withdrawn or expired item back to the player
+	// Set by the expiry scheduler when the agreement period ends; the player re-lists or collects it
+	ItemStatusExpired ConsignmentItemStatus = "EXPIRED"
 )
 
 // Consignment corresponds to the "consignments" table (a request).
 type Consignment struct {
-	ID        int64                    `json:"id"`
-	PlayerID  int64                    `json:"player_id"`
-	StoreID   int64                    `json:"store_id"`
-	Status    ConsignmentRequestStatus `json:"status"`
-	Items     []ConsignmentItem        `json:"items,omitempty"` // Used for API responses
-	CreatedAt time.Time                `json:"created_at"`
-	UpdatedAt time.Time                `json:"updated_at"`
+	ID            int64                    `json:"id"`
+	PlayerID      int64                    `json:"player_id"`
+	StoreID       int64                    `json:"store_id"`
+	Status        ConsignmentRequestStatus `json:"status"`
+	AgreementDays int                      `json:"agreement_days"`  // Days an approved item stays on sale; 0 means no expiry
+	Items         []ConsignmentItem        `json:"items,omitempty"` // Used for API responses
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
 }
 
 // ConsignmentItem corresponds to the "consignment_items" table.
 type ConsignmentItem struct {
-	ID               int64                 `json:"id"`
-	ConsignmentID    int64                 `json:"consignment_id"`
-	CardID           int64                 `json:"card_id"`
-	Status           ConsignmentItemStatus `json:"status"`
-	RejectionReason  string                `json:"rejection_reason,omitempty"`
-	CreatedAt        time.Time             `json:"created_at"`
-	UpdatedAt        time.Time             `json:"updated_at"`
-}
\ No newline at end of file
+	ID              int64                  `json:"id"`
+	ConsignmentID   int64                  `json:"consignment_id"`
+	CardID          int64                  `json:"card_id"`
+	Quantity        int                    `json:"quantity"` // Copies of the card this item stands for
+	Status          ConsignmentItemStatus  `json:"status"`
+	RejectionReason string                 `json:"rejection_reason,omitempty"`
+	MinPrice        Money                  `json:"min_price,omitempty" swaggertype:"number"`    // Player's per-unit price floor; sales below it are refused
+	ListedPrice     Money                  `json:"listed_price,omitempty" swaggertype:"number"` // Per-unit price agreed through negotiation
+	ListedAt        *time.Time             `json:"listed_at,omitempty"`                         // When the item last went on sale or had a price agreed; automatic price drops count from here
+	ExpiresAt       *time.Time             `json:"expires_at,omitempty"`                        // End of the agreement period
+	PriceDrops      int                    `json:"price_drops,omitempty"`                       // Automatic price drops applied since listing
+	ConditionGrade                         // Recorded by the store on approval
+	SplitFromID     *int64                 `json:"split_from_id,omitempty"` // Item these units were split off by a partial approval or sale
+	Card            *Card                  `json:"card,omitempty"`          // Joined card details, used for API responses
+	Photos          []ConsignmentItemPhoto `json:"photos,omitempty"`        // Intake photos, used for API responses
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// PriceParty identifies which side of a consignment made a price proposal.
+type PriceParty string
+
+const (
+	PricePartyStore  PriceParty = "STORE"
+	PricePartyPlayer PriceParty = "PLAYER"
+)
+
+// PriceProposalStatus represents the state of a price proposal.
+type PriceProposalStatus string
+
+const (
+	ProposalStatusOpen       PriceProposalStatus = "OPEN"       // Waiting for the other side to accept or counter
+	ProposalStatusAccepted   PriceProposalStatus = "ACCEPTED"   // Became the item's listed price
+	ProposalStatusSuperseded PriceProposalStatus = "SUPERSEDED" // Replaced by a newer proposal or counter-offer
+)
+
+// PriceProposal corresponds to the "price_proposals" table: one offer in the price negotiation
+// between the store and the player for a consignment item.
+type PriceProposal struct {
+	ID                int64               `json:"id"`
+	ConsignmentItemID int64               `json:"consignment_item_id"`
+	ProposedBy        PriceParty          `json:"proposed_by"`
+	ProposerID        int64               `json:"proposer_id"`
+	Price             Money               `json:"price" swaggertype:"number"`
+	Status            PriceProposalStatus `json:"status"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+// WithdrawalFee corresponds to the "withdrawal_fees" table: the store's fee for handing a
+// withdrawn item back, deducted from the player's next settlement with that store.
+type WithdrawalFee struct {
+	ID                int64     `json:"id"`
+	ConsignmentItemID int64     `json:"consignment_item_id"`
+	PlayerID          int64     `json:"player_id"`
+	StoreID           int64     `json:"store_id"`
+	Amount            Money     `json:"amount" swaggertype:"number"`
+	SettlementID      *int64    `json:"settlement_id,omitempty"` // Set once a settlement has deducted the fee
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ConsignmentItemEvent corresponds to the "consignment_item_events" table: one row per
+// status change of an item, kept as an audit trail for dispute handling.
+type ConsignmentItemEvent struct {
+	ID                int64                 `json:"id"`
+	ConsignmentItemID int64                 `json:"consignment_item_id"`
+	ActorID           *int64                `json:"actor_id,omitempty"`    // User who caused the change; empty for system changes
+	FromStatus        ConsignmentItemStatus `json:"from_status,omitempty"` // Empty for the submission event
+	ToStatus          ConsignmentItemStatus `json:"to_status"`
+	Reason            string                `json:"reason,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+// itemTransitions lists, for every item status, the statuses it may move to next.
+// It is the single source of truth for the item lifecycle; any move not listed here is illegal.
+var itemTransitions = map[ConsignmentItemStatus][]ConsignmentItemStatus{
+	ItemStatusPending:   {ItemStatusApproved, ItemStatusRejected, ItemStatusCancelled},
+	ItemStatusApproved:  {ItemStatusSold, ItemStatusWithdrawn, ItemStatusExpired},
+	ItemStatusSold:      {ItemStatusCleared, ItemStatusApproved}, // Back on sale after a void or refund
+	ItemStatusCleared:   {ItemStatusApproved},                    // Refunded after settlement
+	ItemStatusWithdrawn: {ItemStatusReturned},
+	ItemStatusExpired:   {ItemStatusApproved, ItemStatusReturned},
+}
+
+// CanTransitionTo reports whether an item in status s may move to status next.
+func (s ConsignmentItemStatus) CanTransitionTo(next ConsignmentItemStatus) bool {
+	for _, allowed := range itemTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosed reports whether an item is done with: settled, or never on sale or back with the player.
+// A CLEARED item only moves again if the sale is refunded, which reopens its request.
+func (s ConsignmentItemStatus) isClosed() bool {
+	return s == ItemStatusCleared || len(itemTransitions[s]) == 0
+}
+
+// DeriveConsignmentRequestStatus computes a consignment request's status from the
+// statuses of its items:
+//   - PROCESSING while any item is still PENDING
+//   - ALL_REJECTED when every item was rejected
+//   - CLOSED when every item is settled (CLEARED), rejected, cancelled or returned
+//   - PARTIALLY_APPROVED when intake is done and some items were rejected
+//   - COMPLETED when intake is done and every item was accepted
+func DeriveConsignmentRequestStatus(itemStatuses []ConsignmentItemStatus) ConsignmentRequestStatus {
+	if len(itemStatuses) == 0 {
+		return ConsignmentRequestStatusProcessing
+	}
+
+	var rejected, closed int
+	for _, status := range itemStatuses {
+		if status == ItemStatusPending {
+			return ConsignmentRequestStatusProcessing
+		}
+		if status == ItemStatusRejected {
+			rejected++
+		}
+		if status.isClosed() {
+			closed++
+		}
+	}
+
+	switch {
+	case rejected == len(itemStatuses):
+		return ConsignmentRequestStatusAllRejected
+	case closed == len(itemStatuses):
+		return ConsignmentRequestStatusClosed
+	case rejected > 0:
+		return ConsignmentRequestStatusPartiallyApproved
+	default:
+		return ConsignmentRequestStatusCompleted
+	}
+}
+
+// ExpiryFor returns when an item listed at listedAt leaves sale under this consignment's
+// agreement, or nil when the agreement has no end.
+func (c *Consignment) ExpiryFor(listedAt time.Time) *time.Time {
+	if c.AgreementDays <= 0 {
+		return nil
+	}
+	expiresAt := listedAt.AddDate(0, 0, c.AgreementDays)
+	return &expiresAt
+}
+
+// DropPrice lowers a price by rate once per step, compounding, rounding each step like
+// SplitCommission. The result never goes below floor (0 means no floor).
+func DropPrice(price Money, rate Rate, steps int, floor Money) Money {
+	for i := 0; i < steps; i++ {
+		_, price = SplitCommission(price, rate)
+	}
+	if price < floor {
+		return floor
+	}
+	return price
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveConsignmentRequestStatus(t *testing.T) {
+	cases := []struct {
+		name  string
+		items []ConsignmentItemStatus
+		want  ConsignmentRequestStatus
+	}{
+		{"no items", nil, ConsignmentRequestStatusProcessing},
+		{"all pending", []ConsignmentItemStatus{ItemStatusPending, ItemStatusPending}, ConsignmentRequestStatusProcessing},
+		{"some still pending", []ConsignmentItemStatus{ItemStatusApproved, ItemStatusPending}, ConsignmentRequestStatusProcessing},
+		{"all approved", []ConsignmentItemStatus{ItemStatusApproved, ItemStatusSold}, ConsignmentRequestStatusCompleted},
+		{"some rejected", []ConsignmentItemStatus{ItemStatusApproved, ItemStatusRejected}, ConsignmentRequestStatusPartiallyApproved},
+		{"all rejected", []ConsignmentItemStatus{ItemStatusRejected, ItemStatusRejected}, ConsignmentRequestStatusAllRejected},
+		{"all cleared", []ConsignmentItemStatus{ItemStatusCleared, ItemStatusCleared}, ConsignmentRequestStatusClosed},
+		{"cleared or rejected", []ConsignmentItemStatus{ItemStatusCleared, ItemStatusRejected}, ConsignmentRequestStatusClosed},
+		{"cancelled or returned", []ConsignmentItemStatus{ItemStatusCancelled, ItemStatusReturned}, ConsignmentRequestStatusClosed},
+		{"withdrawal awaiting return", []ConsignmentItemStatus{ItemStatusWithdrawn, ItemStatusCleared}, ConsignmentRequestStatusCompleted},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, DeriveConsignmentRequestStatus(tc.items))
+		})
+	}
+}
+
+func TestConsignmentItemStatusCanTransitionTo(t *testing.T) {
+	statuses := []ConsignmentItemStatus{
+		ItemStatusPending, ItemStatusApproved, ItemStatusRejected, ItemStatusSold, ItemStatusCleared,
+		ItemStatusCancelled, ItemStatusWithdrawn, ItemStatusReturned, ItemStatusExpired,
+	}
+	allowed := map[[2]ConsignmentItemStatus]bool{
+		{ItemStatusPending, ItemStatusApproved}:   true,
+		{ItemStatusPending, ItemStatusRejected}:   true,
+		{ItemStatusPending, ItemStatusCancelled}:  true,
+		{ItemStatusApproved, ItemStatusSold}:      true,
+		{ItemStatusApproved, ItemStatusWithdrawn}: true,
+		{ItemStatusSold, ItemStatusCleared}:       true,
+		{ItemStatusSold, ItemStatusApproved}:      true,
+		{ItemStatusCleared, ItemStatusApproved}:   true,
+		{ItemStatusWithdrawn, ItemStatusReturned}: true,
+		{ItemStatusApproved, ItemStatusExpired}:   true,
+		{ItemStatusExpired, ItemStatusApproved}:   true,
+		{ItemStatusExpired, ItemStatusReturned}:   true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]ConsignmentItemStatus{from, to}]
+			assert.Equal(t, want, from.CanTransitionTo(to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestConsignmentExpiryFor(t *testing.T) {
+	listedAt := time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, (&Consignment{}).ExpiryFor(listedAt))
+
+	expiresAt := (&Consignment{AgreementDays: 30}).ExpiryFor(listedAt)
+	if assert.NotNil(t, expiresAt) {
+		assert.Equal(t, time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), *expiresAt)
+	}
+}
+
+func TestDropPrice(t *testing.T) {
+	cases := []struct {
+		name  string
+		steps int
+		floor Money
+		want  Money
+	}{
+		{"no steps", 0, 0, 10000},
+		{"one step", 1, 0, 9000},
+		{"steps compound", 2, 0, 8100},
+		{"stops at the floor", 3, 8500, 8500},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, DropPrice(10000, 1000, tc.steps, tc.floor))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// CreditEntryKind says why a store credit balance changed.
+type CreditEntryKind string
+
+const (
+	CreditEntryTopUp      CreditEntryKind = "TOP_UP"     // The store took payment and added credit
+	CreditEntrySpend      CreditEntryKind = "SPEND"      // Credit paid for a CREDIT sale
+	CreditEntryRefund     CreditEntryKind = "REFUND"     // A CREDIT sale was voided or refunded
+	CreditEntrySettlement CreditEntryKind = "SETTLEMENT" // A settlement was paid out as store credit
+)
+
+// CreditEntry corresponds to the "credit_ledger_entries" table: one change to a user's store credit
+// (儲值金) at one store. The ledger is append-only; a balance is the sum of its entries, and mistakes
+// are corrected with a new entry rather than by editing an old one.
+type CreditEntry struct {
+	ID            int64           `json:"id"`
+	StoreID       int64           `json:"store_id"`
+	UserID        int64           `json:"user_id"`
+	Kind          CreditEntryKind `json:"kind" enums:"TOP_UP,SPEND,REFUND,SETTLEMENT"`
+	Amount        Money           `json:"amount" swaggertype:"number"` // Positive adds credit, negative spends it
+	TransactionID *int64          `json:"transaction_id,omitempty"`    // Sale, void or refund the entry belongs to
+	SaleID        *int64          `json:"sale_id,omitempty"`           // Basket paid with credit
+	SettlementID  *int64          `json:"settlement_id,omitempty"`     // Settlement paid out as credit
+	ActorID       *int64          `json:"actor_id,omitempty"`          // User who made the change
+	Note          string          `json:"note,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// CreditBalance is a user's store credit at one store.
+type CreditBalance struct {
+	StoreID int64 `json:"store_id"`
+	UserID  int64 `json:"user_id"`
+	Balance Money `json:"balance" swaggertype:"number"`
+}
+
+// CreditAccount is a balance with the ledger entries behind it, newest first.
+type CreditAccount struct {
+	CreditBalance
+	Entries []CreditEntry `json:"entries"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// StoreEarnings sums up a player's consignments at one store, from intake to payout.
+type StoreEarnings struct {
+	StoreID        int64  `json:"store_id"`
+	StoreName      string `json:"store_name"`
+	PendingUnits   int    `json:"pending_units"`                        // Units awaiting the store's intake review
+	ListedUnits    int    `json:"listed_units"`                         // Units on sale
+	ListedValue    Money  `json:"listed_value" swaggertype:"number"`    // Units on sale at their listed price; units without one count as 0
+	UnsettledSales Money  `json:"unsettled_sales" swaggertype:"number"` // Total of the sales not yet settled
+	UnsettledShare Money  `json:"unsettled_share" swaggertype:"number"` // Player's share of those sales at the rate recorded at sale time
+	SettledShare   Money  `json:"settled_share" swaggertype:"number"`   // Player's share of the settled sales
+	PaidOut        Money  `json:"paid_out" swaggertype:"number"`        // Paid out by settlements, after fees and adjustments
+}
+
+// SaleEarning is one sale of a player's consigned card with the player's share of it. Settled
+// sales are split as their settlement paid them; the others at the rate recorded at sale time.
+type SaleEarning struct {
+	TransactionID     int64         `json:"transaction_id"`
+	StoreID           int64         `json:"store_id"`
+	ConsignmentItemID int64         `json:"consignment_item_id"`
+	CardName          string        `json:"card_name"`
+	Price             Money         `json:"price" swaggertype:"number"` // Unit price
+	Quantity          int           `json:"quantity"`
+	PaymentMethod     PaymentMethod `json:"payment_method"`
+	CommissionRate    Rate          `json:"commission_rate" swaggertype:"number"`
+	GrossAmount       Money         `json:"gross_amount" swaggertype:"number"`
+	CommissionAmount  Money         `json:"commission_amount" swaggertype:"number"`
+	NetAmount         Money         `json:"net_amount" swaggertype:"number"`
+	SettlementID      *int64        `json:"settlement_id,omitempty"` // Set once the sale has been settled
+	SoldAt            time.Time     `json:"sold_at"`
+}
+
+// PlayerEarnings is a player's earnings overview: totals per store and a page of their sales.
+type PlayerEarnings struct {
+	Stores []StoreEarnings `json:"stores"`
+	Sales  []SaleEarning   `json:"sales"`
+}
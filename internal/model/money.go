@@ -0,0 +1,238 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact currency amount stored as an integer number of cents.
+// It maps to the NUMERIC(…,2) columns in the database and is encoded in JSON
+// as a plain decimal number (e.g. 120.50), so no floating point is ever involved.
+type Money int64
+
+// Rate is a percentage with two decimal places (e.g. 12.50%), stored as
+// hundredths of a percent. It maps to the NUMERIC(5,2) commission columns.
+type Rate int64
+
+// moneyScale is the number of minor units in one major unit for both Money and Rate.
+const moneyScale = 100
+
+// ParseMoney parses a decimal string such as "120.5" into Money.
+// Digits beyond the second decimal place are rounded half away from zero.
+func ParseMoney(s string) (Money, error) {
+	v, err := parseFixed(s)
+	return Money(v), err
+}
+
+// String formats the amount with exactly two decimal places.
+func (m Money) String() string {
+	return formatFixed(int64(m))
+}
+
+// Cents returns the amount as an integer number of cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Mul returns the amount multiplied by n, e.g. a unit price times a quantity.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number (120.5) or a numeric string ("120.50").
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data)
+	if err != nil {
+		return fmt.Errorf("invalid money amount: %w", err)
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns.
+func (m *Money) Scan(src interface{}) error {
+	v, err := scanFixed(src)
+	if err != nil {
+		return fmt.Errorf("cannot scan money: %w", err)
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Value implements driver.Valuer; the amount is sent as an exact decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// ParseRate parses a percentage string such as "12.5" into a Rate.
+func ParseRate(s string) (Rate, error) {
+	v, err := parseFixed(s)
+	return Rate(v), err
+}
+
+// String formats the rate with exactly two decimal places, without a percent sign.
+func (r Rate) String() string {
+	return formatFixed(int64(r))
+}
+
+// Valid reports whether the rate lies between 0% and 100% inclusive.
+func (r Rate) Valid() bool {
+	return r >= 0 && r <= 100*moneyScale
+}
+
+// MarshalJSON encodes the rate as a JSON number with two decimal places.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number (12.5) or a numeric string ("12.50").
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data)
+	if err != nil {
+		return fmt.Errorf("invalid rate: %w", err)
+	}
+	*r = Rate(v)
+	return nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns.
+func (r *Rate) Scan(src interface{}) error {
+	v, err := scanFixed(src)
+	if err != nil {
+		return fmt.Errorf("cannot scan rate: %w", err)
+	}
+	*r = Rate(v)
+	return nil
+}
+
+// Value implements driver.Valuer; the rate is sent as an exact decimal string.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// SplitCommission divides a sale price between the store and the player.
+//
+// Rounding rule: the store's commission is price × rate rounded to the nearest
+// cent, with exact halves rounded away from zero. The player's share is the
+// remainder, so commission + share always equals the price to the cent and
+// summing per-sale shares never drifts from the total.
+func SplitCommission(price Money, rate Rate) (commission, playerShare Money) {
+	commission = Money(divRound(int64(price)*int64(rate), 100*moneyScale))
+	return commission, price - commission
+}
+
+// divRound divides a by b (b > 0), rounding halves away from zero.
+func divRound(a, b int64) int64 {
+	q, r := a/b, a%b
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+// parseFixed parses a decimal string into an integer scaled by moneyScale.
+func parseFixed(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty decimal")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid decimal %q", s)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("invalid decimal %q", s)
+			}
+		}
+	}
+
+	var units int64
+	if intPart != "" {
+		v, err := strconv.ParseInt(intPart, 10, 64)
+		if err != nil || v > (1<<62)/moneyScale {
+			return 0, fmt.Errorf("decimal %q out of range", s)
+		}
+		units = v * moneyScale
+	}
+
+	// Take the first two fractional digits and round on the third.
+	padded := fracPart + "000"
+	cents, _ := strconv.ParseInt(padded[:2], 10, 64)
+	units += cents
+	if padded[2] >= '5' {
+		units++
+	}
+
+	if negative {
+		units = -units
+	}
+	return units, nil
+}
+
+// formatFixed formats an integer scaled by moneyScale as a decimal string.
+func formatFixed(v int64) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign = "-"
+		u = uint64(-v)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/moneyScale, u%moneyScale)
+}
+
+func unmarshalFixed(data []byte) (int64, error) {
+	if string(data) == "null" {
+		return 0, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, fmt.Errorf("expected a number")
+		}
+		return parseFixed(s)
+	}
+	return parseFixed(n.String())
+}
+
+func scanFixed(src interface{}) (int64, error) {
+	switch v := src.(type) {
+	case []byte:
+		return parseFixed(string(v))
+	case string:
+		return parseFixed(v)
+	case int64:
+		return v * moneyScale, nil
+	case nil:
+		return 0, fmt.Errorf("unexpected NULL")
+	default:
+		return 0, fmt.Errorf("unsupported type %T", src)
+	}
+}
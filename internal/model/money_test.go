@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"120", 12000},
+		{"120.5", 12050},
+		{"120.50", 12050},
+		{".05", 5},
+		{"-3.10", -310},
+		{"0.004", 0},
+		{"0.005", 1},
+		{"-0.005", -1},
+		{"99999999.99", 9999999999},
+	}
+	for _, tc := range cases {
+		got, err := ParseMoney(tc.in)
+		assert.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, got, tc.in)
+	}
+
+	for _, bad := range []string{"", "abc", "1.2.3", "1e5", "-", "."} {
+		_, err := ParseMoney(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", Money(0).String())
+	assert.Equal(t, "120.50", Money(12050).String())
+	assert.Equal(t, "-0.05", Money(-5).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Money `json:"price"`
+	}{Price: 12050})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price": 120.50}`, string(data))
+
+	var v struct {
+		Price Money `json:"price"`
+		Rate  Rate  `json:"rate"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": 0.1, "rate": "12.5"}`), &v))
+	assert.Equal(t, Money(10), v.Price)
+	assert.Equal(t, Rate(1250), v.Rate)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": true}`), &v))
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m Money
+	assert.NoError(t, m.Scan([]byte("1234.56")))
+	assert.Equal(t, Money(123456), m)
+
+	assert.NoError(t, m.Scan(int64(7)))
+	assert.Equal(t, Money(700), m)
+
+	assert.Error(t, m.Scan(nil))
+
+	v, err := Money(123456).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.56", v)
+}
+
+func TestRate_Valid(t *testing.T) {
+	assert.True(t, Rate(0).Valid())
+	assert.True(t, Rate(10000).Valid())
+	assert.False(t, Rate(10001).Valid())
+	assert.False(t, Rate(-1).Valid())
+}
+
+func TestSplitCommission(t *testing.T) {
+	cases := []struct {
+		name       string
+		price      Money
+		rate       Rate
+		commission Money
+	}{
+		{"whole amounts", 10000, 1000, 1000},          // 100.00 at 10%
+		{"fractional rate", 33300, 1250, 4163},        // 333.00 at 12.5% = 41.625
+		{"half cent rounds up", 50, 1000, 5},          // 0.50 at 10% = 0.05
+		{"exact half away from zero", 5, 1000, 1},     // 0.05 at 10% = 0.005
+		{"just below half rounds down", 4, 1000, 0},   // 0.04 at 10% = 0.004
+		{"refund rounds symmetrically", -5, 1000, -1}, // -0.05 at 10%
+		{"zero commission", 9999, 0, 0},
+		{"full commission", 9999, 10000, 9999},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			commission, share := SplitCommission(tc.price, tc.rate)
+			assert.Equal(t, tc.commission, commission)
+			assert.Equal(t, tc.price, commission+share)
+		})
+	}
+}
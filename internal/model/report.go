@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// ReportPeriod is the length of the buckets a sales report is grouped into.
+type ReportPeriod string
+
+const (
+	ReportPeriodDay   ReportPeriod = "day"
+	ReportPeriodWeek  ReportPeriod = "week" // Weeks start on Monday
+	ReportPeriodMonth ReportPeriod = "month"
+)
+
+// Valid reports whether p is one of the known periods.
+func (p ReportPeriod) Valid() bool {
+	switch p {
+	case ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth:
+		return true
+	}
+	return false
+}
+
+// SalesPeriodReport sums up a store's sales in one day, week or month. Refunds count
+// against the period they were made in; voided sales are left out.
+type SalesPeriodReport struct {
+	PeriodStart time.Time `json:"period_start"`
+	Sales       int       `json:"sales"`   // Number of sales
+	Refunds     int       `json:"refunds"` // Number of refunds
+	Units       int       `json:"units"`   // Units sold less units refunded
+	Gross       Money     `json:"gross" swaggertype:"number"`
+	Commission  Money     `json:"commission" swaggertype:"number"`   // Kept by the store
+	PlayerShare Money     `json:"player_share" swaggertype:"number"` // Owed or paid to the players
+}
+
+// CommissionReport is the commission a store earned on sales paid with one payment method.
+type CommissionReport struct {
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	Sales         int           `json:"sales"`
+	Gross         Money         `json:"gross" swaggertype:"number"`
+	Commission    Money         `json:"commission" swaggertype:"number"`
+}
+
+// PlayerLiability is what a store owes one player: the share of sales not settled yet, less
+// the fees and refund adjustments the next settlement will take back, plus settlements
+// requested but not paid out.
+type PlayerLiability struct {
+	PlayerID       int64  `json:"player_id"`
+	PlayerEmail    string `json:"player_email"`
+	UnsettledSales Money  `json:"unsettled_sales" swaggertype:"number"`
+	UnsettledShare Money  `json:"unsettled_share" swaggertype:"number"` // At the rate recorded at sale time
+	Deductions     Money  `json:"deductions" swaggertype:"number"`      // Outstanding withdrawal fees and refund adjustments
+	PendingPayouts Money  `json:"pending_payouts" swaggertype:"number"` // Requested settlements not yet completed
+	Owed           Money  `json:"owed" swaggertype:"number"`
+}
+
+// TotalOwed works out Owed from the other amounts. Deductions are only ever taken out of a
+// payout, so they can bring the unsettled share down to zero but not below.
+func (l *PlayerLiability) TotalOwed() Money {
+	unsettled := l.UnsettledShare - l.Deductions
+	if unsettled < 0 {
+		unsettled = 0
+	}
+	return unsettled + l.PendingPayouts
+}
+
+// TopSellerGrouping is what a top sellers report ranks: single cards or whole series.
+type TopSellerGrouping string
+
+const (
+	TopSellersByCard   TopSellerGrouping = "card"
+	TopSellersBySeries TopSellerGrouping = "series"
+)
+
+// Valid reports whether g is one of the known groupings.
+func (g TopSellerGrouping) Valid() bool {
+	return g == TopSellersByCard || g == TopSellersBySeries
+}
+
+// TopSeller is a card, or a series, ranked by units sold. Refunded and voided sales are left out.
+type TopSeller struct {
+	CardID   *int64 `json:"card_id,omitempty"` // Not set when ranking series
+	CardName string `json:"card_name,omitempty"`
+	Series   string `json:"series"`
+	Units    int    `json:"units"`
+	Sales    int    `json:"sales"`
+	Gross    Money  `json:"gross" swaggertype:"number"`
+}
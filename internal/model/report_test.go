@@ -0,0 +1,15 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerLiabilityTotalOwed(t *testing.T) {
+	liability := &PlayerLiability{UnsettledShare: 9000, Deductions: 1000, PendingPayouts: 5000}
+	assert.Equal(t, Money(13000), liability.TotalOwed())
+
+	liability = &PlayerLiability{UnsettledShare: 500, Deductions: 1000, PendingPayouts: 5000}
+	assert.Equal(t, Money(5000), liability.TotalOwed(), "deductions never eat into requested payouts")
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Sale corresponds to the "sales" table: one basket sold at the counter. Each consignment item
+// sold in it is a Transaction line, and the buyer may pay with several tenders.
+type Sale struct {
+	ID            int64         `json:"id"`
+	StoreID       int64         `json:"store_id"`
+	ReceiptNumber string        `json:"receipt_number"`
+	Total         Money         `json:"total" swaggertype:"number"`
+	CashierID     int64         `json:"cashier_id"`
+	BuyerID       *int64        `json:"buyer_id,omitempty"` // Store credit account charged for the CREDIT tenders
+	Lines         []Transaction `json:"lines,omitempty"`    // Used for API responses
+	Tenders       []SaleTender  `json:"tenders,omitempty"`  // Used for API responses
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// SaleTender corresponds to the "sale_tenders" table: the part of a sale paid with one method.
+type SaleTender struct {
+	ID            int64         `json:"id"`
+	SaleID        int64         `json:"sale_id"`
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	Amount        Money         `json:"amount" swaggertype:"number"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Session corresponds to the "sessions" table: one login, kept alive by refreshing its token.
+// Only hashes of refresh tokens are stored.
+type Session struct {
+	ID                int64      `json:"id"`
+	UserID            int64      `json:"user_id"`
+	RefreshTokenHash  string     `json:"-"`
+	PreviousTokenHash string     `json:"-"` // Replaced by the last refresh, empty before the first
+	ExpiresAt         time.Time  `json:"expires_at"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty"` // Time of the last refresh
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// Active reports whether the session can still be used at the given time.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
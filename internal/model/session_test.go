@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionActive(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	session := Session{ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, session.Active(now))
+	assert.False(t, session.Active(now.Add(time.Hour)), "expires at ExpiresAt")
+
+	revokedAt := now.Add(-time.Minute)
+	session.RevokedAt = &revokedAt
+	assert.False(t, session.Active(now))
+}
@@ -12,11 +12,55 @@ const (
 
 // Settlement corresponds to the "settlements" table in the database.
 type Settlement struct {
-	ID        int64            `json:"id"`
-	PlayerID  int64            `json:"player_id"`
-	StoreID   int64            `json:"store_id"`
-	Amount    float64          `json:"amount"`
-	Status    SettlementStatus `json:"status"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID                  int64            `json:"id"`
+	PlayerID            int64            `json:"player_id"`
+	StoreID             int64            `json:"store_id"`
+	PayoutMethod        PaymentMethod    `json:"payout_method"`                             // How the player is paid: CASH, or into their store credit
+	CommissionRate      Rate             `json:"commission_rate" swaggertype:"number"`      // Store's base rate for the payout method when requested
+	GrossAmount         Money            `json:"gross_amount" swaggertype:"number"`         // Total of the sales settled
+	CommissionAmount    Money            `json:"commission_amount" swaggertype:"number"`    // Store's commission at CommissionRate, or at the schedule rule a sale was charged under
+	NetAmount           Money            `json:"net_amount" swaggertype:"number"`           // Player's share: gross less commission, before deductions
+	Amount              Money            `json:"amount" swaggertype:"number"`               // Net payout after fees
+	FeesDeducted        Money            `json:"fees_deducted" swaggertype:"number"`        // Withdrawal fees taken out of this payout
+	AdjustmentsDeducted Money            `json:"adjustments_deducted" swaggertype:"number"` // Shares of refunded sales taken back from this payout
+	Status              SettlementStatus `json:"status"`
+	RequestedAt         time.Time        `json:"requested_at"`
+	CompletedAt         *time.Time       `json:"completed_at,omitempty"`
+	CompletedBy         *int64           `json:"completed_by,omitempty"` // Staff member who paid it out; none for credit payouts
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// SettlementAdjustment corresponds to the "settlement_adjustments" table: the player's share of a
+// sale that was refunded after it had been settled. The store takes it back from the player's next
+// settlement with that store.
+type SettlementAdjustment struct {
+	ID            int64     `json:"id"`
+	TransactionID int64     `json:"transaction_id"` // The refund that caused the adjustment
+	PlayerID      int64     `json:"player_id"`
+	StoreID       int64     `json:"store_id"`
+	Amount        Money     `json:"amount" swaggertype:"number"`
+	SettlementID  *int64    `json:"settlement_id,omitempty"` // Set once a settlement has deducted the adjustment
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SettlementLine corresponds to the "settlement_transactions" table: a sale paid by a settlement,
+// split at the settlement's commission rate. The item, price, quantity and sale time come from the sale.
+type SettlementLine struct {
+	ID                int64     `json:"id"`
+	SettlementID      int64     `json:"settlement_id"`
+	TransactionID     int64     `json:"transaction_id"`
+	ConsignmentItemID int64     `json:"consignment_item_id"`
+	Price             Money     `json:"price" swaggertype:"number"` // Unit price of the sale
+	Quantity          int       `json:"quantity"`
+	GrossAmount       Money     `json:"gross_amount" swaggertype:"number"`
+	CommissionAmount  Money     `json:"commission_amount" swaggertype:"number"`
+	NetAmount         Money     `json:"net_amount" swaggertype:"number"` // Player's share of the sale
+	SoldAt            time.Time `json:"sold_at"`
+}
+
+// SettlementDetail is a settlement with the sales it paid.
+type SettlementDetail struct {
+	Settlement
+	Lines []SettlementLine `json:"lines"`
 }
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// StoreRole is a user's role at the store they work at.
+type StoreRole string
+
+const (
+	StoreRoleOwner       StoreRole = "OWNER" // The user who registered the store
+	StoreRoleManager     StoreRole = "MANAGER"
+	StoreRoleCashier     StoreRole = "CASHIER"
+	StoreRoleIntakeClerk StoreRole = "INTAKE_CLERK"
+)
+
+// StorePermission is something a store member may be allowed to do on the store's behalf.
+type StorePermission string
+
+const (
+	PermissionView        StorePermission = "VIEW"         // See the store's cards, consignments, sales, settlements and credit
+	PermissionManageCards StorePermission = "MANAGE_CARDS" // Add, edit and delete catalogue cards
+	PermissionIntake      StorePermission = "INTAKE"       // Review consignment items, take intake photos, negotiate prices and hand items back
+	PermissionSell        StorePermission = "SELL"         // Ring up and void sales, and top up store credit
+	PermissionRefund      StorePermission = "REFUND"       // Refund sales
+	PermissionSettle      StorePermission = "SETTLE"       // Pay out settlements
+	PermissionViewReports StorePermission = "VIEW_REPORTS" // Run the store reports
+	PermissionManageStore StorePermission = "MANAGE_STORE" // Edit the store profile, rates and commission schedules
+	PermissionManageStaff StorePermission = "MANAGE_STAFF" // Invite, change and remove staff
+)
+
+// rolePermissions lists what each staff role may do. Owners may do everything.
+var rolePermissions = map[StoreRole][]StorePermission{
+	StoreRoleManager: {
+		PermissionView, PermissionManageCards, PermissionIntake, PermissionSell,
+		PermissionRefund, PermissionSettle, PermissionViewReports,
+	},
+	StoreRoleCashier:     {PermissionView, PermissionSell},
+	StoreRoleIntakeClerk: {PermissionView, PermissionManageCards, PermissionIntake},
+}
+
+// Can reports whether the role grants the permission.
+func (r StoreRole) Can(permission StorePermission) bool {
+	if r == StoreRoleOwner {
+		return true
+	}
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether r is a role an owner can give to staff.
+func (r StoreRole) IsStaff() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// StoreMemberStatus represents where a store membership stands.
+type StoreMemberStatus string
+
+const (
+	MemberStatusInvited  StoreMemberStatus = "INVITED" // Waiting for the user to accept
+	MemberStatusActive   StoreMemberStatus = "ACTIVE"
+	MemberStatusDeclined StoreMemberStatus = "DECLINED"
+	MemberStatusRemoved  StoreMemberStatus = "REMOVED" // Taken off the staff by the owner, or left
+)
+
+// StoreMember corresponds to the "store_members" table: a user's membership of a store's staff.
+// A user is an active member of at most one store; the owner is a member with the OWNER role.
+type StoreMember struct {
+	ID         int64             `json:"id"`
+	StoreID    int64             `json:"store_id"`
+	UserID     int64             `json:"user_id"`
+	Email      string            `json:"email,omitempty"` // Joined from the user, used for API responses
+	Role       StoreRole         `json:"role" enums:"OWNER,MANAGER,CASHIER,INTAKE_CLERK"`
+	Status     StoreMemberStatus `json:"status" enums:"INVITED,ACTIVE,DECLINED,REMOVED"`
+	InvitedBy  *int64            `json:"invited_by,omitempty"`
+	AcceptedAt *time.Time        `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreRoleCan(t *testing.T) {
+	assert.True(t, StoreRoleOwner.Can(PermissionManageStaff))
+	assert.True(t, StoreRoleOwner.Can(PermissionManageStore))
+
+	assert.True(t, StoreRoleManager.Can(PermissionRefund))
+	assert.True(t, StoreRoleManager.Can(PermissionSettle))
+	assert.False(t, StoreRoleManager.Can(PermissionManageStore))
+	assert.False(t, StoreRoleManager.Can(PermissionManageStaff))
+
+	assert.True(t, StoreRoleCashier.Can(PermissionSell))
+	assert.False(t, StoreRoleCashier.Can(PermissionRefund), "refunds need a manager")
+	assert.False(t, StoreRoleCashier.Can(PermissionIntake))
+
+	assert.True(t, StoreRoleIntakeClerk.Can(PermissionIntake))
+	assert.True(t, StoreRoleIntakeClerk.Can(PermissionManageCards))
+	assert.False(t, StoreRoleIntakeClerk.Can(PermissionSell))
+
+	for _, role := range []StoreRole{StoreRoleOwner, StoreRoleManager, StoreRoleCashier, StoreRoleIntakeClerk} {
+		assert.True(t, role.Can(PermissionView), role)
+	}
+	assert.False(t, StoreRole("JANITOR").Can(PermissionView))
+}
+
+func TestStoreRoleIsStaff(t *testing.T) {
+	assert.True(t, StoreRoleManager.IsStaff())
+	assert.True(t, StoreRoleCashier.IsStaff())
+	assert.True(t, StoreRoleIntakeClerk.IsStaff())
+	assert.False(t, StoreRoleOwner.IsStaff(), "ownership cannot be handed out")
+	assert.False(t, StoreRole("").IsStaff())
+}
@@ -2,13 +2,117 @@ package model
 
 import "time"
 
+// StoreStatus represents whether a store is currently operating on the platform.
+type StoreStatus string
+
+const (
+	StoreStatusPendingReview StoreStatus = "PENDING_REVIEW" // Registered, waiting for an admin
+	StoreStatusActive        StoreStatus = "ACTIVE"
+	StoreStatusInactive      StoreStatus = "INACTIVE"
+	StoreStatusRejected      StoreStatus = "REJECTED"
+	StoreStatusSuspended     StoreStatus = "SUSPENDED"
+)
+
+// storeTransitions lists the status changes admins can make when reviewing stores.
+var storeTransitions = map[StoreStatus][]StoreStatus{
+	StoreStatusPendingReview: {StoreStatusActive, StoreStatusRejected},
+	StoreStatusRejected:      {StoreStatusActive}, // Approved after all
+	StoreStatusActive:        {StoreStatusSuspended},
+	StoreStatusSuspended:     {StoreStatusActive}, // Reinstated
+}
+
+// CanTransitionTo reports whether a store in status s may be moved to status next.
+func (s StoreStatus) CanTransitionTo(next StoreStatus) bool {
+	for _, allowed := range storeTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Store corresponds to the "stores" table in the database.
 type Store struct {
-	ID               int64     `json:"id"`
-	UserID           int64     `json:"user_id"`
-	Name             string    `json:"name"`
-	CommissionCash   float64   `json:"commission_cash"`
-	CommissionCredit float64   `json:"commission_credit"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               int64       `json:"id"`
+	UserID           int64       `json:"user_id"`
+	Name             string      `json:"name"`
+	Address          string      `json:"address"`
+	City             string      `json:"city"`
+	Phone            string      `json:"phone"`
+	Email            string      `json:"email"`
+	OpeningHours     string      `json:"opening_hours"` // Free text, e.g. "Mon-Fri 12:00-21:00"
+	CommissionCash   Rate        `json:"commission_cash" swaggertype:"number"`
+	CommissionCredit Rate        `json:"commission_credit" swaggertype:"number"`
+	WithdrawalFee    Money       `json:"withdrawal_fee" swaggertype:"number"`        // Charged per withdrawn item, 0 for none
+	AgreementDays    int         `json:"agreement_days"`                             // Days an approved item stays on sale, 0 for no expiry
+	PriceDropDays    int         `json:"price_drop_days"`                            // Lower the listed price every N days on sale, 0 for never
+	PriceDropRate    Rate        `json:"price_drop_rate" swaggertype:"number"`       // Percentage taken off the listed price at each drop
+	MinSettlement    Money       `json:"min_settlement_amount" swaggertype:"number"` // Smallest player share a settlement may pay out, 0 for none
+	Status           StoreStatus `json:"status"`
+	StatusReason     string      `json:"status_reason,omitempty"` // Why the store was rejected or suspended
+	ReviewedBy       *int64      `json:"reviewed_by,omitempty"`   // Admin who last changed the status
+	ReviewedAt       *time.Time  `json:"reviewed_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// StoreProfile is what players see of a store in the store directory: where to find it and
+// the terms it offers consignors, without its owner or review details.
+type StoreProfile struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	Address          string `json:"address"`
+	City             string `json:"city"`
+	Phone            string `json:"phone"`
+	Email            string `json:"email"`
+	OpeningHours     string `json:"opening_hours"`
+	CommissionCash   Rate   `json:"commission_cash" swaggertype:"number"`
+	CommissionCredit Rate   `json:"commission_credit" swaggertype:"number"`
+	WithdrawalFee    Money  `json:"withdrawal_fee" swaggertype:"number"`
+	AgreementDays    int    `json:"agreement_days"`
+	PriceDropDays    int    `json:"price_drop_days"`
+	PriceDropRate    Rate   `json:"price_drop_rate" swaggertype:"number"`
+	MinSettlement    Money  `json:"min_settlement_amount" swaggertype:"number"`
+}
+
+// Profile returns the store's public profile.
+func (s *Store) Profile() StoreProfile {
+	return StoreProfile{
+		ID:               s.ID,
+		Name:             s.Name,
+		Address:          s.Address,
+		City:             s.City,
+		Phone:            s.Phone,
+		Email:            s.Email,
+		OpeningHours:     s.OpeningHours,
+		CommissionCash:   s.CommissionCash,
+		CommissionCredit: s.CommissionCredit,
+		WithdrawalFee:    s.WithdrawalFee,
+		AgreementDays:    s.AgreementDays,
+		PriceDropDays:    s.PriceDropDays,
+		PriceDropRate:    s.PriceDropRate,
+		MinSettlement:    s.MinSettlement,
+	}
+}
+
+// IsActive reports whether the store is open for business.
+func (s *Store) IsActive() bool {
+	return s.Status == StoreStatusActive
+}
+
+// CommissionFor returns the store's commission rate on sales paid with the given method.
+func (s *Store) CommissionFor(method PaymentMethod) Rate {
+	if method == PaymentMethodCash {
+		return s.CommissionCash
+	}
+	return s.CommissionCredit
+}
+
+// PriceDropsDue returns how many automatic price drops an item listed at listedAt
+// should have received by now under the store's policy.
+func (s *Store) PriceDropsDue(listedAt, now time.Time) int {
+	if s.PriceDropDays <= 0 || s.PriceDropRate <= 0 || now.Before(listedAt) {
+		return 0
+	}
+	return int(now.Sub(listedAt) / (time.Duration(s.PriceDropDays) * 24 * time.Hour))
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorePriceDropsDue(t *testing.T) {
+	listedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &Store{PriceDropDays: 7, PriceDropRate: 1000}
+
+	assert.Equal(t, 0, store.PriceDropsDue(listedAt, listedAt.AddDate(0, 0, 6)))
+	assert.Equal(t, 1, store.PriceDropsDue(listedAt, listedAt.AddDate(0, 0, 7)))
+	assert.Equal(t, 2, store.PriceDropsDue(listedAt, listedAt.AddDate(0, 0, 20)))
+	assert.Equal(t, 0, store.PriceDropsDue(listedAt, listedAt.AddDate(0, 0, -1)))
+
+	assert.Equal(t, 0, (&Store{PriceDropDays: 7}).PriceDropsDue(listedAt, listedAt.AddDate(0, 0, 30)), "no rate, no drops")
+}
+
+func TestStoreStatusCanTransitionTo(t *testing.T) {
+	assert.True(t, StoreStatusPendingReview.CanTransitionTo(StoreStatusActive))
+	assert.True(t, StoreStatusPendingReview.CanTransitionTo(StoreStatusRejected))
+	assert.True(t, StoreStatusRejected.CanTransitionTo(StoreStatusActive))
+	assert.True(t, StoreStatusActive.CanTransitionTo(StoreStatusSuspended))
+	assert.True(t, StoreStatusSuspended.CanTransitionTo(StoreStatusActive))
+
+	assert.False(t, StoreStatusPendingReview.CanTransitionTo(StoreStatusSuspended))
+	assert.False(t, StoreStatusActive.CanTransitionTo(StoreStatusRejected))
+	assert.False(t, StoreStatusActive.CanTransitionTo(StoreStatusActive))
+	assert.False(t, StoreStatusInactive.CanTransitionTo(StoreStatusActive))
+}
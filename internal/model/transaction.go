@@ -10,13 +10,76 @@ const (
 	PaymentMethodCredit PaymentMethod = "CREDIT"
 )
 
+// Valid reports whether m is a known payment method.
+func (m PaymentMethod) Valid() bool {
+	return m == PaymentMethodCash || m == PaymentMethodCredit
+}
+
+// TransactionType tells a sale apart from the refund that reverses it.
+type TransactionType string
+
+const (
+	TransactionTypeSale   TransactionType = "SALE"
+	TransactionTypeRefund TransactionType = "REFUND" // Reverses a sale: same unit price, negative quantity
+)
+
 // Transaction corresponds to the "transactions" table in the database.
 type Transaction struct {
 	ID             int64         `json:"id"`
 	ConsignmentItemID  int64         `json:"consignment_item_id"`
+	SaleID         *int64        `json:"sale_id,omitempty"` // The basket the line was sold in, if any
 	StoreID        int64         `json:"store_id"`
-	Price          float64       `json:"price"`
+	Type           TransactionType `json:"type" enums:"SALE,REFUND"`
+	ReversesID     *int64        `json:"reverses_id,omitempty"` // For refunds, the sale being reversed
+	RefundID       *int64        `json:"refund_id,omitempty"`   // For refunded sales, the reversing transaction
+	Price          Money         `json:"price" swaggertype:"number"` // Unit price
+	Quantity       int           `json:"quantity"`                   // Negative for refunds
 	PaymentMethod  PaymentMethod `json:"payment_method"`
-	CommissionRate float64       `json:"commission_rate"`
+	CommissionRate Rate          `json:"commission_rate" swaggertype:"number"`
+	CommissionRuleID *int64      `json:"commission_rule_id,omitempty"` // Schedule rule the rate came from; nil for the store's base rate
+	ConditionGrade               // The item's condition at the time of sale
+	ActorID        *int64        `json:"actor_id,omitempty"` // User who rang up the sale or the refund
+	BuyerID        *int64        `json:"buyer_id,omitempty"` // Store credit account charged, for CREDIT sales
+	Reason         string        `json:"reason,omitempty"`   // Why a refund was given
+	VoidedAt       *time.Time    `json:"voided_at,omitempty"`
+	VoidedBy       *int64        `json:"voided_by,omitempty"`
+	VoidReason     string        `json:"void_reason,omitempty"`
 	CreatedAt      time.Time     `json:"created_at"`
+	Warnings       []string      `json:"warnings,omitempty"` // Not persisted; set on the sale response, e.g. when sold below the listed price
+}
+
+// Total returns the amount paid for the sale: the unit price times the quantity.
+func (t *Transaction) Total() Money {
+	return t.Price.Mul(int64(t.Quantity))
+}
+
+// Split divides the sale between the store and the player. The commission is worked out on a
+// single unit and multiplied by the quantity, so selling several copies at once pays out exactly
+// what selling them one by one would.
+func (t *Transaction) Split() (commission, playerShare Money) {
+	return t.SplitAt(t.CommissionRate)
+}
+
+// SplitAt divides the sale like Split, but at the given commission rate instead of the one
+// recorded at sale time, e.g. when the player is paid out in store credit.
+func (t *Transaction) SplitAt(rate Rate) (commission, playerShare Money) {
+	commission, playerShare = SplitCommission(t.Price, rate)
+	return commission.Mul(int64(t.Quantity)), playerShare.Mul(int64(t.Quantity))
+}
+
+// IsReversed reports whether the sale was voided or refunded, so it no longer counts.
+func (t *Transaction) IsReversed() bool {
+	return t.VoidedAt != nil || t.RefundID != nil
+}
+
+// CanVoid reports whether the sale may still be voided at now: voids are for correcting the
+// till on the day of the sale, so only a sale rung up on the same calendar day qualifies.
+// Whether it has been settled depends on the item and is checked by the caller.
+func (t *Transaction) CanVoid(now time.Time) bool {
+	if t.Type != TransactionTypeSale || t.IsReversed() {
+		return false
+	}
+	y1, m1, d1 := t.CreatedAt.In(now.Location()).Date()
+	y2, m2, d2 := now.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
 }
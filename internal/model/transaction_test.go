@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionSplit(t *testing.T) {
+	// 0.05 per unit at 10%: each unit's commission rounds up to 0.01
+	tx := Transaction{Price: 5, Quantity: 12, CommissionRate: 1000}
+
+	commission, share := tx.Split()
+	assert.Equal(t, Money(12), commission, "commission is rounded per unit, not on the 0.60 total")
+	assert.Equal(t, Money(48), share)
+	assert.Equal(t, tx.Total(), commission+share)
+
+	// Selling the units one at a time pays the player the same
+	single := Transaction{Price: 5, Quantity: 1, CommissionRate: 1000}
+	_, unitShare := single.Split()
+	assert.Equal(t, share, unitShare.Mul(12))
+}
+
+func TestTransactionRefundReversesSplit(t *testing.T) {
+	sale := Transaction{Type: TransactionTypeSale, Price: 10000, Quantity: 2, CommissionRate: 1000}
+	refund := Transaction{Type: TransactionTypeRefund, Price: sale.Price, Quantity: -sale.Quantity, CommissionRate: sale.CommissionRate}
+
+	saleCommission, saleShare := sale.Split()
+	refundCommission, refundShare := refund.Split()
+	assert.Equal(t, -saleCommission, refundCommission)
+	assert.Equal(t, -saleShare, refundShare)
+	assert.Equal(t, Money(0), sale.Total()+refund.Total())
+}
+
+func TestTransactionCanVoid(t *testing.T) {
+	soldAt := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	sale := Transaction{Type: TransactionTypeSale, CreatedAt: soldAt}
+
+	assert.True(t, sale.CanVoid(soldAt.Add(5*time.Hour)), "same day")
+	assert.False(t, sale.CanVoid(soldAt.Add(6*time.Hour)), "next day")
+
+	voidedAt := soldAt.Add(time.Hour)
+	voided := sale
+	voided.VoidedAt = &voidedAt
+	assert.False(t, voided.CanVoid(voidedAt))
+
+	refundID := int64(7)
+	refunded := sale
+	refunded.RefundID = &refundID
+	assert.False(t, refunded.CanVoid(soldAt))
+
+	refund := Transaction{Type: TransactionTypeRefund, CreatedAt: soldAt}
+	assert.False(t, refund.CanVoid(soldAt))
+}
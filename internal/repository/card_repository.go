@@ -4,20 +4,27 @@ import (
 	"card_manage/internal/model"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type CardRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewCardRepository(db *sql.DB) *CardRepository {
+func NewCardRepository(db Querier) *CardRepository {
 	return &CardRepository{db: db}
 }
 
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *CardRepository) WithTx(tx *sql.Tx) *CardRepository {
+	return &CardRepository{db: tx}
+}
+
 // CreateCard inserts a new card into the database.
 func (r *CardRepository) CreateCard(card *model.Card) (int64, error) {
-	query := `INSERT INTO cards (store_id, name, series, rarity, card_number, image_url, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	query := `INSERT INTO cards (store_id, name, series, rarity, card_number, image_url, created_by, updated_by, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
 	
 	card.CreatedAt = time.Now()
 	card.UpdatedAt = time.Now()
@@ -31,6 +38,8 @@ func (r *CardRepository) CreateCard(card *model.Card) (int64, error) {
 		card.Rarity,
 		card.CardNumber,
 		card.ImageURL,
+		card.CreatedBy,
+		card.UpdatedBy,
 		card.CreatedAt,
 		card.UpdatedAt,
 	).Scan(&cardID)
@@ -43,7 +52,8 @@ func (r *CardRepository) CreateCard(card *model.Card) (int64, error) {
 
 // GetCardByID retrieves a single card by its ID.
 func (r *CardRepository) GetCardByID(cardID int64) (*model.Card, error) {
-	query := `SELECT id, store_id, name, series, rarity, card_number, image_url, created_at, updated_at 
+	query := `SELECT id, store_id, name, COALESCE(series, ''), COALESCE(rarity, ''), COALESCE(card_number, ''), COALESCE(image_url, ''), created_by, updated_by,
+			  created_at, updated_at
 			  FROM cards WHERE id = $1`
 	
 	card := &model.Card{}
@@ -55,6 +65,8 @@ func (r *CardRepository) GetCardByID(cardID int64) (*model.Card, error) {
 		&card.Rarity,
 		&card.CardNumber,
 		&card.ImageURL,
+		&card.CreatedBy,
+		&card.UpdatedBy,
 		&card.CreatedAt,
 		&card.UpdatedAt,
 	)
@@ -68,11 +80,48 @@ func (r *CardRepository) GetCardByID(cardID int64) (*model.Card, error) {
 	return card, nil
 }
 
+// GetCardsByIDs retrieves all cards whose IDs are in the given list.
+// IDs that do not exist are simply absent from the result.
+func (r *CardRepository) GetCardsByIDs(cardIDs []int64) ([]model.Card, error) {
+	query := `SELECT id, store_id, name, COALESCE(series, ''), COALESCE(rarity, ''), COALESCE(card_number, ''), COALESCE(image_url, ''), created_by, updated_by,
+			  created_at, updated_at
+			  FROM cards WHERE id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(cardIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []model.Card
+	for rows.Next() {
+		var card model.Card
+		if err := rows.Scan(
+			&card.ID,
+			&card.StoreID,
+			&card.Name,
+			&card.Series,
+			&card.Rarity,
+			&card.CardNumber,
+			&card.ImageURL,
+			&card.CreatedBy,
+			&card.UpdatedBy,
+			&card.CreatedAt,
+			&card.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
 // UpdateCard updates an existing card in the database.
 func (r *CardRepository) UpdateCard(card *model.Card) error {
 	query := `UPDATE cards 
-			  SET name = $1, series = $2, rarity = $3, card_number = $4, updated_at = $5
-			  WHERE id = $6`
+			  SET name = $1, series = $2, rarity = $3, card_number = $4, updated_by = $5, updated_at = $6
+			  WHERE id = $7`
 	
 	card.UpdatedAt = time.Now()
 	
@@ -82,6 +131,7 @@ func (r *CardRepository) UpdateCard(card *model.Card) error {
 		card.Series,
 		card.Rarity,
 		card.CardNumber,
+		card.UpdatedBy,
 		card.UpdatedAt,
 		card.ID,
 	)
@@ -97,7 +147,8 @@ func (r *CardRepository) DeleteCard(cardID int64) error {
 
 // ListCardsByStore retrieves a list of cards for a specific store.
 func (r *CardRepository) ListCardsByStore(storeID int64) ([]model.Card, error) {
-	query := `SELECT id, store_id, name, series, rarity, card_number, image_url, created_at, updated_at
+	query := `SELECT id, store_id, name, COALESCE(series, ''), COALESCE(rarity, ''), COALESCE(card_number, ''), COALESCE(image_url, ''), created_by, updated_by,
+			  created_at, updated_at
 			  FROM cards WHERE store_id = $1 ORDER BY created_at DESC`
 
 	rows, err := r.db.Query(query, storeID)
@@ -117,6 +168,8 @@ func (r *CardRepository) ListCardsByStore(storeID int64) ([]model.Card, error) {
 			&card.Rarity,
 			&card.CardNumber,
 			&card.ImageURL,
+			&card.CreatedBy,
+			&card.UpdatedBy,
 			&card.CreatedAt,
 			&card.UpdatedAt,
 		); err != nil {
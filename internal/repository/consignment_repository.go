@@ -4,37 +4,42 @@ import (
 	"card_manage/internal/model"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type ConsignmentRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewConsignmentRepository(db *sql.DB) *ConsignmentRepository {
+func NewConsignmentRepository(db Querier) *ConsignmentRepository {
 	return &ConsignmentRepository{db: db}
 }
 
-// CreateConsignment creates a new consignment request and its items in a single transaction.
-func (r *ConsignmentRepository) CreateConsignment(consignment *model.Consignment, items []*model.ConsignmentItem) (int64, error) {
-	tx, err := r.db.Begin()
-	if err != nil {
-		return 0, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback() // Rollback on any error
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *ConsignmentRepository) WithTx(tx *sql.Tx) *ConsignmentRepository {
+	return &ConsignmentRepository{db: tx}
+}
 
+// CreateConsignment creates a new consignment request and its items.
+// Callers should run it on a transaction-bound repository (see WithTx) so the
+// request and its items are written atomically.
+func (r *ConsignmentRepository) CreateConsignment(consignment *model.Consignment, items []*model.ConsignmentItem) (int64, error) {
 	// 1. Create the parent consignment request
-	consignmentQuery := `INSERT INTO consignments (player_id, store_id, status, created_at, updated_at)
-					   VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	consignmentQuery := `INSERT INTO consignments (player_id, store_id, status, agreement_days, created_at, updated_at)
+					   VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 	consignment.CreatedAt = time.Now()
 	consignment.UpdatedAt = time.Now()
 
 	var consignmentID int64
-	err = tx.QueryRow(
+	err := r.db.QueryRow(
 		consignmentQuery,
 		consignment.PlayerID,
 		consignment.StoreID,
 		consignment.Status,
+		consignment.AgreementDays,
 		consignment.CreatedAt,
 		consignment.UpdatedAt,
 	).Scan(&consignmentID)
@@ -43,39 +48,57 @@ func (r *ConsignmentRepository) CreateConsignment(consignment *model.Consignment
 	}
 
 	// 2. Create the consignment items
-	itemStmt, err := tx.Prepare(`INSERT INTO consignment_items (consignment_id, card_id, status, created_at, updated_at)
-								VALUES ($1, $2, $3, $4, $5)`) 
-	if err != nil {
-		return 0, fmt.Errorf("failed to prepare item statement: %w", err)
-	}
-	defer itemStmt.Close()
-
+	itemQuery := `INSERT INTO consignment_items (consignment_id, card_id, quantity, status, min_price, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 	for _, item := range items {
+		item.ConsignmentID = consignmentID
 		item.CreatedAt = time.Now()
 		item.UpdatedAt = time.Now()
-		_, err := itemStmt.Exec(consignmentID, item.CardID, item.Status, item.CreatedAt, item.UpdatedAt)
+		err := r.db.QueryRow(itemQuery, consignmentID, item.CardID, item.Quantity, item.Status, item.MinPrice, item.CreatedAt, item.UpdatedAt).Scan(&item.ID)
 		if err != nil {
 			return 0, fmt.Errorf("failed to create consignment item for card %d: %w", item.CardID, err)
 		}
 	}
 
-	// 3. Commit the transaction
-	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return consignmentID, nil
 }
 
-// GetConsignmentByID retrieves a consignment and all its items.
+// ConsignmentFilter narrows down a consignment listing. Zero values are ignored.
+type ConsignmentFilter struct {
+	PlayerID   int64
+	StoreID    int64
+	Status     model.ConsignmentRequestStatus
+	ItemStatus model.ConsignmentItemStatus // Only consignments with at least one item in this status
+	From       *time.Time                  // Inclusive lower bound on created_at
+	To         *time.Time                  // Exclusive upper bound on created_at
+	Limit      int
+	Offset     int
+}
+
+const consignmentColumns = `c.id, c.player_id, c.store_id, c.status, c.agreement_days, c.created_at, c.updated_at`
+
+const consignmentItemColumns = `ci.id, ci.consignment_id, ci.card_id, ci.quantity, ci.status, COALESCE(ci.rejection_reason, ''),
+	ci.min_price, ci.listed_price, ci.listed_at, ci.expires_at, ci.price_drops,
+	COALESCE(ci.condition, ''), COALESCE(ci.grade_company, ''), ci.grade_score, ci.split_from_id, ci.created_at, ci.updated_at`
+
+// itemScanDest returns the scan destinations matching consignmentItemColumns.
+func itemScanDest(item *model.ConsignmentItem) []interface{} {
+	return []interface{}{
+		&item.ID, &item.ConsignmentID, &item.CardID, &item.Quantity, &item.Status, &item.RejectionReason,
+		&item.MinPrice, &item.ListedPrice, &item.ListedAt, &item.ExpiresAt, &item.PriceDrops,
+		&item.Condition, &item.GradeCompany, &item.GradeScore, &item.SplitFromID, &item.CreatedAt, &item.UpdatedAt,
+	}
+}
+
+// itemCardColumns are the card catalog fields joined onto each item for API responses.
+const itemCardColumns = `cd.id, cd.store_id, cd.name, COALESCE(cd.series, ''), COALESCE(cd.rarity, ''),
+	COALESCE(cd.card_number, ''), COALESCE(cd.image_url, ''), cd.created_at, cd.updated_at`
+
+// GetConsignmentByID retrieves a consignment and all its items, including their card details.
 func (r *ConsignmentRepository) GetConsignmentByID(id int64) (*model.Consignment, error) {
 	// Get the parent consignment
-	consignment := &model.Consignment{}
-	query := `SELECT id, player_id, store_id, status, created_at, updated_at FROM consignments WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&consignment.ID, &consignment.PlayerID, &consignment.StoreID, 
-		&consignment.Status, &consignment.CreatedAt, &consignment.UpdatedAt,
-	)
+	query := `SELECT ` + consignmentColumns + ` FROM consignments c WHERE c.id = $1`
+	consignment, err := scanConsignment(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Not found
@@ -84,35 +107,168 @@ func (r *ConsignmentRepository) GetConsignmentByID(id int64) (*model.Consignment
 	}
 
 	// Get the associated items
-	rows, err := r.db.Query(`SELECT id, consignment_id, card_id, status, rejection_reason, created_at, updated_at 
-							   FROM consignment_items WHERE consignment_id = $1`, id)
+	itemsByConsignment, err := r.listItemsWithCards([]int64{id})
+	if err != nil {
+		return nil, err
+	}
+	consignment.Items = itemsByConsignment[id]
+
+	return consignment, nil
+}
+
+// ListConsignments retrieves one page of consignments matching the filter, newest first,
+// together with the total number of matching consignments.
+func (r *ConsignmentRepository) ListConsignments(filter ConsignmentFilter) ([]model.Consignment, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.PlayerID != 0 {
+		addCondition("c.player_id = $%d", filter.PlayerID)
+	}
+	if filter.StoreID != 0 {
+		addCondition("c.store_id = $%d", filter.StoreID)
+	}
+	if filter.Status != "" {
+		addCondition("c.status = $%d", filter.Status)
+	}
+	if filter.ItemStatus != "" {
+		addCondition("EXISTS (SELECT 1 FROM consignment_items ci WHERE ci.consignment_id = c.id AND ci.status = $%d)", filter.ItemStatus)
+	}
+	if filter.From != nil {
+		addCondition("c.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("c.created_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s, COUNT(*) OVER() FROM consignments c %s
+			  ORDER BY c.created_at DESC, c.id DESC
+			  LIMIT $%d OFFSET $%d`, consignmentColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing consignments: %w", err)
+	}
+	defer rows.Close()
+
+	consignments := []model.Consignment{}
+	var ids []int64
+	var total int
+	for rows.Next() {
+		var consignment model.Consignment
+		if err := rows.Scan(
+			&consignment.ID, &consignment.PlayerID, &consignment.StoreID,
+			&consignment.Status, &consignment.AgreementDays, &consignment.CreatedAt, &consignment.UpdatedAt, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error scanning consignment: %w", err)
+		}
+		consignments = append(consignments, consignment)
+		ids = append(ids, consignment.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error listing consignments: %w", err)
+	}
+	if len(ids) == 0 {
+		// The page may be past the end, so count separately to still report the total.
+		countArgs := args[:len(args)-2]
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM consignments c `+where, countArgs...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("error counting consignments: %w", err)
+		}
+		return consignments, total, nil
+	}
+
+	itemsByConsignment, err := r.listItemsWithCards(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range consignments {
+		consignments[i].Items = itemsByConsignment[consignments[i].ID]
+	}
+
+	return consignments, total, nil
+}
+
+// listItemsWithCards loads the items of the given consignments with their card details, keyed by consignment ID.
+func (r *ConsignmentRepository) listItemsWithCards(consignmentIDs []int64) (map[int64][]model.ConsignmentItem, error) {
+	query := `SELECT ` + consignmentItemColumns + `, ` + itemCardColumns + `
+			  FROM consignment_items ci
+			  JOIN cards cd ON cd.id = ci.card_id
+			  WHERE ci.consignment_id = ANY($1)
+			  ORDER BY ci.id`
+	rows, err := r.db.Query(query, pq.Array(consignmentIDs))
 	if err != nil {
 		return nil, fmt.Errorf("error getting consignment items: %w", err)
 	}
 	defer rows.Close()
 
-	var items []model.ConsignmentItem
+	var list []model.ConsignmentItem
+	var itemIDs []int64
 	for rows.Next() {
-		var item model.ConsignmentItem
-		if err := rows.Scan(&item.ID, &item.ConsignmentID, &item.CardID, &item.Status, &item.RejectionReason, &item.CreatedAt, &item.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error scanning consignment item: %w", err)
+		item, err := scanItemWithCard(rows)
+		if err != nil {
+			return nil, err
 		}
-		items = append(items, item)
+		list = append(list, item)
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting consignment items: %w", err)
 	}
-	consignment.Items = items
+	rows.Close()
 
-	return consignment, nil
+	photos, err := r.listPhotosForItems(itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[int64][]model.ConsignmentItem, len(consignmentIDs))
+	for _, item := range list {
+		item.Photos = photos[item.ID]
+		items[item.ConsignmentID] = append(items[item.ConsignmentID], item)
+	}
+	return items, nil
+}
+
+// scanItemWithCard scans a row selecting consignmentItemColumns followed by itemCardColumns.
+func scanItemWithCard(row rowScanner) (model.ConsignmentItem, error) {
+	item := model.ConsignmentItem{Card: &model.Card{}}
+	dest := append(itemScanDest(&item),
+		&item.Card.ID, &item.Card.StoreID, &item.Card.Name, &item.Card.Series, &item.Card.Rarity,
+		&item.Card.CardNumber, &item.Card.ImageURL, &item.Card.CreatedAt, &item.Card.UpdatedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return item, fmt.Errorf("error scanning consignment item: %w", err)
+	}
+	return item, nil
 }
 
 // GetConsignmentItemByID retrieves a single consignment item.
 func (r *ConsignmentRepository) GetConsignmentItemByID(id int64) (*model.ConsignmentItem, error) {
+	query := `SELECT ` + consignmentItemColumns + ` FROM consignment_items ci WHERE ci.id = $1`
+	return r.getConsignmentItem(query, id)
+}
+
+// GetConsignmentItemForUpdate retrieves a single consignment item and locks its row
+// until the surrounding transaction ends, so concurrent status changes are serialized.
+// It must be called on a transaction-bound repository.
+func (r *ConsignmentRepository) GetConsignmentItemForUpdate(id int64) (*model.ConsignmentItem, error) {
+	query := `SELECT ` + consignmentItemColumns + ` FROM consignment_items ci WHERE ci.id = $1 FOR UPDATE`
+	return r.getConsignmentItem(query, id)
+}
+
+func (r *ConsignmentRepository) getConsignmentItem(query string, id int64) (*model.ConsignmentItem, error) {
 	item := &model.ConsignmentItem{}
-	query := `SELECT id, consignment_id, card_id, status, rejection_reason, created_at, updated_at 
-			  FROM consignment_items WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&item.ID, &item.ConsignmentID, &item.CardID, &item.Status, 
-		&item.RejectionReason, &item.CreatedAt, &item.UpdatedAt,
-	)
+	err := r.db.QueryRow(query, id).Scan(itemScanDest(item)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Not found
@@ -122,9 +278,480 @@ func (r *ConsignmentRepository) GetConsignmentItemByID(id int64) (*model.Consign
 	return item, nil
 }
 
-// UpdateConsignmentItemStatus updates the status and rejection reason of a specific item.
-func (r *ConsignmentRepository) UpdateConsignmentItemStatus(id int64, status model.ConsignmentItemStatus, reason string) error {
-	query := `UPDATE consignment_items SET status = $1, rejection_reason = $2, updated_at = $3 WHERE id = $4`
+// UpdateConsignmentItemStatus updates the status of a specific item.
+// The rejection reason is only written when the item moves to REJECTED; other moves leave it untouched.
+func (r *ConsignmentRepository) UpdateConsignmentItemStatus(id int64, status model.ConsignmentItemStatus, rejectionReason string) error {
+	reason := sql.NullString{String: rejectionReason, Valid: status == model.ItemStatusRejected}
+	query := `UPDATE consignment_items SET status = $1, rejection_reason = COALESCE($2, rejection_reason), updated_at = $3 WHERE id = $4`
 	_, err := r.db.Exec(query, status, reason, time.Now(), id)
 	return err
-}
\ No newline at end of file
+}
+
+// SplitConsignmentItem moves quantity units of an item into a new item that copies its status,
+// prices, listing and condition, and returns the new item. The original keeps the remaining units,
+// so quantity must be less than its current quantity. It must be called on a transaction-bound
+// repository with the original item locked.
+func (r *ConsignmentRepository) SplitConsignmentItem(id int64, quantity int) (*model.ConsignmentItem, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`UPDATE consignment_items SET quantity = quantity - $1, updated_at = $2 WHERE id = $3 AND quantity > $1`,
+		quantity, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce item quantity: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected != 1 {
+		return nil, fmt.Errorf("cannot split %d units off item %d", quantity, id)
+	}
+
+	query := `INSERT INTO consignment_items (consignment_id, card_id, quantity, status, rejection_reason, min_price, listed_price,
+				  listed_at, expires_at, price_drops, condition, grade_company, grade_score, split_from_id, created_at, updated_at)
+			  SELECT consignment_id, card_id, $1, status, rejection_reason, min_price, listed_price,
+				  listed_at, expires_at, price_drops, condition, grade_company, grade_score, id, $2, $2
+			  FROM consignment_items WHERE id = $3
+			  RETURNING id`
+	var newID int64
+	if err := r.db.QueryRow(query, quantity, now, id).Scan(&newID); err != nil {
+		return nil, fmt.Errorf("failed to create split item: %w", err)
+	}
+	return r.GetConsignmentItemByID(newID)
+}
+
+// SetItemCondition records the condition grade the store assessed for an item.
+func (r *ConsignmentRepository) SetItemCondition(id int64, grade model.ConditionGrade) error {
+	query := `UPDATE consignment_items SET condition = NULLIF($1, ''), grade_company = NULLIF($2, ''), grade_score = $3, updated_at = $4
+			  WHERE id = $5`
+	if _, err := r.db.Exec(query, grade.Condition, grade.GradeCompany, grade.GradeScore, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to set item condition: %w", err)
+	}
+	return nil
+}
+
+// CreateConsignmentItemPhoto stores a reference to an uploaded intake photo of an item.
+func (r *ConsignmentRepository) CreateConsignmentItemPhoto(photo *model.ConsignmentItemPhoto) error {
+	query := `INSERT INTO consignment_item_photos (consignment_item_id, url, uploaded_by, created_at)
+			  VALUES ($1, $2, $3, $4) RETURNING id`
+	photo.CreatedAt = time.Now()
+	if err := r.db.QueryRow(query, photo.ConsignmentItemID, photo.URL, photo.UploadedBy, photo.CreatedAt).Scan(&photo.ID); err != nil {
+		return fmt.Errorf("failed to create consignment item photo: %w", err)
+	}
+	return nil
+}
+
+// ListConsignmentItemPhotos returns an item's intake photos, oldest first.
+func (r *ConsignmentRepository) ListConsignmentItemPhotos(itemID int64) ([]model.ConsignmentItemPhoto, error) {
+	photos, err := r.listPhotosForItems([]int64{itemID})
+	if err != nil {
+		return nil, err
+	}
+	if photos[itemID] == nil {
+		return []model.ConsignmentItemPhoto{}, nil
+	}
+	return photos[itemID], nil
+}
+
+// listPhotosForItems loads the intake photos of the given items, keyed by item ID.
+func (r *ConsignmentRepository) listPhotosForItems(itemIDs []int64) (map[int64][]model.ConsignmentItemPhoto, error) {
+	photos := make(map[int64][]model.ConsignmentItemPhoto)
+	if len(itemIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT id, consignment_item_id, url, uploaded_by, created_at
+			  FROM consignment_item_photos
+			  WHERE consignment_item_id = ANY($1)
+			  ORDER BY created_at, id`
+	rows, err := r.db.Query(query, pq.Array(itemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error listing consignment item photos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var photo model.ConsignmentItemPhoto
+		if err := rows.Scan(&photo.ID, &photo.ConsignmentItemID, &photo.URL, &photo.UploadedBy, &photo.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning consignment item photo: %w", err)
+		}
+		photos[photo.ConsignmentItemID] = append(photos[photo.ConsignmentItemID], photo)
+	}
+	return photos, rows.Err()
+}
+
+// StartItemListing marks an item as put on sale at listedAt, with the given end of its agreement
+// period (nil for none), and resets its automatic price drops.
+func (r *ConsignmentRepository) StartItemListing(id int64, listedAt time.Time, expiresAt *time.Time) error {
+	query := `UPDATE consignment_items SET listed_at = $1, expires_at = $2, price_drops = 0, updated_at = $3 WHERE id = $4`
+	if _, err := r.db.Exec(query, listedAt, expiresAt, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to start item listing: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredItemIDs returns the IDs of items still on sale whose agreement period ended at or before now.
+func (r *ConsignmentRepository) ListExpiredItemIDs(now time.Time) ([]int64, error) {
+	query := `SELECT id FROM consignment_items WHERE status = $1 AND expires_at <= $2 ORDER BY id`
+	rows, err := r.db.Query(query, model.ItemStatusApproved, now)
+	if err != nil {
+		return nil, fmt.Errorf("error finding expired items: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning expired item id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PriceDropCandidate is an item on sale at a store with an automatic price-drop policy.
+type PriceDropCandidate struct {
+	Item  model.ConsignmentItem
+	Store model.Store // Only the price-drop policy fields are loaded
+}
+
+// ListPriceDropCandidates returns the listed items on sale at stores that lower prices automatically.
+func (r *ConsignmentRepository) ListPriceDropCandidates() ([]PriceDropCandidate, error) {
+	query := `SELECT ` + consignmentItemColumns + `, s.id, s.price_drop_days, s.price_drop_rate
+			  FROM consignment_items ci
+			  JOIN consignments c ON c.id = ci.consignment_id
+			  JOIN stores s ON s.id = c.store_id
+			  WHERE ci.status = $1 AND ci.listed_price > 0 AND ci.listed_at IS NOT NULL
+			    AND s.price_drop_days > 0 AND s.price_drop_rate > 0
+			  ORDER BY ci.id`
+	rows, err := r.db.Query(query, model.ItemStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("error finding price drop candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []PriceDropCandidate
+	for rows.Next() {
+		var c PriceDropCandidate
+		dest := append(itemScanDest(&c.Item), &c.Store.ID, &c.Store.PriceDropDays, &c.Store.PriceDropRate)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("error scanning price drop candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// ApplyPriceDrop sets a lower listed price and the number of drops applied to an item read as
+// previous. The update only happens if the item is still on sale with the prices and number of
+// drops it was read with, so it is safe to run without locking the item: a price agreed or a floor
+// raised in the meantime is never overwritten. It reports whether the row changed.
+func (r *ConsignmentRepository) ApplyPriceDrop(previous *model.ConsignmentItem, listedPrice model.Money, priceDrops int) (bool, error) {
+	query := `UPDATE consignment_items SET listed_price = $1, price_drops = $2, updated_at = $3
+			  WHERE id = $4 AND status = $5 AND price_drops = $6 AND listed_price = $7 AND min_price = $8`
+	result, err := r.db.Exec(query, listedPrice, priceDrops, time.Now(), previous.ID, model.ItemStatusApproved,
+		previous.PriceDrops, previous.ListedPrice, previous.MinPrice)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply price drop: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ListExpiringItems returns the store's items on sale whose agreement period ends before the
+// given time, soonest first, with their card details.
+func (r *ConsignmentRepository) ListExpiringItems(storeID int64, before time.Time) ([]model.ConsignmentItem, error) {
+	query := `SELECT ` + consignmentItemColumns + `, ` + itemCardColumns + `
+			  FROM consignment_items ci
+			  JOIN consignments c ON c.id = ci.consignment_id
+			  JOIN cards cd ON cd.id = ci.card_id
+			  WHERE c.store_id = $1 AND ci.status = $2 AND ci.expires_at < $3
+			  ORDER BY ci.expires_at, ci.id`
+	rows, err := r.db.Query(query, storeID, model.ItemStatusApproved, before)
+	if err != nil {
+		return nil, fmt.Errorf("error listing expiring items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []model.ConsignmentItem{}
+	for rows.Next() {
+		item, err := scanItemWithCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// UpdateConsignmentItemPrices stores the agreed listed price and the player's price floor.
+func (r *ConsignmentRepository) UpdateConsignmentItemPrices(id int64, listedPrice, minPrice model.Money) error {
+	query := `UPDATE consignment_items SET listed_price = $1, min_price = $2, updated_at = $3 WHERE id = $4`
+	if _, err := r.db.Exec(query, listedPrice, minPrice, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update item prices: %w", err)
+	}
+	return nil
+}
+
+const priceProposalColumns = `id, consignment_item_id, proposed_by, proposer_id, price, status, created_at, updated_at`
+
+// CreatePriceProposal opens a new price proposal for an item. Any proposal still open for the
+// item is marked SUPERSEDED first, so an item has at most one open proposal at a time.
+func (r *ConsignmentRepository) CreatePriceProposal(proposal *model.PriceProposal) error {
+	now := time.Now()
+	supersede := `UPDATE price_proposals SET status = $1, updated_at = $2 WHERE consignment_item_id = $3 AND status = $4`
+	if _, err := r.db.Exec(supersede, model.ProposalStatusSuperseded, now, proposal.ConsignmentItemID, model.ProposalStatusOpen); err != nil {
+		return fmt.Errorf("failed to supersede open price proposals: %w", err)
+	}
+
+	query := `INSERT INTO price_proposals (consignment_item_id, proposed_by, proposer_id, price, status, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	proposal.Status = model.ProposalStatusOpen
+	proposal.CreatedAt = now
+	proposal.UpdatedAt = now
+	err := r.db.QueryRow(query, proposal.ConsignmentItemID, proposal.ProposedBy, proposal.ProposerID, proposal.Price,
+		proposal.Status, proposal.CreatedAt, proposal.UpdatedAt).Scan(&proposal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create price proposal: %w", err)
+	}
+	return nil
+}
+
+// GetOpenPriceProposal returns the item's open price proposal, or nil if there is none.
+func (r *ConsignmentRepository) GetOpenPriceProposal(itemID int64) (*model.PriceProposal, error) {
+	query := `SELECT ` + priceProposalColumns + ` FROM price_proposals WHERE consignment_item_id = $1 AND status = $2`
+	proposal, err := scanPriceProposal(r.db.QueryRow(query, itemID, model.ProposalStatusOpen))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting open price proposal: %w", err)
+	}
+	return proposal, nil
+}
+
+// UpdatePriceProposalStatus changes the status of a price proposal.
+func (r *ConsignmentRepository) UpdatePriceProposalStatus(id int64, status model.PriceProposalStatus) error {
+	query := `UPDATE price_proposals SET status = $1, updated_at = $2 WHERE id = $3`
+	if _, err := r.db.Exec(query, status, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update price proposal: %w", err)
+	}
+	return nil
+}
+
+// ListPriceProposals returns every price proposal made for an item, oldest first.
+func (r *ConsignmentRepository) ListPriceProposals(itemID int64) ([]model.PriceProposal, error) {
+	query := `SELECT ` + priceProposalColumns + ` FROM price_proposals WHERE consignment_item_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing price proposals: %w", err)
+	}
+	defer rows.Close()
+
+	proposals := []model.PriceProposal{}
+	for rows.Next() {
+		proposal, err := scanPriceProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning price proposal: %w", err)
+		}
+		proposals = append(proposals, *proposal)
+	}
+	return proposals, rows.Err()
+}
+
+func scanPriceProposal(row rowScanner) (*model.PriceProposal, error) {
+	proposal := &model.PriceProposal{}
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.ConsignmentItemID,
+		&proposal.ProposedBy,
+		&proposal.ProposerID,
+		&proposal.Price,
+		&proposal.Status,
+		&proposal.CreatedAt,
+		&proposal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// CreateWithdrawalFee records a fee owed by the player for a returned item.
+func (r *ConsignmentRepository) CreateWithdrawalFee(fee *model.WithdrawalFee) error {
+	query := `INSERT INTO withdrawal_fees (consignment_item_id, player_id, store_id, amount, created_at)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	fee.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(query, fee.ConsignmentItemID, fee.PlayerID, fee.StoreID, fee.Amount, fee.CreatedAt).Scan(&fee.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create withdrawal fee: %w", err)
+	}
+	return nil
+}
+
+// GetOutstandingWithdrawalFeesForUpdate returns, oldest first, the fees the player still owes the
+// store and locks them so that two concurrent settlements cannot deduct the same fee.
+func (r *ConsignmentRepository) GetOutstandingWithdrawalFeesForUpdate(playerID, storeID int64) ([]model.WithdrawalFee, error) {
+	query := `SELECT id, consignment_item_id, player_id, store_id, amount, created_at
+			  FROM withdrawal_fees
+			  WHERE player_id = $1 AND store_id = $2 AND settlement_id IS NULL
+			  ORDER BY created_at, id
+			  FOR UPDATE`
+	rows, err := r.db.Query(query, playerID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawal fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []model.WithdrawalFee
+	for rows.Next() {
+		var fee model.WithdrawalFee
+		if err := rows.Scan(&fee.ID, &fee.ConsignmentItemID, &fee.PlayerID, &fee.StoreID, &fee.Amount, &fee.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning withdrawal fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// MarkWithdrawalFeesDeducted links the given fees to the settlement that deducted them.
+func (r *ConsignmentRepository) MarkWithdrawalFeesDeducted(feeIDs []int64, settlementID int64) error {
+	query := `UPDATE withdrawal_fees SET settlement_id = $1 WHERE id = ANY($2)`
+	if _, err := r.db.Exec(query, settlementID, pq.Array(feeIDs)); err != nil {
+		return fmt.Errorf("failed to mark withdrawal fees as deducted: %w", err)
+	}
+	return nil
+}
+
+// CreateConsignmentItemEvent appends a status change to an item's history.
+func (r *ConsignmentRepository) CreateConsignmentItemEvent(event *model.ConsignmentItemEvent) error {
+	query := `INSERT INTO consignment_item_events (consignment_item_id, actor_id, from_status, to_status, reason, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	event.CreatedAt = time.Now()
+
+	var actorID sql.NullInt64
+	if event.ActorID != nil {
+		actorID = sql.NullInt64{Int64: *event.ActorID, Valid: true}
+	}
+	fromStatus := sql.NullString{String: string(event.FromStatus), Valid: event.FromStatus != ""}
+	reason := sql.NullString{String: event.Reason, Valid: event.Reason != ""}
+
+	err := r.db.QueryRow(query, event.ConsignmentItemID, actorID, fromStatus, event.ToStatus, reason, event.CreatedAt).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create consignment item event: %w", err)
+	}
+	return nil
+}
+
+// ListConsignmentItemEvents returns an item's status history, oldest first.
+func (r *ConsignmentRepository) ListConsignmentItemEvents(itemID int64) ([]model.ConsignmentItemEvent, error) {
+	query := `SELECT id, consignment_item_id, actor_id, COALESCE(from_status, ''), to_status, COALESCE(reason, ''), created_at
+			  FROM consignment_item_events
+			  WHERE consignment_item_id = $1
+			  ORDER BY created_at, id`
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing consignment item events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []model.ConsignmentItemEvent{}
+	for rows.Next() {
+		var event model.ConsignmentItemEvent
+		var actorID sql.NullInt64
+		if err := rows.Scan(&event.ID, &event.ConsignmentItemID, &actorID, &event.FromStatus, &event.ToStatus, &event.Reason, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning consignment item event: %w", err)
+		}
+		if actorID.Valid {
+			event.ActorID = &actorID.Int64
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// RefreshConsignmentStatus recomputes a consignment request's status from its items and stores it.
+// The consignment row is locked first, so when several transactions change items of the same
+// request, each refresh sees the items committed before it and the last one wins with a correct view.
+func (r *ConsignmentRepository) RefreshConsignmentStatus(consignmentID int64) (model.ConsignmentRequestStatus, error) {
+	var current model.ConsignmentRequestStatus
+	err := r.db.QueryRow(`SELECT status FROM consignments WHERE id = $1 FOR UPDATE`, consignmentID).Scan(&current)
+	if err != nil {
+		return "", fmt.Errorf("error locking consignment: %w", err)
+	}
+
+	rows, err := r.db.Query(`SELECT status FROM consignment_items WHERE consignment_id = $1`, consignmentID)
+	if err != nil {
+		return "", fmt.Errorf("error getting consignment item statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var itemStatuses []model.ConsignmentItemStatus
+	for rows.Next() {
+		var status model.ConsignmentItemStatus
+		if err := rows.Scan(&status); err != nil {
+			return "", fmt.Errorf("error scanning consignment item status: %w", err)
+		}
+		itemStatuses = append(itemStatuses, status)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error getting consignment item statuses: %w", err)
+	}
+
+	derived := model.DeriveConsignmentRequestStatus(itemStatuses)
+	if derived == current {
+		return current, nil
+	}
+
+	query := `UPDATE consignments SET status = $1, updated_at = $2 WHERE id = $3`
+	if _, err := r.db.Exec(query, derived, time.Now(), consignmentID); err != nil {
+		return "", fmt.Errorf("error updating consignment status: %w", err)
+	}
+	return derived, nil
+}
+
+// RefreshConsignmentStatusesForItems refreshes the status of every consignment request that owns
+// one of the given items. Requests are refreshed in ID order to keep lock ordering consistent.
+func (r *ConsignmentRepository) RefreshConsignmentStatusesForItems(itemIDs []int64) error {
+	rows, err := r.db.Query(`SELECT DISTINCT consignment_id FROM consignment_items WHERE id = ANY($1) ORDER BY consignment_id`, pq.Array(itemIDs))
+	if err != nil {
+		return fmt.Errorf("error finding consignments for items: %w", err)
+	}
+
+	var consignmentIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning consignment id: %w", err)
+		}
+		consignmentIDs = append(consignmentIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error finding consignments for items: %w", err)
+	}
+
+	for _, id := range consignmentIDs {
+		if _, err := r.RefreshConsignmentStatus(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanConsignment(row rowScanner) (*model.Consignment, error) {
+	consignment := &model.Consignment{}
+	err := row.Scan(
+		&consignment.ID, &consignment.PlayerID, &consignment.StoreID,
+		&consignment.Status, &consignment.CreatedAt, &consignment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return consignment, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"card_manage/internal/model"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CreditRepository reads and appends to the store credit ledger. It has no update or delete
+// methods on purpose: the ledger is append-only.
+type CreditRepository struct {
+	db Querier
+}
+
+func NewCreditRepository(db Querier) *CreditRepository {
+	return &CreditRepository{db: db}
+}
+
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *CreditRepository) WithTx(tx *sql.Tx) *CreditRepository {
+	return &CreditRepository{db: tx}
+}
+
+const creditEntryColumns = `id, store_id, user_id, kind, amount, transaction_id, sale_id, settlement_id,
+	actor_id, COALESCE(note, ''), created_at`
+
+// LockAccount serialises balance changes to one user's credit at one store until the surrounding
+// DB transaction ends. There is no balance row to lock, so a transaction-level advisory lock keyed
+// on the account stands in for one; it keeps two concurrent spends from both passing the balance check.
+func (r *CreditRepository) LockAccount(storeID, userID int64) error {
+	if _, err := r.db.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, storeID, userID); err != nil {
+		return fmt.Errorf("failed to lock credit account: %w", err)
+	}
+	return nil
+}
+
+// CreateEntry appends an entry to the ledger.
+func (r *CreditRepository) CreateEntry(entry *model.CreditEntry) error {
+	query := `INSERT INTO credit_ledger_entries (store_id, user_id, kind, amount, transaction_id, sale_id, settlement_id,
+			  actor_id, note, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10) RETURNING id`
+	entry.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(
+		query,
+		entry.StoreID,
+		entry.UserID,
+		entry.Kind,
+		entry.Amount,
+		entry.TransactionID,
+		entry.SaleID,
+		entry.SettlementID,
+		entry.ActorID,
+		entry.Note,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create credit entry: %w", err)
+	}
+	return nil
+}
+
+// GetBalance sums the user's ledger entries at the store.
+func (r *CreditRepository) GetBalance(storeID, userID int64) (model.Money, error) {
+	var balance model.Money
+	query := `SELECT COALESCE(SUM(amount), 0) FROM credit_ledger_entries WHERE store_id = $1 AND user_id = $2`
+	if err := r.db.QueryRow(query, storeID, userID).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("error getting credit balance: %w", err)
+	}
+	return balance, nil
+}
+
+// ListBalancesByUser returns the user's balance at every store they have credit history with.
+func (r *CreditRepository) ListBalancesByUser(userID int64) ([]model.CreditBalance, error) {
+	query := `SELECT store_id, user_id, SUM(amount) FROM credit_ledger_entries
+			  WHERE user_id = $1
+			  GROUP BY store_id, user_id
+			  ORDER BY store_id`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing credit balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := []model.CreditBalance{}
+	for rows.Next() {
+		var balance model.CreditBalance
+		if err := rows.Scan(&balance.StoreID, &balance.UserID, &balance.Balance); err != nil {
+			return nil, fmt.Errorf("error scanning credit balance: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+// ListEntries returns the user's ledger at the store, newest first.
+func (r *CreditRepository) ListEntries(storeID, userID int64) ([]model.CreditEntry, error) {
+	query := `SELECT ` + creditEntryColumns + ` FROM credit_ledger_entries
+			  WHERE store_id = $1 AND user_id = $2
+			  ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.Query(query, storeID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing credit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.CreditEntry{}
+	for rows.Next() {
+		var entry model.CreditEntry
+		err := rows.Scan(
+			&entry.ID, &entry.StoreID, &entry.UserID, &entry.Kind, &entry.Amount, &entry.TransactionID,
+			&entry.SaleID, &entry.SettlementID, &entry.ActorID, &entry.Note, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning credit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -23,3 +23,24 @@ func ConnectDB(driver, source string) (*sql.DB, error) {
 	log.Println("Database connection established")
 	return db, nil
 }
+
+// Querier is the subset of database methods shared by *sql.DB and *sql.Tx.
+// Repositories are built on a Querier so the same queries can run either
+// directly against the pool or inside a transaction owned by the service layer.
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Statically check that both *sql.DB and *sql.Tx implement Querier.
+var (
+	_ Querier = (*sql.DB)(nil)
+	_ Querier = (*sql.Tx)(nil)
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows, so a single scan
+// helper can serve both single-row lookups and listings.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
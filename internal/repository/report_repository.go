@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"card_manage/internal/model"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ReportRepository runs the aggregate queries behind store reports.
+type ReportRepository struct {
+	db Querier
+}
+
+func NewReportRepository(db Querier) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *ReportRepository) WithTx(tx *sql.Tx) *ReportRepository {
+	return &ReportRepository{db: tx}
+}
+
+// ReportFilter limits a report to the sales made in a date range. Zero values are ignored.
+type ReportFilter struct {
+	From *time.Time // Inclusive lower bound on the sale time
+	To   *time.Time // Exclusive upper bound on the sale time
+}
+
+// reportedSales selects a store's sales and refunds that count, i.e. were not voided. $1 is the
+// store; the filter's date range is appended by reportConditions. Settled sales are joined to
+// the line that paid them, and refunds to the line of the sale they reverse.
+const reportedSales = `FROM transactions t
+	LEFT JOIN settlement_transactions st ON st.transaction_id = COALESCE(t.reverses_id, t.id)
+	WHERE t.store_id = $1 AND t.voided_at IS NULL`
+
+// reportedCommission is the commission the store keeps on a row of reportedSales: what the
+// settlement kept once the sale is settled, otherwise the split at the rate recorded at sale time,
+// rounded per unit like model.SplitCommission. A refund gives back its sale's commission.
+const reportedCommission = `CASE WHEN st.id IS NULL THEN ROUND(t.price * t.commission_rate / 100, 2) * t.quantity
+	ELSE SIGN(t.quantity) * st.commission_amount END`
+
+// reportConditions appends the date range of the filter to a query built on reportedSales.
+func reportConditions(filter ReportFilter, args []interface{}) (string, []interface{}) {
+	conditions := ""
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions += fmt.Sprintf(" AND t.created_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions += fmt.Sprintf(" AND t.created_at < $%d", len(args))
+	}
+	return conditions, args
+}
+
+// SalesByPeriod sums up the store's sales per day, week or month, oldest first. Periods
+// without sales are left out.
+func (r *ReportRepository) SalesByPeriod(storeID int64, period model.ReportPeriod, filter ReportFilter) ([]model.SalesPeriodReport, error) {
+	conditions, args := reportConditions(filter, []interface{}{storeID, string(period)})
+	query := `SELECT date_trunc($2, t.created_at) AS period_start,
+			COUNT(*) FILTER (WHERE t.type = 'SALE'),
+			COUNT(*) FILTER (WHERE t.type = 'REFUND'),
+			COALESCE(SUM(t.quantity), 0),
+			COALESCE(SUM(t.price * t.quantity), 0),
+			COALESCE(SUM(` + reportedCommission + `), 0)
+		` + reportedSales + conditions + `
+		GROUP BY period_start
+		ORDER BY period_start`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reporting sales by period: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []model.SalesPeriodReport{}
+	for rows.Next() {
+		var report model.SalesPeriodReport
+		if err := rows.Scan(&report.PeriodStart, &report.Sales, &report.Refunds, &report.Units, &report.Gross, &report.Commission); err != nil {
+			return nil, fmt.Errorf("error scanning sales report: %w", err)
+		}
+		report.PlayerShare = report.Gross - report.Commission
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// CommissionByPaymentMethod sums up the commission the store earned per payment method.
+func (r *ReportRepository) CommissionByPaymentMethod(storeID int64, filter ReportFilter) ([]model.CommissionReport, error) {
+	conditions, args := reportConditions(filter, []interface{}{storeID})
+	query := `SELECT t.payment_method,
+			COUNT(*) FILTER (WHERE t.type = 'SALE'),
+			COALESCE(SUM(t.price * t.quantity), 0),
+			COALESCE(SUM(` + reportedCommission + `), 0)
+		` + reportedSales + conditions + `
+		GROUP BY t.payment_method
+		ORDER BY t.payment_method`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reporting commission: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []model.CommissionReport{}
+	for rows.Next() {
+		var report model.CommissionReport
+		if err := rows.Scan(&report.PaymentMethod, &report.Sales, &report.Gross, &report.Commission); err != nil {
+			return nil, fmt.Errorf("error scanning commission report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// PlayerLiabilities lists, per player with anything outstanding at the store, the share of sold
+// but unsettled sales, the outstanding deductions and the requested settlements not yet paid out.
+// Owed is left for the caller to work out.
+func (r *ReportRepository) PlayerLiabilities(storeID int64) ([]model.PlayerLiability, error) {
+	query := `
+		WITH unsettled AS (
+			SELECT c.player_id,
+			       SUM(t.price * t.quantity) AS sales,
+			       SUM((t.price - ROUND(t.price * t.commission_rate / 100, 2)) * t.quantity) AS share
+			FROM transactions t
+			JOIN consignment_items ci ON t.consignment_item_id = ci.id
+			JOIN consignments c ON ci.consignment_id = c.id
+			WHERE c.store_id = $1
+			  AND ci.status = 'SOLD'
+			  AND t.type = 'SALE'
+			  AND t.voided_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reverses_id = t.id)
+			GROUP BY c.player_id
+		), deductions AS (
+			SELECT player_id, SUM(amount) AS amount FROM (
+				SELECT player_id, amount FROM withdrawal_fees WHERE store_id = $1 AND settlement_id IS NULL
+				UNION ALL
+				SELECT player_id, amount FROM settlement_adjustments WHERE store_id = $1 AND settlement_id IS NULL
+			) outstanding
+			GROUP BY player_id
+		), pending AS (
+			SELECT player_id, SUM(amount) AS amount FROM settlements
+			WHERE store_id = $1 AND status = 'REQUESTED'
+			GROUP BY player_id
+		)
+		SELECT u.id, u.email, COALESCE(un.sales, 0), COALESCE(un.share, 0), COALESCE(d.amount, 0), COALESCE(p.amount, 0)
+		FROM users u
+		LEFT JOIN unsettled un ON un.player_id = u.id
+		LEFT JOIN deductions d ON d.player_id = u.id
+		LEFT JOIN pending p ON p.player_id = u.id
+		WHERE un.player_id IS NOT NULL OR d.player_id IS NOT NULL OR p.player_id IS NOT NULL
+		ORDER BY u.id`
+
+	rows, err := r.db.Query(query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error reporting player liabilities: %w", err)
+	}
+	defer rows.Close()
+
+	liabilities := []model.PlayerLiability{}
+	for rows.Next() {
+		var l model.PlayerLiability
+		if err := rows.Scan(&l.PlayerID, &l.PlayerEmail, &l.UnsettledSales, &l.UnsettledShare, &l.Deductions, &l.PendingPayouts); err != nil {
+			return nil, fmt.Errorf("error scanning player liability: %w", err)
+		}
+		liabilities = append(liabilities, l)
+	}
+	return liabilities, rows.Err()
+}
+
+// TopSellers ranks the store's cards, or series, by units sold and then by sales total.
+// Refunded sales are left out.
+func (r *ReportRepository) TopSellers(storeID int64, grouping model.TopSellerGrouping, filter ReportFilter, limit int) ([]model.TopSeller, error) {
+	conditions, args := reportConditions(filter, []interface{}{storeID})
+	args = append(args, limit)
+
+	columns, groupBy := "card.id, card.name, COALESCE(card.series, '')", "card.id, card.name, card.series"
+	if grouping == model.TopSellersBySeries {
+		columns, groupBy = "NULL::INT, '', COALESCE(card.series, '')", "COALESCE(card.series, '')"
+	}
+
+	query := fmt.Sprintf(`SELECT %s, SUM(t.quantity), COUNT(*), SUM(t.price * t.quantity)
+		FROM transactions t
+		JOIN consignment_items ci ON t.consignment_item_id = ci.id
+		JOIN cards card ON ci.card_id = card.id
+		WHERE t.store_id = $1
+		  AND t.type = 'SALE'
+		  AND t.voided_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reverses_id = t.id)%s
+		GROUP BY %s
+		ORDER BY 4 DESC, 6 DESC
+		LIMIT $%d`, columns, conditions, groupBy, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reporting top sellers: %w", err)
+	}
+	defer rows.Close()
+
+	sellers := []model.TopSeller{}
+	for rows.Next() {
+		var seller model.TopSeller
+		if err := rows.Scan(&seller.CardID, &seller.CardName, &seller.Series, &seller.Units, &seller.Sales, &seller.Gross); err != nil {
+			return nil, fmt.Errorf("error scanning top seller: %w", err)
+		}
+		sellers = append(sellers, seller)
+	}
+	return sellers, rows.Err()
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"card_manage/internal/model"
+	"database/sql"
+	"time"
+)
+
+type SessionRepository struct {
+	db Querier
+}
+
+func NewSessionRepository(db Querier) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *SessionRepository) WithTx(tx *sql.Tx) *SessionRepository {
+	return &SessionRepository{db: tx}
+}
+
+const sessionColumns = `id, user_id, refresh_token_hash, COALESCE(previous_token_hash, ''), expires_at,
+	last_used_at, revoked_at, created_at`
+
+// CreateSession inserts a new session.
+func (r *SessionRepository) CreateSession(session *model.Session) error {
+	query := `INSERT INTO sessions (user_id, refresh_token_hash, expires_at, created_at)
+			  VALUES ($1, $2, $3, $4) RETURNING id`
+
+	session.CreatedAt = time.Now()
+	return r.db.QueryRow(query, session.UserID, session.RefreshTokenHash, session.ExpiresAt, session.CreatedAt).
+		Scan(&session.ID)
+}
+
+// GetSession retrieves a session by its ID. It returns nil if there is none.
+func (r *SessionRepository) GetSession(id int64) (*model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+	return r.getSession(query, id)
+}
+
+// GetSessionByRefreshHash retrieves the session whose current refresh token has the given hash.
+// It returns nil if there is none.
+func (r *SessionRepository) GetSessionByRefreshHash(hash string) (*model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE refresh_token_hash = $1`
+	return r.getSession(query, hash)
+}
+
+// RotateRefreshToken replaces the session's refresh token, provided it is still oldHash and the
+// session has not been revoked. It reports whether the token was replaced, so that only one of
+// two concurrent refreshes with the same token succeeds.
+func (r *SessionRepository) RotateRefreshToken(id int64, oldHash, newHash string, at time.Time) (bool, error) {
+	query := `UPDATE sessions SET refresh_token_hash = $3, previous_token_hash = $2, last_used_at = $4
+			  WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL`
+	return r.exec(query, id, oldHash, newHash, at)
+}
+
+// RevokeSessionByRefreshHash revokes the session whose current refresh token has the given hash. It reports
+// whether an unrevoked session was found.
+func (r *SessionRepository) RevokeSessionByRefreshHash(hash string, at time.Time) (bool, error) {
+	query := `UPDATE sessions SET revoked_at = $2 WHERE refresh_token_hash = $1 AND revoked_at IS NULL`
+	return r.exec(query, hash, at)
+}
+
+// RevokeSessionByPreviousHash revokes the session whose last replaced refresh token has the given
+// hash. It reports whether an unrevoked session was found.
+func (r *SessionRepository) RevokeSessionByPreviousHash(hash string, at time.Time) (bool, error) {
+	query := `UPDATE sessions SET revoked_at = $2 WHERE previous_token_hash = $1 AND revoked_at IS NULL`
+	return r.exec(query, hash, at)
+}
+
+// RevokeUserSessions revokes every unrevoked session of a user and returns how many there were.
+func (r *SessionRepository) RevokeUserSessions(userID int64, at time.Time) (int64, error) {
+	query := `UPDATE sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, userID, at)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SessionRepository) exec(query string, args ...interface{}) (bool, error) {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *SessionRepository) getSession(query string, arg interface{}) (*model.Session, error) {
+	session, err := scanSession(r.db.QueryRow(query, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+func scanSession(row rowScanner) (*model.Session, error) {
+	var session model.Session
+	err := row.Scan(&session.ID, &session.UserID, &session.RefreshTokenHash, &session.PreviousTokenHash,
+		&session.ExpiresAt, &session.LastUsedAt, &session.RevokedAt, &session.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
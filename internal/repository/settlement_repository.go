@@ -3,32 +3,61 @@ package repository
 import (
 	"card_manage/internal/model"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type SettlementRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewSettlementRepository(db *sql.DB) *SettlementRepository {
+func NewSettlementRepository(db Querier) *SettlementRepository {
 	return &SettlementRepository{db: db}
 }
 
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *SettlementRepository) WithTx(tx *sql.Tx) *SettlementRepository {
+	return &SettlementRepository{db: tx}
+}
+
+// SettlementFilter narrows down a settlement listing. Zero values are ignored.
+type SettlementFilter struct {
+	Status model.SettlementStatus
+	From   *time.Time // Inclusive lower bound on requested_at
+	To     *time.Time // Exclusive upper bound on requested_at
+}
+
+const settlementColumns = `id, player_id, store_id, payout_method, commission_rate, gross_amount, commission_amount, net_amount,
+	amount, fees_deducted, adjustments_deducted, status, requested_at, completed_at, completed_by, created_at, updated_at`
+
 // CreateSettlement creates a new settlement request.
 func (r *SettlementRepository) CreateSettlement(settlement *model.Settlement) (int64, error) {
-	query := `INSERT INTO settlements (player_id, store_id, amount, status, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
-	
+	query := `INSERT INTO settlements (player_id, store_id, payout_method, commission_rate, gross_amount, commission_amount, net_amount,
+			  amount, fees_deducted, adjustments_deducted, status, requested_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
+
 	settlement.CreatedAt = time.Now()
-	settlement.UpdatedAt = time.Now()
+	settlement.UpdatedAt = settlement.CreatedAt
+	settlement.RequestedAt = settlement.CreatedAt
 
 	var settlementID int64
 	err := r.db.QueryRow(
 		query,
 		settlement.PlayerID,
 		settlement.StoreID,
+		settlement.PayoutMethod,
+		settlement.CommissionRate,
+		settlement.GrossAmount,
+		settlement.CommissionAmount,
+		settlement.NetAmount,
 		settlement.Amount,
+		settlement.FeesDeducted,
+		settlement.AdjustmentsDeducted,
 		settlement.Status,
+		settlement.RequestedAt,
 		settlement.CreatedAt,
 		settlement.UpdatedAt,
 	).Scan(&settlementID)
@@ -39,16 +68,67 @@ func (r *SettlementRepository) CreateSettlement(settlement *model.Settlement) (i
 	return settlementID, nil
 }
 
+// GetSettlementByID retrieves a single settlement by its ID.
+func (r *SettlementRepository) GetSettlementByID(id int64) (*model.Settlement, error) {
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE id = $1`
+
+	settlement, err := scanSettlement(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("error getting settlement: %w", err)
+	}
+	return settlement, nil
+}
+
 // ListSettlementsByPlayer retrieves all settlements for a specific player.
-func (r *SettlementRepository) ListSettlementsByPlayer(playerID int64) ([]model.Settlement, error) {
-	// Implementation similar to other List methods
-	return nil, nil // Placeholder
+func (r *SettlementRepository) ListSettlementsByPlayer(playerID int64, filter SettlementFilter) ([]model.Settlement, error) {
+	return r.listSettlements("player_id", playerID, filter)
 }
 
 // ListSettlementsByStore retrieves all settlements for a specific store.
-func (r *SettlementRepository) ListSettlementsByStore(storeID int64) ([]model.Settlement, error) {
-	// Implementation similar to other List methods
-	return nil, nil // Placeholder
+func (r *SettlementRepository) ListSettlementsByStore(storeID int64, filter SettlementFilter) ([]model.Settlement, error) {
+	return r.listSettlements("store_id", storeID, filter)
+}
+
+// listSettlements builds the shared listing query for the given owner column.
+func (r *SettlementRepository) listSettlements(ownerColumn string, ownerID int64, filter SettlementFilter) ([]model.Settlement, error) {
+	conditions := []string{ownerColumn + " = $1"}
+	args := []interface{}{ownerID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("requested_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("requested_at < $%d", len(args)))
+	}
+
+	query := `SELECT ` + settlementColumns + ` FROM settlements
+			  WHERE ` + strings.Join(conditions, " AND ") + `
+			  ORDER BY requested_at DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := []model.Settlement{}
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, *settlement)
+	}
+	return settlements, rows.Err()
 }
 
 // UpdateSettlementStatus updates the status of a settlement.
@@ -58,18 +138,75 @@ func (r *SettlementRepository) UpdateSettlementStatus(id int64, status model.Set
 	return err
 }
 
-// GetUnsettledTransactions calculates the total amount from sold but uncleared consignments for a player at a specific store.
-func (r *SettlementRepository) GetUnsettledTransactions(playerID, storeID int64) ([]model.Transaction, error) {
-	query := `
-		SELECT t.id, t.consignment_item_id, t.store_id, t.price, t.payment_method, t.commission_rate, t.created_at
+// CompleteSettlement marks a requested settlement as completed and stamps the completion time and
+// the staff member who paid it out, if any. It returns false if the settlement was no longer in
+// the REQUESTED state.
+func (r *SettlementRepository) CompleteSettlement(id int64, completedAt time.Time, completedBy *int64) (bool, error) {
+	query := `UPDATE settlements SET status = $1, completed_at = $2, completed_by = $3, updated_at = $2
+			  WHERE id = $4 AND status = $5`
+	result, err := r.db.Exec(query, model.StatusCompleted, completedAt, completedBy, id, model.StatusRequested)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// EarningsFilter narrows down a player's earnings. StoreID applies to the totals and the sales;
+// the date range and paging apply to the sales only. Zero values are ignored.
+type EarningsFilter struct {
+	StoreID int64
+	From    *time.Time // Inclusive lower bound on the sale time
+	To      *time.Time // Exclusive upper bound on the sale time
+	Limit   int
+	Offset  int
+}
+
+// unsettledSalesQuery selects the sales that put a player's items at a store in SOLD, leaving out
+// voided and refunded sales of the same item. $1 is the player and $2 the store.
+const unsettledSalesQuery = `
+		SELECT t.id, t.consignment_item_id, t.store_id, t.price, t.quantity, t.payment_method, t.commission_rate, t.commission_rule_id, t.created_at
 		FROM transactions t
 		JOIN consignment_items ci ON t.consignment_item_id = ci.id
 		JOIN consignments c ON ci.consignment_id = c.id
 		WHERE c.player_id = $1
-		  AND c.store_id = $2
-		  AND ci.status = 'SOLD'`
+		  AND ($2 = 0 OR c.store_id = $2)
+		  AND ci.status = 'SOLD'
+		  AND t.type = 'SALE'
+		  AND t.voided_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reverses_id = t.id)`
 
-	rows, err := r.db.Query(query, playerID, storeID)
+// GetUnsettledTransactions calculates the total amount from sold but uncleared consignments for a player at a specific store.
+// Only the sale that put each item in SOLD counts; voided and refunded sales of the same item are skipped.
+// With transactionIDs, only those of the sales are returned; otherwise all of them are.
+// The matching consignment item rows are locked until the surrounding transaction ends,
+// so two settlement requests cannot clear the same sale.
+func (r *SettlementRepository) GetUnsettledTransactions(playerID, storeID int64, transactionIDs []int64) ([]model.Transaction, error) {
+	args := []interface{}{playerID, storeID}
+	selected := ""
+	if len(transactionIDs) > 0 {
+		args = append(args, pq.Array(transactionIDs))
+		selected = "AND t.id = ANY($3)"
+	}
+
+	query := unsettledSalesQuery + `
+		  ` + selected + `
+		ORDER BY t.id
+		FOR UPDATE OF ci`
+	return r.queryUnsettledTransactions(query, args...)
+}
+
+// ListUnsettledTransactions returns a player's unsettled sales at a store, or at every store when
+// storeID is 0, without locking them.
+func (r *SettlementRepository) ListUnsettledTransactions(playerID, storeID int64) ([]model.Transaction, error) {
+	return r.queryUnsettledTransactions(unsettledSalesQuery+` ORDER BY t.id`, playerID, storeID)
+}
+
+func (r *SettlementRepository) queryUnsettledTransactions(query string, args ...interface{}) ([]model.Transaction, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -78,10 +215,279 @@ func (r *SettlementRepository) GetUnsettledTransactions(playerID, storeID int64)
 	var transactions []model.Transaction
 	for rows.Next() {
 		var tx model.Transaction
-		if err := rows.Scan(&tx.ID, &tx.ConsignmentItemID, &tx.StoreID, &tx.Price, &tx.PaymentMethod, &tx.CommissionRate, &tx.CreatedAt); err != nil {
+		if err := rows.Scan(&tx.ID, &tx.ConsignmentItemID, &tx.StoreID, &tx.Price, &tx.Quantity, &tx.PaymentMethod, &tx.CommissionRate, &tx.CommissionRuleID, &tx.CreatedAt); err != nil {
 			return nil, err
 		}
 		transactions = append(transactions, tx)
 	}
-	return transactions, nil
+	return transactions, rows.Err()
+}
+
+// ListStoreEarnings sums up a player's items and settlements at every store they have consigned
+// to, or at one store. The unsettled sales are left at zero for the caller to fill in, as each
+// sale's share is rounded on its own.
+func (r *SettlementRepository) ListStoreEarnings(playerID, storeID int64) ([]model.StoreEarnings, error) {
+	query := `
+		WITH items AS (
+			SELECT c.store_id,
+			       COALESCE(SUM(ci.quantity) FILTER (WHERE ci.status = 'PENDING'), 0) AS pending_units,
+			       COALESCE(SUM(ci.quantity) FILTER (WHERE ci.status = 'APPROVED'), 0) AS listed_units,
+			       COALESCE(SUM(ci.listed_price * ci.quantity) FILTER (WHERE ci.status = 'APPROVED'), 0) AS listed_value
+			FROM consignment_items ci
+			JOIN consignments c ON ci.consignment_id = c.id
+			WHERE c.player_id = $1
+			GROUP BY c.store_id
+		), settled AS (
+			SELECT store_id, SUM(net_amount) AS settled_share, SUM(amount) AS paid_out
+			FROM settlements
+			WHERE player_id = $1
+			GROUP BY store_id
+		)
+		SELECT s.id, s.name, COALESCE(i.pending_units, 0), COALESCE(i.listed_units, 0), COALESCE(i.listed_value, 0),
+		       COALESCE(st.settled_share, 0), COALESCE(st.paid_out, 0)
+		FROM stores s
+		LEFT JOIN items i ON i.store_id = s.id
+		LEFT JOIN settled st ON st.store_id = s.id
+		WHERE (i.store_id IS NOT NULL OR st.store_id IS NOT NULL)
+		  AND ($2 = 0 OR s.id = $2)
+		ORDER BY s.id`
+
+	rows, err := r.db.Query(query, playerID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing store earnings: %w", err)
+	}
+	defer rows.Close()
+
+	earnings := []model.StoreEarnings{}
+	for rows.Next() {
+		var e model.StoreEarnings
+		if err := rows.Scan(&e.StoreID, &e.StoreName, &e.PendingUnits, &e.ListedUnits, &e.ListedValue, &e.SettledShare, &e.PaidOut); err != nil {
+			return nil, fmt.Errorf("error scanning store earnings: %w", err)
+		}
+		earnings = append(earnings, e)
+	}
+	return earnings, rows.Err()
+}
+
+// ListPlayerSales returns a page of the sales of a player's items, newest first, with the total
+// number of matching sales. Voided and refunded sales are left out. Settled sales carry their
+// settlement line's split and the rate it was split at: the payout method's rate of the commission
+// rule the sale was charged under, or else the settlement's base rate. For the others only the
+// rate recorded at sale time is filled in.
+func (r *SettlementRepository) ListPlayerSales(playerID int64, filter EarningsFilter) ([]model.SaleEarning, int, error) {
+	conditions := []string{
+		"c.player_id = $1",
+		"t.type = 'SALE'",
+		"t.voided_at IS NULL",
+		"NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reverses_id = t.id)",
+	}
+	args := []interface{}{playerID}
+	addCondition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	if filter.StoreID != 0 {
+		addCondition("t.store_id = $%d", filter.StoreID)
+	}
+	if filter.From != nil {
+		addCondition("t.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("t.created_at < $%d", *filter.To)
+	}
+
+	from := `FROM transactions t
+		JOIN consignment_items ci ON t.consignment_item_id = ci.id
+		JOIN consignments c ON ci.consignment_id = c.id
+		JOIN cards card ON ci.card_id = card.id
+		LEFT JOIN settlement_transactions st ON st.transaction_id = t.id
+		LEFT JOIN settlements s ON st.settlement_id = s.id
+		LEFT JOIN commission_rules cr ON cr.id = t.commission_rule_id
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) `+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting player sales: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT t.id, t.store_id, t.consignment_item_id, card.name, t.price, t.quantity, t.payment_method,
+			CASE WHEN s.gross_amount > 0 THEN COALESCE(
+				CASE s.payout_method WHEN 'CASH' THEN cr.commission_cash ELSE cr.commission_credit END,
+				s.commission_rate) ELSE t.commission_rate END,
+			COALESCE(st.commission_amount, 0), COALESCE(st.net_amount, 0), st.settlement_id, t.created_at
+		%s
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $%d OFFSET $%d`, from, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing player sales: %w", err)
+	}
+	defer rows.Close()
+
+	sales := []model.SaleEarning{}
+	for rows.Next() {
+		var sale model.SaleEarning
+		err := rows.Scan(
+			&sale.TransactionID, &sale.StoreID, &sale.ConsignmentItemID, &sale.CardName, &sale.Price, &sale.Quantity,
+			&sale.PaymentMethod, &sale.CommissionRate, &sale.CommissionAmount, &sale.NetAmount, &sale.SettlementID, &sale.SoldAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error scanning player sale: %w", err)
+		}
+		sale.GrossAmount = sale.Price.Mul(int64(sale.Quantity))
+		sales = append(sales, sale)
+	}
+	return sales, total, rows.Err()
+}
+
+// CreateSettlementLines records the sales a settlement paid.
+func (r *SettlementRepository) CreateSettlementLines(lines []model.SettlementLine) error {
+	query := `INSERT INTO settlement_transactions (settlement_id, transaction_id, gross_amount, commission_amount, net_amount)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	for i := range lines {
+		line := &lines[i]
+		err := r.db.QueryRow(query, line.SettlementID, line.TransactionID, line.GrossAmount, line.CommissionAmount, line.NetAmount).Scan(&line.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create settlement line: %w", err)
+		}
+	}
+	return nil
+}
+
+const settlementLineSelect = `SELECT st.id, st.settlement_id, st.transaction_id, t.consignment_item_id, t.price, t.quantity,
+	st.gross_amount, st.commission_amount, st.net_amount, t.created_at
+	FROM settlement_transactions st
+	JOIN transactions t ON st.transaction_id = t.id`
+
+// ListSettlementLines returns the sales a settlement paid, in the order they were sold.
+func (r *SettlementRepository) ListSettlementLines(settlementID int64) ([]model.SettlementLine, error) {
+	rows, err := r.db.Query(settlementLineSelect+` WHERE st.settlement_id = $1 ORDER BY t.id`, settlementID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing settlement lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []model.SettlementLine{}
+	for rows.Next() {
+		line, err := scanSettlementLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning settlement line: %w", err)
+		}
+		lines = append(lines, *line)
+	}
+	return lines, rows.Err()
+}
+
+// GetSettlementLineByTransaction returns the settlement line that paid a sale, or nil if the
+// sale has not been settled or was settled before lines were recorded.
+func (r *SettlementRepository) GetSettlementLineByTransaction(transactionID int64) (*model.SettlementLine, error) {
+	line, err := scanSettlementLine(r.db.QueryRow(settlementLineSelect+` WHERE st.transaction_id = $1`, transactionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting settlement line: %w", err)
+	}
+	return line, nil
+}
+
+func scanSettlementLine(row rowScanner) (*model.SettlementLine, error) {
+	line := &model.SettlementLine{}
+	err := row.Scan(
+		&line.ID,
+		&line.SettlementID,
+		&line.TransactionID,
+		&line.ConsignmentItemID,
+		&line.Price,
+		&line.Quantity,
+		&line.GrossAmount,
+		&line.CommissionAmount,
+		&line.NetAmount,
+		&line.SoldAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// CreateSettlementAdjustment records a refunded player share to take back from a later settlement.
+func (r *SettlementRepository) CreateSettlementAdjustment(adjustment *model.SettlementAdjustment) error {
+	query := `INSERT INTO settlement_adjustments (transaction_id, player_id, store_id, amount, created_at)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	adjustment.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(query, adjustment.TransactionID, adjustment.PlayerID, adjustment.StoreID, adjustment.Amount, adjustment.CreatedAt).Scan(&adjustment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement adjustment: %w", err)
+	}
+	return nil
+}
+
+// GetOutstandingAdjustmentsForUpdate returns, oldest first, the adjustments not yet deducted from a
+// settlement between the player and the store, and locks them so that two concurrent settlements
+// cannot deduct the same adjustment.
+func (r *SettlementRepository) GetOutstandingAdjustmentsForUpdate(playerID, storeID int64) ([]model.SettlementAdjustment, error) {
+	query := `SELECT id, transaction_id, player_id, store_id, amount, created_at
+			  FROM settlement_adjustments
+			  WHERE player_id = $1 AND store_id = $2 AND settlement_id IS NULL
+			  ORDER BY created_at, id
+			  FOR UPDATE`
+	rows, err := r.db.Query(query, playerID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting settlement adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	var adjustments []model.SettlementAdjustment
+	for rows.Next() {
+		var adjustment model.SettlementAdjustment
+		if err := rows.Scan(&adjustment.ID, &adjustment.TransactionID, &adjustment.PlayerID, &adjustment.StoreID, &adjustment.Amount, &adjustment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning settlement adjustment: %w", err)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	return adjustments, rows.Err()
+}
+
+// MarkAdjustmentsDeducted links the given adjustments to the settlement that deducted them.
+func (r *SettlementRepository) MarkAdjustmentsDeducted(adjustmentIDs []int64, settlementID int64) error {
+	query := `UPDATE settlement_adjustments SET settlement_id = $1 WHERE id = ANY($2)`
+	if _, err := r.db.Exec(query, settlementID, pq.Array(adjustmentIDs)); err != nil {
+		return fmt.Errorf("failed to mark settlement adjustments as deducted: %w", err)
+	}
+	return nil
+}
+
+func scanSettlement(row rowScanner) (*model.Settlement, error) {
+	settlement := &model.Settlement{}
+	var completedAt sql.NullTime
+	err := row.Scan(
+		&settlement.ID,
+		&settlement.PlayerID,
+		&settlement.StoreID,
+		&settlement.PayoutMethod,
+		&settlement.CommissionRate,
+		&settlement.GrossAmount,
+		&settlement.CommissionAmount,
+		&settlement.NetAmount,
+		&settlement.Amount,
+		&settlement.FeesDeducted,
+		&settlement.AdjustmentsDeducted,
+		&settlement.Status,
+		&settlement.RequestedAt,
+		&completedAt,
+		&settlement.CompletedBy,
+		&settlement.CreatedAt,
+		&settlement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		settlement.CompletedAt = &completedAt.Time
+	}
+	return settlement, nil
 }
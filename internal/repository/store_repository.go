@@ -3,32 +3,75 @@ package repository
 import (
 	"card_manage/internal/model"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type StoreRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewStoreRepository(db *sql.DB) *StoreRepository {
+func NewStoreRepository(db Querier) *StoreRepository {
 	return &StoreRepository{db: db}
 }
 
-// CreateStore inserts a new store into the database.
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *StoreRepository) WithTx(tx *sql.Tx) *StoreRepository {
+	return &StoreRepository{db: tx}
+}
+
+// StoreFilter narrows the store directory. Only active stores are listed.
+type StoreFilter struct {
+	Name   string // Part of the store name, case-insensitive
+	City   string // Whole city name, case-insensitive
+	Limit  int
+	Offset int
+}
+
+const storeColumns = `id, user_id, name, COALESCE(address, ''), COALESCE(city, ''), COALESCE(phone, ''),
+	COALESCE(email, ''), COALESCE(opening_hours, ''), commission_cash, commission_credit, withdrawal_fee,
+	agreement_days, price_drop_days, price_drop_rate, min_settlement_amount, status, COALESCE(status_reason, ''),
+	reviewed_by, reviewed_at, created_at, updated_at`
+
+// CreateStore inserts a new store into the database, together with its owner's membership.
 func (r *StoreRepository) CreateStore(store *model.Store) (int64, error) {
-	query := `INSERT INTO stores (user_id, name, commission_cash, commission_credit, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	query := `WITH s AS (
+				  INSERT INTO stores (user_id, name, address, city, phone, email, opening_hours, commission_cash, commission_credit,
+				  withdrawal_fee, agreement_days, price_drop_days, price_drop_rate, min_settlement_amount, status, created_at, updated_at)
+				  VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''),
+				  $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, user_id, created_at
+			  )
+			  INSERT INTO store_members (store_id, user_id, role, status, accepted_at, created_at, updated_at)
+			  SELECT id, user_id, 'OWNER', 'ACTIVE', created_at, created_at, created_at FROM s
+			  RETURNING store_id`
 
 	store.CreatedAt = time.Now()
 	store.UpdatedAt = time.Now()
+	if store.Status == "" {
+		store.Status = model.StoreStatusPendingReview
+	}
 
 	var storeID int64
 	err := r.db.QueryRow(
 		query,
 		store.UserID,
 		store.Name,
+		store.Address,
+		store.City,
+		store.Phone,
+		store.Email,
+		store.OpeningHours,
 		store.CommissionCash,
 		store.CommissionCredit,
+		store.WithdrawalFee,
+		store.AgreementDays,
+		store.PriceDropDays,
+		store.PriceDropRate,
+		store.MinSettlement,
+		store.Status,
 		store.CreatedAt,
 		store.UpdatedAt,
 	).Scan(&storeID)
@@ -40,28 +83,479 @@ func (r *StoreRepository) CreateStore(store *model.Store) (int64, error) {
 	return storeID, nil
 }
 
-// GetStoreByUserID retrieves a store from the database by its owner's user ID.
-func (r *StoreRepository) GetStoreByUserID(userID int64) (*model.Store, error) {
-	query := `SELECT id, user_id, name, commission_cash, commission_credit, created_at, updated_at 
-			  FROM stores WHERE user_id = $1`
+// GetStoreByID retrieves a store from the database by its ID.
+func (r *StoreRepository) GetStoreByID(id int64) (*model.Store, error) {
+	query := `SELECT ` + storeColumns + ` FROM stores WHERE id = $1`
+	return r.getStore(query, id)
+}
+
+// UpdateStore saves the owner-editable details of a store: its profile, commission rates and policies.
+// The status and review details are only changed through UpdateStoreStatus.
+func (r *StoreRepository) UpdateStore(store *model.Store) error {
+	query := `UPDATE stores SET name = $1, address = NULLIF($2, ''), city = NULLIF($3, ''), phone = NULLIF($4, ''),
+			  email = NULLIF($5, ''), opening_hours = NULLIF($6, ''), commission_cash = $7, commission_credit = $8,
+			  withdrawal_fee = $9, agreement_days = $10, price_drop_days = $11, price_drop_rate = $12,
+			  min_settlement_amount = $13, updated_at = $14
+			  WHERE id = $15`
+	store.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(
+		query,
+		store.Name,
+		store.Address,
+		store.City,
+		store.Phone,
+		store.Email,
+		store.OpeningHours,
+		store.CommissionCash,
+		store.CommissionCredit,
+		store.WithdrawalFee,
+		store.AgreementDays,
+		store.PriceDropDays,
+		store.PriceDropRate,
+		store.MinSettlement,
+		store.UpdatedAt,
+		store.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update store: %w", err)
+	}
+	return nil
+}
+
+// SearchStores retrieves one page of active stores matching the filter, ordered by name,
+// together with the total number of matching stores.
+func (r *StoreRepository) SearchStores(filter StoreFilter) ([]model.Store, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	addCondition("status = $%d", model.StoreStatusActive)
+
+	if filter.Name != "" {
+		addCondition(`name ILIKE '%%' || $%d || '%%' ESCAPE '\'`, escapeLike(filter.Name))
+	}
+	if filter.City != "" {
+		addCondition("LOWER(city) = LOWER($%d)", filter.City)
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM stores `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting stores: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM stores %s ORDER BY name, id LIMIT $%d OFFSET $%d`,
+		storeColumns, where, len(args)-1, len(args))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error searching stores: %w", err)
+	}
+	defer rows.Close()
+
+	stores := []model.Store{}
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error scanning store: %w", err)
+		}
+		stores = append(stores, *store)
+	}
+	return stores, total, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListStores returns the stores in the given status, or all stores when status is empty,
+// oldest first so that the review queue is worked through in order.
+func (r *StoreRepository) ListStores(status model.StoreStatus) ([]model.Store, error) {
+	query := `SELECT ` + storeColumns + ` FROM stores WHERE ($1 = '' OR status = $1) ORDER BY created_at, id`
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stores: %w", err)
+	}
+	defer rows.Close()
+
+	stores := []model.Store{}
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning store: %w", err)
+		}
+		stores = append(stores, *store)
+	}
+	return stores, rows.Err()
+}
+
+// UpdateStoreStatus moves a store from one status to another on an admin's review, recording
+// the reason and the admin. It returns false if the store was no longer in the from status.
+func (r *StoreRepository) UpdateStoreStatus(id int64, from, to model.StoreStatus, reason string, reviewedBy int64, reviewedAt time.Time) (bool, error) {
+	query := `UPDATE stores SET status = $1, status_reason = NULLIF($2, ''), reviewed_by = $3, reviewed_at = $4, updated_at = $4
+			  WHERE id = $5 AND status = $6`
+	result, err := r.db.Exec(query, to, reason, reviewedBy, reviewedAt, id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update store status: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *StoreRepository) getStore(query string, arg interface{}) (*model.Store, error) {
+	store, err := scanStore(r.db.QueryRow(query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No store found is not an application error
+		}
+		return nil, err
+	}
+
+	return store, nil
+}
 
+func scanStore(row rowScanner) (*model.Store, error) {
 	store := &model.Store{}
-	err := r.db.QueryRow(query, userID).Scan(
+	err := row.Scan(
 		&store.ID,
 		&store.UserID,
 		&store.Name,
+		&store.Address,
+		&store.City,
+		&store.Phone,
+		&store.Email,
+		&store.OpeningHours,
 		&store.CommissionCash,
 		&store.CommissionCredit,
+		&store.WithdrawalFee,
+		&store.AgreementDays,
+		&store.PriceDropDays,
+		&store.PriceDropRate,
+		&store.MinSettlement,
+		&store.Status,
+		&store.StatusReason,
+		&store.ReviewedBy,
+		&store.ReviewedAt,
 		&store.CreatedAt,
 		&store.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// CreateCommissionSchedule creates a commission schedule and its rules.
+// Callers should run it on a transaction-bound repository (see WithTx) so the
+// schedule and its rules are written atomically.
+func (r *StoreRepository) CreateCommissionSchedule(schedule *model.CommissionSchedule) error {
+	query := `INSERT INTO commission_schedules (store_id, effective_from, note, created_by, created_at)
+			  VALUES ($1, $2, NULLIF($3, ''), $4, $5) RETURNING id`
+	schedule.CreatedAt = time.Now()
 
+	err := r.db.QueryRow(query, schedule.StoreID, schedule.EffectiveFrom, schedule.Note, schedule.CreatedBy, schedule.CreatedAt).Scan(&schedule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create commission schedule: %w", err)
+	}
+
+	ruleQuery := `INSERT INTO commission_rules (schedule_id, min_price, max_price, rarity, series, commission_cash, commission_credit)
+				  VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7) RETURNING id`
+	for i := range schedule.Rules {
+		rule := &schedule.Rules[i]
+		rule.ScheduleID = schedule.ID
+		err := r.db.QueryRow(ruleQuery, rule.ScheduleID, rule.MinPrice, rule.MaxPrice, rule.Rarity, rule.Series,
+			rule.CommissionCash, rule.CommissionCredit).Scan(&rule.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create commission rule: %w", err)
+		}
+	}
+	return nil
+}
+
+const commissionScheduleColumns = `id, store_id, effective_from, COALESCE(note, ''), created_by, created_at`
+
+// ListCommissionSchedules returns every commission schedule of a store with its rules, latest
+// effective date first.
+func (r *StoreRepository) ListCommissionSchedules(storeID int64) ([]model.CommissionSchedule, error) {
+	query := `SELECT ` + commissionScheduleColumns + ` FROM commission_schedules
+			  WHERE store_id = $1
+			  ORDER BY effective_from DESC`
+	rows, err := r.db.Query(query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing commission schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []model.CommissionSchedule{}
+	var ids []int64
+	for rows.Next() {
+		var schedule model.CommissionSchedule
+		if err := rows.Scan(&schedule.ID, &schedule.StoreID, &schedule.EffectiveFrom, &schedule.Note, &schedule.CreatedBy, &schedule.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning commission schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+		ids = append(ids, schedule.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing commission schedules: %w", err)
+	}
+
+	rules, err := r.listCommissionRules(`WHERE schedule_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	for i := range schedules {
+		schedules[i].Rules = rulesOfSchedule(rules, schedules[i].ID)
+	}
+	return schedules, nil
+}
+
+// GetCommissionSchedule retrieves a commission schedule and its rules by ID.
+func (r *StoreRepository) GetCommissionSchedule(id int64) (*model.CommissionSchedule, error) {
+	return r.getCommissionSchedule(`SELECT `+commissionScheduleColumns+` FROM commission_schedules WHERE id = $1`, id)
+}
+
+// GetCommissionScheduleInForce retrieves the store's commission schedule in force at the given
+// time, with its rules, or nil when the store has none and its base rates apply.
+func (r *StoreRepository) GetCommissionScheduleInForce(storeID int64, at time.Time) (*model.CommissionSchedule, error) {
+	query := `SELECT ` + commissionScheduleColumns + ` FROM commission_schedules
+			  WHERE store_id = $1 AND effective_from <= $2
+			  ORDER BY effective_from DESC
+			  LIMIT 1`
+	return r.getCommissionSchedule(query, storeID, at)
+}
+
+func (r *StoreRepository) getCommissionSchedule(query string, args ...interface{}) (*model.CommissionSchedule, error) {
+	schedule := &model.CommissionSchedule{}
+	err := r.db.QueryRow(query, args...).Scan(
+		&schedule.ID, &schedule.StoreID, &schedule.EffectiveFrom, &schedule.Note, &schedule.CreatedBy, &schedule.CreatedAt,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil // No store found is not an application error
+			return nil, nil // Not found
 		}
+		return nil, fmt.Errorf("error getting commission schedule: %w", err)
+	}
+
+	rules, err := r.listCommissionRules(`WHERE schedule_id = $1`, schedule.ID)
+	if err != nil {
 		return nil, err
 	}
+	schedule.Rules = rules
+	return schedule, nil
+}
 
-	return store, nil
+// DeleteCommissionSchedule deletes a schedule that has not taken effect by now. It returns false
+// if there is no such schedule.
+func (r *StoreRepository) DeleteCommissionSchedule(id int64, now time.Time) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM commission_schedules WHERE id = $1 AND effective_from > $2`, id, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete commission schedule: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// GetCommissionRules returns the given commission rules keyed by ID.
+func (r *StoreRepository) GetCommissionRules(ids []int64) (map[int64]model.CommissionRule, error) {
+	rules := make(map[int64]model.CommissionRule, len(ids))
+	if len(ids) == 0 {
+		return rules, nil
+	}
+
+	list, err := r.listCommissionRules(`WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range list {
+		rules[rule.ID] = rule
+	}
+	return rules, nil
+}
+
+func (r *StoreRepository) listCommissionRules(where string, args ...interface{}) ([]model.CommissionRule, error) {
+	query := `SELECT id, schedule_id, min_price, max_price, COALESCE(rarity, ''), COALESCE(series, ''),
+			  commission_cash, commission_credit
+			  FROM commission_rules ` + where + `
+			  ORDER BY schedule_id, min_price, id`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing commission rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []model.CommissionRule{}
+	for rows.Next() {
+		var rule model.CommissionRule
+		err := rows.Scan(&rule.ID, &rule.ScheduleID, &rule.MinPrice, &rule.MaxPrice, &rule.Rarity, &rule.Series,
+			&rule.CommissionCash, &rule.CommissionCredit)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning commission rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// rulesOfSchedule picks the rules of one schedule out of the rules of several.
+func rulesOfSchedule(rules []model.CommissionRule, scheduleID int64) []model.CommissionRule {
+	picked := []model.CommissionRule{}
+	for _, rule := range rules {
+		if rule.ScheduleID == scheduleID {
+			picked = append(picked, rule)
+		}
+	}
+	return picked
+}
+
+const storeMemberColumns = `m.id, m.store_id, m.user_id, u.email, m.role, m.status, m.invited_by, m.accepted_at,
+	m.created_at, m.updated_at`
+
+// CreateMember adds a user to a store's staff, normally as an invitation.
+func (r *StoreRepository) CreateMember(member *model.StoreMember) error {
+	query := `INSERT INTO store_members (store_id, user_id, role, status, invited_by, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = member.CreatedAt
+
+	err := r.db.QueryRow(
+		query,
+		member.StoreID,
+		member.UserID,
+		member.Role,
+		member.Status,
+		member.InvitedBy,
+		member.CreatedAt,
+		member.UpdatedAt,
+	).Scan(&member.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create store member: %w", err)
+	}
+	return nil
+}
+
+// GetMember retrieves a store membership by its ID.
+func (r *StoreRepository) GetMember(id int64) (*model.StoreMember, error) {
+	query := `SELECT ` + storeMemberColumns + ` FROM store_members m JOIN users u ON u.id = m.user_id WHERE m.id = $1`
+	return r.getMember(query, id)
+}
+
+// GetActiveMembership retrieves the user's active membership, or nil when the user works at no store.
+func (r *StoreRepository) GetActiveMembership(userID int64) (*model.StoreMember, error) {
+	query := `SELECT ` + storeMemberColumns + ` FROM store_members m JOIN users u ON u.id = m.user_id
+			  WHERE m.user_id = $1 AND m.status = 'ACTIVE'`
+	return r.getMember(query, userID)
+}
+
+// GetOpenMembership retrieves the user's pending invitation or active membership at a store, if any.
+func (r *StoreRepository) GetOpenMembership(storeID, userID int64) (*model.StoreMember, error) {
+	query := `SELECT ` + storeMemberColumns + ` FROM store_members m JOIN users u ON u.id = m.user_id
+			  WHERE m.store_id = $1 AND m.user_id = $2 AND m.status IN ('INVITED', 'ACTIVE')`
+	return r.getMember(query, storeID, userID)
+}
+
+// ListMembers returns a store's active staff and pending invitations, oldest first.
+func (r *StoreRepository) ListMembers(storeID int64) ([]model.StoreMember, error) {
+	return r.listMembers(`WHERE m.store_id = $1 AND m.status IN ('INVITED', 'ACTIVE')`, storeID)
+}
+
+// ListInvitations returns the user's pending invitations, oldest first.
+func (r *StoreRepository) ListInvitations(userID int64) ([]model.StoreMember, error) {
+	return r.listMembers(`WHERE m.user_id = $1 AND m.status = 'INVITED'`, userID)
+}
+
+// UpdateMemberStatus moves a membership from one status to another, stamping accepted_at when it
+// becomes active. It returns false if the membership was no longer in the from status.
+func (r *StoreRepository) UpdateMemberStatus(id int64, from, to model.StoreMemberStatus, at time.Time) (bool, error) {
+	query := `UPDATE store_members
+			  SET status = $1, updated_at = $2, accepted_at = CASE WHEN $1 = 'ACTIVE' THEN $2 ELSE accepted_at END
+			  WHERE id = $3 AND status = $4`
+	result, err := r.db.Exec(query, to, at, id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update store member status: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UpdateMemberRole changes the role of a pending or active staff member. Owners are never changed;
+// it returns false if there is no such staff member.
+func (r *StoreRepository) UpdateMemberRole(id int64, role model.StoreRole) (bool, error) {
+	query := `UPDATE store_members SET role = $1, updated_at = $2
+			  WHERE id = $3 AND role <> 'OWNER' AND status IN ('INVITED', 'ACTIVE')`
+	result, err := r.db.Exec(query, role, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update store member role: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *StoreRepository) getMember(query string, args ...interface{}) (*model.StoreMember, error) {
+	member, err := scanMember(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("error getting store member: %w", err)
+	}
+	return member, nil
+}
+
+func (r *StoreRepository) listMembers(where string, arg interface{}) ([]model.StoreMember, error) {
+	query := `SELECT ` + storeMemberColumns + ` FROM store_members m JOIN users u ON u.id = m.user_id ` + where + `
+			  ORDER BY m.created_at, m.id`
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("error listing store members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []model.StoreMember{}
+	for rows.Next() {
+		member, err := scanMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning store member: %w", err)
+		}
+		members = append(members, *member)
+	}
+	return members, rows.Err()
+}
+
+func scanMember(row rowScanner) (*model.StoreMember, error) {
+	member := &model.StoreMember{}
+	err := row.Scan(
+		&member.ID,
+		&member.StoreID,
+		&member.UserID,
+		&member.Email,
+		&member.Role,
+		&member.Status,
+		&member.InvitedBy,
+		&member.AcceptedAt,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
 }
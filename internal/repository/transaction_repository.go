@@ -3,32 +3,53 @@ package repository
 import (
 	"card_manage/internal/model"
 	"database/sql"
+	"fmt"
 	"time"
 )
 
 type TransactionRepository struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+func NewTransactionRepository(db Querier) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *TransactionRepository) WithTx(tx *sql.Tx) *TransactionRepository {
+	return &TransactionRepository{db: tx}
+}
+
 // CreateTransaction inserts a new transaction into the database.
 func (r *TransactionRepository) CreateTransaction(tx *model.Transaction) (int64, error) {
-	query := `INSERT INTO transactions (consignment_item_id, store_id, price, payment_method, commission_rate, created_at)
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	query := `INSERT INTO transactions (consignment_item_id, sale_id, store_id, type, reverses_id, price, quantity, payment_method,
+			  commission_rate, commission_rule_id, condition, grade_company, grade_score, actor_id, buyer_id, reason, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13, $14, $15, NULLIF($16, ''), $17) RETURNING id`
 
+	if tx.Type == "" {
+		tx.Type = model.TransactionTypeSale
+	}
 	tx.CreatedAt = time.Now()
 
 	var transactionID int64
 	err := r.db.QueryRow(
 		query,
 		tx.ConsignmentItemID,
+		tx.SaleID,
 		tx.StoreID,
+		tx.Type,
+		tx.ReversesID,
 		tx.Price,
+		tx.Quantity,
 		tx.PaymentMethod,
 		tx.CommissionRate,
+		tx.CommissionRuleID,
+		tx.Condition,
+		tx.GradeCompany,
+		tx.GradeScore,
+		tx.ActorID,
+		tx.BuyerID,
+		tx.Reason,
 		tx.CreatedAt,
 	).Scan(&transactionID)
 
@@ -38,26 +59,144 @@ func (r *TransactionRepository) CreateTransaction(tx *model.Transaction) (int64,
 	return transactionID, nil
 }
 
-// CreateTransactionInTx inserts a new transaction into the database within a specific transaction.
-func (r *TransactionRepository) CreateTransactionInTx(tx *sql.Tx, transaction *model.Transaction) (int64, error) {
-	query := `INSERT INTO transactions (consignment_item_id, store_id, price, payment_method, commission_rate, created_at)
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+const transactionColumns = `t.id, t.consignment_item_id, t.sale_id, t.store_id, t.type, t.reverses_id,
+	(SELECT r.id FROM transactions r WHERE r.reverses_id = t.id), t.price, t.quantity, t.payment_method,
+	t.commission_rate, t.commission_rule_id, COALESCE(t.condition, ''), COALESCE(t.grade_company, ''), t.grade_score,
+	t.actor_id, t.buyer_id, COALESCE(t.reason, ''), t.voided_at, t.voided_by, COALESCE(t.void_reason, ''), t.created_at`
 
-	transaction.CreatedAt = time.Now()
+func scanTransaction(row rowScanner) (*model.Transaction, error) {
+	tx := &model.Transaction{}
+	err := row.Scan(
+		&tx.ID, &tx.ConsignmentItemID, &tx.SaleID, &tx.StoreID, &tx.Type, &tx.ReversesID, &tx.RefundID,
+		&tx.Price, &tx.Quantity, &tx.PaymentMethod, &tx.CommissionRate, &tx.CommissionRuleID, &tx.Condition, &tx.GradeCompany, &tx.GradeScore,
+		&tx.ActorID, &tx.BuyerID, &tx.Reason, &tx.VoidedAt, &tx.VoidedBy, &tx.VoidReason, &tx.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
 
-	var transactionID int64
-	err := tx.QueryRow(
-		query,
-		transaction.ConsignmentItemID,
-		transaction.StoreID,
-		transaction.Price,
-		transaction.PaymentMethod,
-		transaction.CommissionRate,
-		transaction.CreatedAt,
-	).Scan(&transactionID)
+// GetTransactionForUpdate retrieves a transaction and locks it until the surrounding
+// DB transaction ends, so it cannot be voided or refunded twice concurrently.
+func (r *TransactionRepository) GetTransactionForUpdate(id int64) (*model.Transaction, error) {
+	tx, err := scanTransaction(r.db.QueryRow(`SELECT `+transactionColumns+` FROM transactions t WHERE t.id = $1 FOR UPDATE OF t`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("error getting transaction: %w", err)
+	}
+	return tx, nil
+}
 
+// IsRefunded reports whether a refund reverses the transaction. Called after
+// GetTransactionForUpdate it sees a refund committed while waiting for the lock, which the
+// RefundID loaded with the locked row does not: a refund adds a row but leaves the sale unchanged.
+func (r *TransactionRepository) IsRefunded(id int64) (bool, error) {
+	var refunded bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM transactions WHERE reverses_id = $1)`, id).Scan(&refunded)
 	if err != nil {
-		return 0, err
+		return false, fmt.Errorf("error checking for refund: %w", err)
 	}
-	return transactionID, nil
+	return refunded, nil
+}
+
+// VoidTransaction marks a sale as voided by the given user. It returns false if the
+// sale had already been voided.
+func (r *TransactionRepository) VoidTransaction(id, voidedBy int64, reason string, voidedAt time.Time) (bool, error) {
+	query := `UPDATE transactions SET voided_at = $1, voided_by = $2, void_reason = $3
+			  WHERE id = $4 AND voided_at IS NULL`
+	result, err := r.db.Exec(query, voidedAt, voidedBy, reason, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to void transaction: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CreateSale inserts a sale and gives it a receipt number built from the sale date and its ID,
+// e.g. R20261017-000042, so receipt numbers are unique without a separate counter.
+func (r *TransactionRepository) CreateSale(sale *model.Sale) error {
+	sale.CreatedAt = time.Now()
+	query := `WITH next AS (SELECT nextval(pg_get_serial_sequence('sales', 'id')) AS id)
+			  INSERT INTO sales (id, store_id, receipt_number, total, cashier_id, buyer_id, created_at)
+			  SELECT id, $1, $2 || LPAD(id::text, 6, '0'), $3, $4, $5, $6 FROM next
+			  RETURNING id, receipt_number`
+	prefix := "R" + sale.CreatedAt.Format("20060102") + "-"
+	err := r.db.QueryRow(query, sale.StoreID, prefix, sale.Total, sale.CashierID, sale.BuyerID, sale.CreatedAt).Scan(&sale.ID, &sale.ReceiptNumber)
+	if err != nil {
+		return fmt.Errorf("failed to create sale: %w", err)
+	}
+	return nil
+}
+
+// CreateSaleTender records one payment towards a sale.
+func (r *TransactionRepository) CreateSaleTender(tender *model.SaleTender) error {
+	query := `INSERT INTO sale_tenders (sale_id, payment_method, amount) VALUES ($1, $2, $3) RETURNING id`
+	if err := r.db.QueryRow(query, tender.SaleID, tender.PaymentMethod, tender.Amount).Scan(&tender.ID); err != nil {
+		return fmt.Errorf("failed to create sale tender: %w", err)
+	}
+	return nil
+}
+
+// GetSaleByID retrieves a sale with its transaction lines and tenders.
+func (r *TransactionRepository) GetSaleByID(id int64) (*model.Sale, error) {
+	sale := &model.Sale{}
+	query := `SELECT id, store_id, receipt_number, total, COALESCE(cashier_id, 0), buyer_id, created_at FROM sales WHERE id = $1`
+	err := r.db.QueryRow(query, id).Scan(&sale.ID, &sale.StoreID, &sale.ReceiptNumber, &sale.Total, &sale.CashierID, &sale.BuyerID, &sale.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("error getting sale: %w", err)
+	}
+
+	if sale.Lines, err = r.ListSaleLines(id); err != nil {
+		return nil, err
+	}
+	if sale.Tenders, err = r.listSaleTenders(id); err != nil {
+		return nil, err
+	}
+	return sale, nil
+}
+
+// ListSaleLines returns the transactions of a sale in the order they were rung up.
+func (r *TransactionRepository) ListSaleLines(saleID int64) ([]model.Transaction, error) {
+	rows, err := r.db.Query(`SELECT `+transactionColumns+` FROM transactions t WHERE t.sale_id = $1 ORDER BY t.id`, saleID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sale lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []model.Transaction{}
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning sale line: %w", err)
+		}
+		lines = append(lines, *tx)
+	}
+	return lines, rows.Err()
+}
+
+func (r *TransactionRepository) listSaleTenders(saleID int64) ([]model.SaleTender, error) {
+	rows, err := r.db.Query(`SELECT id, sale_id, payment_method, amount FROM sale_tenders WHERE sale_id = $1 ORDER BY id`, saleID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sale tenders: %w", err)
+	}
+	defer rows.Close()
+
+	tenders := []model.SaleTender{}
+	for rows.Next() {
+		var tender model.SaleTender
+		if err := rows.Scan(&tender.ID, &tender.SaleID, &tender.PaymentMethod, &tender.Amount); err != nil {
+			return nil, fmt.Errorf("error scanning sale tender: %w", err)
+		}
+		tenders = append(tenders, tender)
+	}
+	return tenders, rows.Err()
 }
@@ -19,14 +19,19 @@ var _ IUserRepository = (*UserRepository)(nil)
 
 // UserRepository handles database operations for users.
 type UserRepository struct {
-	db *sql.DB
+	db Querier
 }
 
 // NewUserRepository creates a new UserRepository.
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db Querier) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// WithTx returns a copy of the repository whose queries run inside tx.
+func (r *UserRepository) WithTx(tx *sql.Tx) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
 // CreateUser adds a new user to the database.
 func (r *UserRepository) CreateUser(user *model.User) (int64, error) {
 	query := `INSERT INTO users (email, password_hash, role) VALUES ($1, $2, $3) RETURNING id`
@@ -6,10 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-
-	"github.com/google/uuid"
 )
 
 var (
@@ -32,38 +28,20 @@ func NewCardService(cardRepo *repository.CardRepository, storeRepo *repository.S
 
 // CreateCard creates a new card for the store associated with the given userID.
 func (s *CardService) CreateCard(userID int64, name, series, rarity, cardNumber string, imageContent io.Reader, imageExtension string) (*model.Card, error) {
-	store, err := s.storeRepo.GetStoreByUserID(userID)
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageCards)
 	if err != nil {
-		return nil, fmt.Errorf("error finding store: %w", err)
+		return nil, err
 	}
-	if store == nil {
-		return nil, ErrStoreNotFound
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
 	}
 
 	var imageURL string
 	if imageContent != nil {
-		// Create the uploads directory if it doesn't exist
-		uploadDir := "uploads"
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create upload directory: %w", err)
-		}
-
-		// Generate a unique filename
-		fileName := uuid.New().String() + imageExtension
-		filePath := filepath.Join(uploadDir, fileName)
-
-		// Create the file
-		file, err := os.Create(filePath)
+		imageURL, err = saveUpload(imageContent, imageExtension)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create image file: %w", err)
-		}
-		defer file.Close()
-
-		// Copy the image content to the file
-		if _, err := io.Copy(file, imageContent); err != nil {
-			return nil, fmt.Errorf("failed to save image content: %w", err)
+			return nil, err
 		}
-		imageURL = "/" + filePath // Store a URL-like path
 	}
 
 	newCard := &model.Card{
@@ -73,10 +51,15 @@ func (s *CardService) CreateCard(userID int64, name, series, rarity, cardNumber
 		Rarity:     rarity,
 		CardNumber: cardNumber,
 		ImageURL:   imageURL,
+		CreatedBy:  &userID,
+		UpdatedBy:  &userID,
 	}
 
 	cardID, err := s.cardRepo.CreateCard(newCard)
 	if err != nil {
+		if imageURL != "" {
+			removeUpload(imageURL)
+		}
 		return nil, fmt.Errorf("failed to create card: %w", err)
 	}
 	newCard.ID = cardID
@@ -94,22 +77,25 @@ func (s *CardService) GetCard(userID, cardID int64) (*model.Card, error) {
 		return nil, ErrCardNotFound
 	}
 
-	// Verify ownership
-	if err := s.verifyStoreOwnership(userID, card.StoreID); err != nil {
+	// Verify membership
+	if err := s.verifyStorePermission(userID, card.StoreID, model.PermissionView); err != nil {
 		return nil, err
 	}
 
 	return card, nil
 }
 
-// ListCardsByCurrentUser lists all cards for the current user's store.
+// ListCardsByCurrentUser lists all cards for the store the current user works at.
 func (s *CardService) ListCardsByCurrentUser(userID int64) ([]model.Card, error) {
-	store, err := s.storeRepo.GetStoreByUserID(userID)
+	store, err := memberStore(s.storeRepo, userID, model.PermissionView)
+	if err == ErrStoreNotFound {
+		return []model.Card{}, nil // Return empty slice if no store
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error finding store: %w", err)
+		return nil, err
 	}
-	if store == nil {
-		return []model.Card{}, nil // Return empty slice if no store
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
 	}
 
 	return s.cardRepo.ListCardsByStore(store.ID)
@@ -126,8 +112,8 @@ func (s *CardService) UpdateCard(userID, cardID int64, name, series, rarity, car
 		return nil, ErrCardNotFound
 	}
 
-	// Verify ownership
-	if err := s.verifyStoreOwnership(userID, card.StoreID); err != nil {
+	// Verify membership
+	if err := s.verifyStorePermission(userID, card.StoreID, model.PermissionManageCards); err != nil {
 		return nil, err
 	}
 
@@ -136,6 +122,7 @@ func (s *CardService) UpdateCard(userID, cardID int64, name, series, rarity, car
 	card.Series = series
 	card.Rarity = rarity
 	card.CardNumber = cardNumber
+	card.UpdatedBy = &userID
 
 	if err := s.cardRepo.UpdateCard(card); err != nil {
 		return nil, fmt.Errorf("failed to update card: %w", err)
@@ -146,7 +133,7 @@ func (s *CardService) UpdateCard(userID, cardID int64, name, series, rarity, car
 
 // DeleteCard handles the logic for deleting a card.
 func (s *CardService) DeleteCard(userID, cardID int64) error {
-	// First, get the existing card to verify ownership
+	// First, get the existing card to verify membership
 	card, err := s.cardRepo.GetCardByID(cardID)
 	if err != nil {
 		return fmt.Errorf("error getting card for deletion: %w", err)
@@ -155,8 +142,8 @@ func (s *CardService) DeleteCard(userID, cardID int64) error {
 		return ErrCardNotFound
 	}
 
-	// Verify ownership
-	if err := s.verifyStoreOwnership(userID, card.StoreID); err != nil {
+	// Verify membership
+	if err := s.verifyStorePermission(userID, card.StoreID, model.PermissionManageCards); err != nil {
 		return err
 	}
 
@@ -165,14 +152,15 @@ func (s *CardService) DeleteCard(userID, cardID int64) error {
 }
 
 
-// verifyStoreOwnership is a helper function to check if the user owns the store.
-func (s *CardService) verifyStoreOwnership(userID, storeID int64) error {
-	store, err := s.storeRepo.GetStoreByUserID(userID)
+// verifyStorePermission is a helper function to check if the user works at the store
+// and their role there grants the permission.
+func (s *CardService) verifyStorePermission(userID, storeID int64, permission model.StorePermission) error {
+	store, err := checkStoreMember(s.storeRepo, userID, storeID, permission)
 	if err != nil {
-		return fmt.Errorf("error finding store for verification: %w", err)
+		return err
 	}
-	if store == nil || store.ID != storeID {
-		return ErrForbidden
+	if !store.IsActive() {
+		return ErrStoreNotActive
 	}
 	return nil
 }
@@ -3,8 +3,11 @@ package service
 import (
 	"card_manage/internal/model"
 	"card_manage/internal/repository"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"time"
 )
 
 var (
@@ -12,46 +15,137 @@ var (
 	ErrConsignmentItemNotFound  = errors.New("consignment item not found")
 	ErrInvalidCardForStore      = errors.New("one or more cards do not belong to the selected store")
 	ErrCannotUpdateStatus       = errors.New("status cannot be updated to the desired value")
+	ErrEmptyConsignment         = errors.New("a consignment must contain at least one card")
+	ErrTargetStoreNotFound      = errors.New("store not found")
+	ErrInvalidPrice             = errors.New("price must be greater than zero")
+	ErrPriceNotNegotiable       = errors.New("the item's price can no longer be negotiated")
+	ErrNoOpenProposal           = errors.New("there is no open price proposal from the other party")
+	ErrInvalidCondition         = errors.New("condition must be NM, LP, MP, HP or DMG, with a grade company and a 1-10 score in half points if graded")
+	ErrPhotoNotAllowed          = errors.New("photos can only be added to items under review or on sale")
+	ErrInvalidQuantity          = errors.New("quantity must be between 1 and the item's quantity")
+	ErrEmptyBatch               = errors.New("a batch must contain at least one decision")
+	ErrDuplicateDecision        = errors.New("the item appears more than once in the batch")
+	ErrBatchReviewRefused       = errors.New("no decisions were applied because some were refused")
 )
 
+// ConsignmentItemInput describes one card a player submits for consignment.
+type ConsignmentItemInput struct {
+	CardID   int64
+	Quantity int         // Copies of the card; 0 means one
+	MinPrice model.Money // Optional per-unit price floor; 0 means none
+}
+
+// InvalidCardsError lists the card IDs that cannot be consigned to the chosen store,
+// either because they do not exist or because they belong to another store's catalog.
+// It matches ErrInvalidCardForStore with errors.Is.
+type InvalidCardsError struct {
+	CardIDs []int64
+}
+
+func (e *InvalidCardsError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrInvalidCardForStore, e.CardIDs)
+}
+
+func (e *InvalidCardsError) Unwrap() error {
+	return ErrInvalidCardForStore
+}
+
 type ConsignmentService struct {
 	consignmentRepo *repository.ConsignmentRepository
 	cardRepo        *repository.CardRepository
 	storeRepo       *repository.StoreRepository
+	uow             *UnitOfWork
 }
 
 func NewConsignmentService(
 	consignmentRepo *repository.ConsignmentRepository,
 	cardRepo *repository.CardRepository,
 	storeRepo *repository.StoreRepository,
+	uow *UnitOfWork,
 ) *ConsignmentService {
 	return &ConsignmentService{
 		consignmentRepo: consignmentRepo,
 		cardRepo:        cardRepo,
 		storeRepo:       storeRepo,
+		uow:             uow,
 	}
 }
 
 // CreateConsignment allows a player to create a new consignment request with multiple items.
-func (s *ConsignmentService) CreateConsignment(playerID, storeID int64, cardIDs []int64) (*model.Consignment, error) {
-	// In a real application, you'd validate that all cardIDs are valid and belong to the store.
-	// This is omitted for brevity but is crucial for production code.
+// Several copies of a card are consigned as one item with a quantity; the same card ID may
+// also appear several times, e.g. with different price floors.
+func (s *ConsignmentService) CreateConsignment(playerID, storeID int64, inputs []ConsignmentItemInput) (*model.Consignment, error) {
+	if len(inputs) == 0 {
+		return nil, ErrEmptyConsignment
+	}
+
+	cardIDs := make([]int64, len(inputs))
+	for i, input := range inputs {
+		if input.MinPrice < 0 {
+			return nil, ErrInvalidPrice
+		}
+		if input.Quantity < 0 {
+			return nil, ErrInvalidQuantity
+		}
+		cardIDs[i] = input.CardID
+	}
+
+	// 1. The target store must exist and be open for consignments
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding store: %w", err)
+	}
+	if store == nil {
+		return nil, ErrTargetStoreNotFound
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	// 2. Every card must exist in that store's catalog
+	if err := s.validateCardsForStore(storeID, cardIDs); err != nil {
+		return nil, err
+	}
 
 	consignment := &model.Consignment{
-		PlayerID: playerID,
-		StoreID:  storeID,
-		Status:   model.ConsignmentRequestStatusProcessing,
+		PlayerID:      playerID,
+		StoreID:       storeID,
+		Status:        model.ConsignmentRequestStatusProcessing,
+		AgreementDays: store.AgreementDays, // The player agrees to the store's current terms
 	}
 
 	var items []*model.ConsignmentItem
-	for _, cardID := range cardIDs {
+	for _, input := range inputs {
+		quantity := input.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
 		items = append(items, &model.ConsignmentItem{
-			CardID: cardID,
-			Status: model.ItemStatusPending,
+			CardID:   input.CardID,
+			Quantity: quantity,
+			Status:   model.ItemStatusPending,
+			MinPrice: input.MinPrice,
 		})
 	}
 
-	consignmentID, err := s.consignmentRepo.CreateConsignment(consignment, items)
+	var consignmentID int64
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		var err error
+		consignmentID, err = consignmentRepo.CreateConsignment(consignment, items)
+		if err != nil {
+			return err
+		}
+
+		// Start every item's history with its submission
+		for _, item := range items {
+			if err := recordItemEvent(consignmentRepo, item.ID, "", model.ItemStatusPending, &playerID, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consignment: %w", err)
 	}
@@ -60,9 +154,295 @@ func (s *ConsignmentService) CreateConsignment(playerID, storeID int64, cardIDs
 	return s.consignmentRepo.GetConsignmentByID(consignmentID)
 }
 
-// UpdateConsignmentItemStatus allows a store to approve or reject a specific item.
-func (s *ConsignmentService) UpdateConsignmentItemStatus(storeUserID, itemID int64, newStatus model.ConsignmentItemStatus, reason string) (*model.ConsignmentItem, error) {
-	// 1. Get the item
+// ListPlayerConsignments returns one page of the player's own consignments and the total count.
+func (s *ConsignmentService) ListPlayerConsignments(playerID int64, filter repository.ConsignmentFilter) ([]model.Consignment, int, error) {
+	filter.PlayerID = playerID
+	filter.StoreID = 0
+
+	consignments, total, err := s.consignmentRepo.ListConsignments(filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list consignments: %w", err)
+	}
+	return consignments, total, nil
+}
+
+// ListStoreConsignments returns one page of the consignments addressed to the store storeUserID works at.
+func (s *ConsignmentService) ListStoreConsignments(storeUserID int64, filter repository.ConsignmentFilter) ([]model.Consignment, int, error) {
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionView)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !store.IsActive() {
+		return nil, 0, ErrStoreNotActive
+	}
+
+	filter.StoreID = store.ID
+	filter.PlayerID = 0
+
+	consignments, total, err := s.consignmentRepo.ListConsignments(filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list consignments: %w", err)
+	}
+	return consignments, total, nil
+}
+
+// GetConsignment returns a consignment with its items if the user is either the
+// player who submitted it or the staff of the store it was addressed to.
+func (s *ConsignmentService) GetConsignment(userID, consignmentID int64) (*model.Consignment, error) {
+	consignment, err := s.consignmentRepo.GetConsignmentByID(consignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting consignment: %w", err)
+	}
+	if consignment == nil {
+		return nil, ErrConsignmentNotFound
+	}
+
+	if err := s.verifyConsignmentAccess(userID, consignment); err != nil {
+		return nil, err
+	}
+	return consignment, nil
+}
+
+// GetConsignmentItemHistory returns every status change of an item, oldest first.
+// Like GetConsignment, it is only visible to the submitting player and the receiving store.
+func (s *ConsignmentService) GetConsignmentItemHistory(userID, itemID int64) ([]model.ConsignmentItemEvent, error) {
+	if err := s.verifyItemAccess(userID, itemID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.consignmentRepo.ListConsignmentItemEvents(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item history: %w", err)
+	}
+	return events, nil
+}
+
+// ItemReview is a store's decision on a pending consignment item.
+type ItemReview struct {
+	Status model.ConsignmentItemStatus // APPROVED or REJECTED
+	Reason string                      // Shown on rejected units
+	Grade  model.ConditionGrade        // Required whenever units are approved
+	Price  model.Money                 // Optional listed price proposed to the player for the approved units
+	// Quantity is the number of units the status applies to; 0 means all of them. The remaining
+	// units are split off into a new item with the opposite decision, so approving 10 of 12
+	// rejects the other 2.
+	Quantity int
+}
+
+// validate checks the parts of a review that do not depend on the item.
+func (r ItemReview) validate() error {
+	if r.Status != model.ItemStatusApproved && r.Status != model.ItemStatusRejected {
+		return fmt.Errorf("%w: can only change to APPROVED or REJECTED", ErrCannotUpdateStatus)
+	}
+	if r.Quantity < 0 {
+		return ErrInvalidQuantity
+	}
+	if r.Price < 0 {
+		return ErrInvalidPrice
+	}
+	if r.Status == model.ItemStatusApproved && !r.Grade.Valid() {
+		return ErrInvalidCondition
+	}
+	return nil
+}
+
+// ItemDecision is one entry of a batch review.
+type ItemDecision struct {
+	ItemID int64
+	ItemReview
+}
+
+// ItemReviewResult is the outcome of one decision in a batch review.
+type ItemReviewResult struct {
+	ItemID int64                  `json:"item_id"`
+	Item   *model.ConsignmentItem `json:"item,omitempty"`  // The reviewed item, set once the batch is applied
+	Error  string                 `json:"error,omitempty"` // Why this decision was refused
+}
+
+// UpdateConsignmentItemStatus allows a store to approve or reject a specific item, in whole or in part.
+// Approving requires the condition grade the store assessed; it is ignored on rejection.
+// The returned item carries the units the requested status was applied to.
+func (s *ConsignmentService) UpdateConsignmentItemStatus(storeUserID, itemID int64, review ItemReview) (*model.ConsignmentItem, error) {
+	if err := review.validate(); err != nil {
+		return nil, err
+	}
+
+	var item *model.ConsignmentItem
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		var err error
+		item, err = s.reviewItem(s.consignmentRepo.WithTx(tx), storeUserID, itemID, review)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ReviewItems applies a store's decisions on many items at once, all in one DB transaction:
+// either every decision is applied or, if any is refused, none is. It always returns one result
+// per decision, in order. When the batch is refused the error is ErrBatchReviewRefused and the
+// refused decisions carry their reason; the first decision that fails while applying stops the batch.
+func (s *ConsignmentService) ReviewItems(storeUserID int64, decisions []ItemDecision) ([]ItemReviewResult, error) {
+	if len(decisions) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	// 1. Check every decision up front so the store sees all malformed ones together
+	results := make([]ItemReviewResult, len(decisions))
+	seen := make(map[int64]bool, len(decisions))
+	refused := false
+	for i, decision := range decisions {
+		results[i].ItemID = decision.ItemID
+		err := decision.validate()
+		if err == nil && seen[decision.ItemID] {
+			err = ErrDuplicateDecision
+		}
+		seen[decision.ItemID] = true
+		if err != nil {
+			results[i].Error = err.Error()
+			refused = true
+		}
+	}
+	if refused {
+		return results, ErrBatchReviewRefused
+	}
+
+	// 2. Apply them in order; any failure rolls the whole batch back
+	failed := -1
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+		for i, decision := range decisions {
+			item, err := s.reviewItem(consignmentRepo, storeUserID, decision.ItemID, decision.ItemReview)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i].Item = item
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Item = nil
+		}
+		if failed < 0 || !isRefusal(err) {
+			return nil, err // Not the decision's fault, e.g. the database failed
+		}
+		results[failed].Error = err.Error()
+		return results, fmt.Errorf("%w: %w", ErrBatchReviewRefused, err)
+	}
+	return results, nil
+}
+
+// isRefusal reports whether a review failed because of the decision itself rather than an internal error.
+func isRefusal(err error) bool {
+	for _, refusal := range []error{
+		ErrConsignmentItemNotFound, ErrConsignmentNotFound, ErrForbidden, ErrPermissionDenied,
+		ErrStoreNotActive, ErrCannotUpdateStatus, ErrInvalidQuantity, ErrInvalidCondition,
+	} {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewItem applies a review inside the caller's DB transaction and rolls it up to the request.
+// The review must already have passed validate.
+func (s *ConsignmentService) reviewItem(consignmentRepo *repository.ConsignmentRepository, storeUserID, itemID int64, review ItemReview) (*model.ConsignmentItem, error) {
+	// 1. Get and lock the item, with its parent consignment for the store ID
+	item, consignment, err := lockItem(consignmentRepo, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Verify membership; only items still awaiting intake can be reviewed
+	if err := s.verifyStorePermission(storeUserID, consignment.StoreID, model.PermissionIntake); err != nil {
+		return nil, err
+	}
+	if item.Status != model.ItemStatusPending {
+		return nil, fmt.Errorf("%w: only pending items can be reviewed", ErrCannotUpdateStatus)
+	}
+
+	// 3. Work out how many units get each decision
+	quantity := review.Quantity
+	if quantity == 0 {
+		quantity = item.Quantity
+	}
+	if quantity < 0 || quantity > item.Quantity {
+		return nil, ErrInvalidQuantity
+	}
+	remainder := item.Quantity - quantity
+	approvesUnits := review.Status == model.ItemStatusApproved || remainder > 0
+	if approvesUnits && !review.Grade.Valid() {
+		return nil, ErrInvalidCondition
+	}
+
+	// 4. Split off the remaining units and give them the opposite decision
+	if remainder > 0 {
+		if !item.Status.CanTransitionTo(review.Status) {
+			return nil, fmt.Errorf("%w: cannot move item from %s to %s", ErrCannotUpdateStatus, item.Status, review.Status)
+		}
+		rest, err := splitItem(consignmentRepo, item, remainder, &storeUserID)
+		if err != nil {
+			return nil, err
+		}
+		opposite := model.ItemStatusRejected
+		if review.Status == model.ItemStatusRejected {
+			opposite = model.ItemStatusApproved
+		}
+		if err := applyReview(consignmentRepo, rest, consignment, opposite, review, storeUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Apply the decision and roll it up to the request
+	if err := applyReview(consignmentRepo, item, consignment, review.Status, review, storeUserID); err != nil {
+		return nil, err
+	}
+	if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+		return nil, fmt.Errorf("failed to refresh consignment status: %w", err)
+	}
+	return item, nil
+}
+
+// applyReview moves a locked pending item to APPROVED or REJECTED. Approval records the
+// condition grade and puts the item on sale.
+func applyReview(consignmentRepo *repository.ConsignmentRepository, item *model.ConsignmentItem, consignment *model.Consignment, status model.ConsignmentItemStatus, review ItemReview, actorID int64) error {
+	if err := transitionItem(consignmentRepo, item.ID, item.Status, status, &actorID, review.Reason); err != nil {
+		return err
+	}
+	item.Status = status
+
+	if status == model.ItemStatusRejected {
+		item.RejectionReason = review.Reason
+		return nil
+	}
+	if err := consignmentRepo.SetItemCondition(item.ID, review.Grade); err != nil {
+		return err
+	}
+	item.ConditionGrade = review.Grade
+	if err := startListing(consignmentRepo, item, consignment, time.Now()); err != nil {
+		return err
+	}
+
+	// The store's price is only an offer; it becomes the listed price once the player accepts it
+	if review.Price > 0 {
+		proposal := &model.PriceProposal{
+			ConsignmentItemID: item.ID,
+			ProposedBy:        model.PricePartyStore,
+			ProposerID:        actorID,
+			Price:             review.Price,
+		}
+		return consignmentRepo.CreatePriceProposal(proposal)
+	}
+	return nil
+}
+
+// AddItemPhoto stores an intake photo of an item. Only the receiving store can add photos,
+// while the item is under review or on sale.
+func (s *ConsignmentService) AddItemPhoto(storeUserID, itemID int64, content io.Reader, extension string) (*model.ConsignmentItemPhoto, error) {
 	item, err := s.consignmentRepo.GetConsignmentItemByID(itemID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting item: %w", err)
@@ -70,47 +450,518 @@ func (s *ConsignmentService) UpdateConsignmentItemStatus(storeUserID, itemID int
 	if item == nil {
 		return nil, ErrConsignmentItemNotFound
 	}
-
-	// 2. Get the parent consignment to find the store ID for verification
 	consignment, err := s.consignmentRepo.GetConsignmentByID(item.ConsignmentID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting parent consignment: %w", err)
 	}
 	if consignment == nil {
-		return nil, ErrConsignmentNotFound // Should not happen if item exists
+		return nil, ErrConsignmentNotFound
+	}
+	if err := s.verifyStorePermission(storeUserID, consignment.StoreID, model.PermissionIntake); err != nil {
+		return nil, err
+	}
+	if item.Status != model.ItemStatusPending && item.Status != model.ItemStatusApproved {
+		return nil, ErrPhotoNotAllowed
 	}
 
-	// 3. Verify ownership
-	if err := s.verifyStoreOwnership(storeUserID, consignment.StoreID); err != nil {
+	url, err := saveUpload(content, extension)
+	if err != nil {
 		return nil, err
 	}
+	photo := &model.ConsignmentItemPhoto{ConsignmentItemID: itemID, URL: url, UploadedBy: storeUserID}
+	if err := s.consignmentRepo.CreateConsignmentItemPhoto(photo); err != nil {
+		removeUpload(url)
+		return nil, err
+	}
+	return photo, nil
+}
 
-	// 4. Validate status transition (can only approve/reject from pending)
-	if item.Status != model.ItemStatusPending {
-		return nil, fmt.Errorf("%w: current status is %s", ErrCannotUpdateStatus, item.Status)
+// ListItemPhotos returns an item's intake photos to the submitting player or the receiving store.
+func (s *ConsignmentService) ListItemPhotos(userID, itemID int64) ([]model.ConsignmentItemPhoto, error) {
+	if err := s.verifyItemAccess(userID, itemID); err != nil {
+		return nil, err
 	}
-	if newStatus != model.ItemStatusApproved && newStatus != model.ItemStatusRejected {
-		return nil, fmt.Errorf("%w: can only change to APPROVED or REJECTED", ErrCannotUpdateStatus)
+
+	photos, err := s.consignmentRepo.ListConsignmentItemPhotos(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item photos: %w", err)
 	}
+	return photos, nil
+}
+
+// CancelConsignmentItem lets the player withdraw an item the store has not reviewed yet.
+func (s *ConsignmentService) CancelConsignmentItem(playerID, itemID int64) (*model.ConsignmentItem, error) {
+	return s.playerItemAction(playerID, itemID, model.ItemStatusCancelled)
+}
+
+// RequestItemWithdrawal lets the player take an approved item off sale and ask for it back.
+// The item stays WITHDRAWN until the store confirms the card was handed over.
+func (s *ConsignmentService) RequestItemWithdrawal(playerID, itemID int64) (*model.ConsignmentItem, error) {
+	return s.playerItemAction(playerID, itemID, model.ItemStatusWithdrawn)
+}
 
-	// 5. Update the status
-	if err := s.consignmentRepo.UpdateConsignmentItemStatus(itemID, newStatus, reason); err != nil {
-		return nil, fmt.Errorf("failed to update item status: %w", err)
+// playerItemAction moves one of the player's own items to the given status.
+func (s *ConsignmentService) playerItemAction(playerID, itemID int64, newStatus model.ConsignmentItemStatus) (*model.ConsignmentItem, error) {
+	var item *model.ConsignmentItem
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		var consignment *model.Consignment
+		var err error
+		item, consignment, err = lockItem(consignmentRepo, itemID)
+		if err != nil {
+			return err
+		}
+		if consignment.PlayerID != playerID {
+			return ErrForbidden
+		}
+
+		if err := transitionItem(consignmentRepo, itemID, item.Status, newStatus, &playerID, ""); err != nil {
+			return err
+		}
+		if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+			return fmt.Errorf("failed to refresh consignment status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	item.Status = newStatus
-	item.RejectionReason = reason
 	return item, nil
 }
 
-// verifyStoreOwnership is a helper function to check if the user owns the store.
-func (s *ConsignmentService) verifyStoreOwnership(userID, storeID int64) error {
-	store, err := s.storeRepo.GetStoreByUserID(userID)
+// RelistItem puts an expired item back on sale for a new agreement period.
+func (s *ConsignmentService) RelistItem(playerID, itemID int64) (*model.ConsignmentItem, error) {
+	var item *model.ConsignmentItem
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		var consignment *model.Consignment
+		var err error
+		item, consignment, err = lockItem(consignmentRepo, itemID)
+		if err != nil {
+			return err
+		}
+		if consignment.PlayerID != playerID {
+			return ErrForbidden
+		}
+		if item.Status != model.ItemStatusExpired {
+			return fmt.Errorf("%w: only expired items can be re-listed", ErrCannotUpdateStatus)
+		}
+
+		if err := transitionItem(consignmentRepo, itemID, item.Status, model.ItemStatusApproved, &playerID, "re-listed"); err != nil {
+			return err
+		}
+		if err := startListing(consignmentRepo, item, consignment, time.Now()); err != nil {
+			return err
+		}
+		if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+			return fmt.Errorf("failed to refresh consignment status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status = model.ItemStatusApproved
+	return item, nil
+}
+
+// ListExpiringItems returns the items on sale at the user's store whose agreement period ends
+// within the given duration, soonest first.
+func (s *ConsignmentService) ListExpiringItems(storeUserID int64, within time.Duration) ([]model.ConsignmentItem, error) {
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	items, err := s.consignmentRepo.ListExpiringItems(store.ID, time.Now().Add(within))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring items: %w", err)
+	}
+	return items, nil
+}
+
+// ExpireItems moves every item whose agreement period ended by now to EXPIRED. Each item is
+// expired in its own DB transaction and re-checked under lock, so an item sold or withdrawn
+// in the meantime is left alone. A failing item does not stop the others; all failures are
+// returned together. It returns the number of items expired.
+func (s *ConsignmentService) ExpireItems(now time.Time) (int, error) {
+	itemIDs, err := s.consignmentRepo.ListExpiredItemIDs(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, itemID := range itemIDs {
+		err := s.uow.Do(func(tx *sql.Tx) error {
+			consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+			item, consignment, err := lockItem(consignmentRepo, itemID)
+			if err != nil {
+				return err
+			}
+			if item.Status != model.ItemStatusApproved || item.ExpiresAt == nil || item.ExpiresAt.After(now) {
+				return nil
+			}
+
+			if err := transitionItem(consignmentRepo, itemID, item.Status, model.ItemStatusExpired, nil, "agreement period ended"); err != nil {
+				return err
+			}
+			if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+				return fmt.Errorf("failed to refresh consignment status: %w", err)
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to expire item %d: %w", itemID, err))
+		}
+	}
+	return expired, errors.Join(errs...)
+}
+
+// ApplyPriceDrops lowers the listed price of items on sale at stores with an automatic
+// price-drop policy, one step per PriceDropDays since listing, never below the player's
+// minimum price. It returns the number of items updated.
+func (s *ConsignmentService) ApplyPriceDrops(now time.Time) (int, error) {
+	candidates, err := s.consignmentRepo.ListPriceDropCandidates()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	var errs []error
+	for _, c := range candidates {
+		due := c.Store.PriceDropsDue(*c.Item.ListedAt, now)
+		if due <= c.Item.PriceDrops {
+			continue
+		}
+
+		newPrice := model.DropPrice(c.Item.ListedPrice, c.Store.PriceDropRate, due-c.Item.PriceDrops, c.Item.MinPrice)
+		changed, err := s.consignmentRepo.ApplyPriceDrop(&c.Item, newPrice, due)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to drop price of item %d: %w", c.Item.ID, err))
+			continue
+		}
+		if changed {
+			updated++
+		}
+	}
+	return updated, errors.Join(errs...)
+}
+
+// ConfirmItemReturn is called by the store once a withdrawn or expired card is physically back
+// with the player. For withdrawals, if the store charges a withdrawal fee, it is recorded against
+// the player and deducted from their next settlement with this store.
+func (s *ConsignmentService) ConfirmItemReturn(storeUserID, itemID int64) (*model.ConsignmentItem, error) {
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionIntake)
+	if err == ErrStoreNotFound {
+		return nil, ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	var item *model.ConsignmentItem
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		var consignment *model.Consignment
+		var err error
+		item, consignment, err = lockItem(consignmentRepo, itemID)
+		if err != nil {
+			return err
+		}
+		if consignment.StoreID != store.ID {
+			return ErrForbidden
+		}
+
+		withdrawn := item.Status == model.ItemStatusWithdrawn
+		if err := transitionItem(consignmentRepo, itemID, item.Status, model.ItemStatusReturned, &storeUserID, ""); err != nil {
+			return err
+		}
+
+		if withdrawn && store.WithdrawalFee > 0 {
+			fee := &model.WithdrawalFee{
+				ConsignmentItemID: itemID,
+				PlayerID:          consignment.PlayerID,
+				StoreID:           store.ID,
+				Amount:            store.WithdrawalFee.Mul(int64(item.Quantity)), // Charged per copy
+			}
+			if err := consignmentRepo.CreateWithdrawalFee(fee); err != nil {
+				return err
+			}
+		}
+
+		if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+			return fmt.Errorf("failed to refresh consignment status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status = model.ItemStatusReturned
+	return item, nil
+}
+
+// ProposePrice records a price offer for an item. The store may propose a price at any time while
+// the item is pending review or on sale; the player may only counter an open store proposal.
+// A new proposal supersedes the open one.
+func (s *ConsignmentService) ProposePrice(userID, itemID int64, price model.Money) (*model.PriceProposal, error) {
+	if price <= 0 {
+		return nil, ErrInvalidPrice
+	}
+
+	var proposal *model.PriceProposal
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		item, consignment, err := lockItem(consignmentRepo, itemID)
+		if err != nil {
+			return err
+		}
+		party, err := s.priceParty(userID, consignment)
+		if err != nil {
+			return err
+		}
+		if !isNegotiable(item.Status) {
+			return ErrPriceNotNegotiable
+		}
+
+		if party == model.PricePartyPlayer {
+			open, err := consignmentRepo.GetOpenPriceProposal(itemID)
+			if err != nil {
+				return err
+			}
+			if open == nil || open.ProposedBy != model.PricePartyStore {
+				return ErrNoOpenProposal
+			}
+		}
+
+		proposal = &model.PriceProposal{
+			ConsignmentItemID: itemID,
+			ProposedBy:        party,
+			ProposerID:        userID,
+			Price:             price,
+		}
+		return consignmentRepo.CreatePriceProposal(proposal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// AcceptPriceProposal accepts the other party's open proposal, which becomes the item's listed price.
+// When the player accepts a store price below their own minimum, that price becomes the new floor.
+// For an item on sale the automatic price drops start over from the agreed price, counted from
+// now; its agreement period is unchanged.
+func (s *ConsignmentService) AcceptPriceProposal(userID, itemID int64) (*model.ConsignmentItem, error) {
+	var item *model.ConsignmentItem
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		var consignment *model.Consignment
+		var err error
+		item, consignment, err = lockItem(consignmentRepo, itemID)
+		if err != nil {
+			return err
+		}
+		party, err := s.priceParty(userID, consignment)
+		if err != nil {
+			return err
+		}
+		if !isNegotiable(item.Status) {
+			return ErrPriceNotNegotiable
+		}
+
+		open, err := consignmentRepo.GetOpenPriceProposal(itemID)
+		if err != nil {
+			return err
+		}
+		if open == nil || open.ProposedBy == party {
+			return ErrNoOpenProposal
+		}
+
+		item.ListedPrice = open.Price
+		if party == model.PricePartyPlayer && item.MinPrice > open.Price {
+			item.MinPrice = open.Price
+		}
+		if err := consignmentRepo.UpdatePriceProposalStatus(open.ID, model.ProposalStatusAccepted); err != nil {
+			return err
+		}
+		if err := consignmentRepo.UpdateConsignmentItemPrices(itemID, item.ListedPrice, item.MinPrice); err != nil {
+			return err
+		}
+		if item.ListedAt == nil {
+			return nil // Not on sale yet; drops count from when it is listed
+		}
+		now := time.Now()
+		item.ListedAt = &now
+		item.PriceDrops = 0
+		return consignmentRepo.StartItemListing(itemID, now, item.ExpiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListPriceProposals returns the price negotiation of an item, oldest first.
+func (s *ConsignmentService) ListPriceProposals(userID, itemID int64) ([]model.PriceProposal, error) {
+	if err := s.verifyItemAccess(userID, itemID); err != nil {
+		return nil, err
+	}
+
+	proposals, err := s.consignmentRepo.ListPriceProposals(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price proposals: %w", err)
+	}
+	return proposals, nil
+}
+
+// priceParty tells whether the user negotiates as the consignment's player or as its store.
+func (s *ConsignmentService) priceParty(userID int64, consignment *model.Consignment) (model.PriceParty, error) {
+	if consignment.PlayerID == userID {
+		return model.PricePartyPlayer, nil
+	}
+	if err := s.verifyStorePermission(userID, consignment.StoreID, model.PermissionIntake); err != nil {
+		return "", err
+	}
+	return model.PricePartyStore, nil
+}
+
+// isNegotiable reports whether an item's price may still change: while it awaits review or is on sale.
+func isNegotiable(status model.ConsignmentItemStatus) bool {
+	return status == model.ItemStatusPending || status == model.ItemStatusApproved
+}
+
+// startListing records that an item went on sale at listedAt and when its agreement period ends.
+func startListing(consignmentRepo *repository.ConsignmentRepository, item *model.ConsignmentItem, consignment *model.Consignment, listedAt time.Time) error {
+	item.ListedAt = &listedAt
+	item.ExpiresAt = consignment.ExpiryFor(listedAt)
+	item.PriceDrops = 0
+	return consignmentRepo.StartItemListing(item.ID, listedAt, item.ExpiresAt)
+}
+
+// lockItem locks a consignment item for the rest of the DB transaction and loads its parent consignment.
+func lockItem(consignmentRepo *repository.ConsignmentRepository, itemID int64) (*model.ConsignmentItem, *model.Consignment, error) {
+	item, err := consignmentRepo.GetConsignmentItemForUpdate(itemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting item: %w", err)
+	}
+	if item == nil {
+		return nil, nil, ErrConsignmentItemNotFound
+	}
+
+	consignment, err := consignmentRepo.GetConsignmentByID(item.ConsignmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting parent consignment: %w", err)
+	}
+	if consignment == nil {
+		return nil, nil, ErrConsignmentNotFound // Should not happen if item exists
+	}
+	return item, consignment, nil
+}
+
+// validateCardsForStore looks up all requested cards in one query and returns an
+// *InvalidCardsError naming every ID that is unknown or belongs to another store.
+func (s *ConsignmentService) validateCardsForStore(storeID int64, cardIDs []int64) error {
+	uniqueIDs := uniqueInt64s(cardIDs)
+
+	cards, err := s.cardRepo.GetCardsByIDs(uniqueIDs)
+	if err != nil {
+		return fmt.Errorf("error validating cards: %w", err)
+	}
+
+	if invalid := findInvalidCardIDs(storeID, uniqueIDs, cards); len(invalid) > 0 {
+		return &InvalidCardsError{CardIDs: invalid}
+	}
+	return nil
+}
+
+// findInvalidCardIDs returns the requested IDs, in request order, that have no matching card in the store.
+func findInvalidCardIDs(storeID int64, requestedIDs []int64, cards []model.Card) []int64 {
+	storeCards := make(map[int64]bool, len(cards))
+	for _, card := range cards {
+		if card.StoreID == storeID {
+			storeCards[card.ID] = true
+		}
+	}
+
+	var invalid []int64
+	for _, id := range requestedIDs {
+		if !storeCards[id] {
+			invalid = append(invalid, id)
+		}
+	}
+	return invalid
+}
+
+// uniqueInt64s returns the distinct values of ids, keeping their first-seen order.
+func uniqueInt64s(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// verifyItemAccess applies verifyConsignmentAccess to the consignment an item belongs to.
+func (s *ConsignmentService) verifyItemAccess(userID, itemID int64) error {
+	item, err := s.consignmentRepo.GetConsignmentItemByID(itemID)
+	if err != nil {
+		return fmt.Errorf("error getting item: %w", err)
+	}
+	if item == nil {
+		return ErrConsignmentItemNotFound
+	}
+
+	consignment, err := s.consignmentRepo.GetConsignmentByID(item.ConsignmentID)
+	if err != nil {
+		return fmt.Errorf("error getting parent consignment: %w", err)
+	}
+	if consignment == nil {
+		return ErrConsignmentNotFound
+	}
+	return s.verifyConsignmentAccess(userID, consignment)
+}
+
+// verifyConsignmentAccess allows the player who submitted the consignment and the staff of the
+// store it was addressed to.
+func (s *ConsignmentService) verifyConsignmentAccess(userID int64, consignment *model.Consignment) error {
+	if consignment.PlayerID == userID {
+		return nil
+	}
+	return s.verifyStorePermission(userID, consignment.StoreID, model.PermissionView)
+}
+
+// verifyStorePermission is a helper function to check if the user works at the store
+// and their role there grants the permission.
+func (s *ConsignmentService) verifyStorePermission(userID, storeID int64, permission model.StorePermission) error {
+	store, err := checkStoreMember(s.storeRepo, userID, storeID, permission)
 	if err != nil {
-		return fmt.Errorf("error finding store for verification: %w", err)
+		return err
 	}
-	if store == nil || store.ID != storeID {
-		return ErrForbidden
+	if !store.IsActive() {
+		return ErrStoreNotActive
 	}
 	return nil
 }
\ No newline at end of file
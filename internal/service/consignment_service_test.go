@@ -0,0 +1,501 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindInvalidCardIDs(t *testing.T) {
+	const storeID = 1
+	cards := []model.Card{
+		{ID: 10, StoreID: storeID},
+		{ID: 11, StoreID: storeID},
+		{ID: 20, StoreID: 2}, // Another store's catalog
+	}
+
+	t.Run("all cards belong to the store", func(t *testing.T) {
+		assert.Empty(t, findInvalidCardIDs(storeID, []int64{10, 11}, cards))
+	})
+
+	t.Run("unknown and foreign cards are reported in request order", func(t *testing.T) {
+		invalid := findInvalidCardIDs(storeID, []int64{99, 10, 20}, cards)
+		assert.Equal(t, []int64{99, 20}, invalid)
+	})
+}
+
+func TestUniqueInt64s(t *testing.T) {
+	assert.Equal(t, []int64{3, 1, 2}, uniqueInt64s([]int64{3, 1, 3, 2, 1}))
+	assert.Empty(t, uniqueInt64s(nil))
+}
+
+func TestInvalidCardsError(t *testing.T) {
+	var err error = &InvalidCardsError{CardIDs: []int64{7}}
+	assert.True(t, errors.Is(err, ErrInvalidCardForStore))
+}
+
+func TestItemReviewValidate(t *testing.T) {
+	assert.NoError(t, approveNearMint.validate())
+	assert.NoError(t, ItemReview{Status: model.ItemStatusRejected}.validate(), "rejections need no grade")
+	assert.ErrorIs(t, ItemReview{Status: model.ItemStatusSold}.validate(), ErrCannotUpdateStatus)
+	assert.Equal(t, ErrInvalidCondition, ItemReview{Status: model.ItemStatusApproved}.validate())
+
+	review := approveNearMint
+	review.Quantity = -1
+	assert.Equal(t, ErrInvalidQuantity, review.validate())
+
+	review = approveNearMint
+	review.Price = -100
+	assert.Equal(t, ErrInvalidPrice, review.validate())
+}
+
+// approveNearMint approves a whole item as Near Mint.
+var approveNearMint = ItemReview{Status: model.ItemStatusApproved, Grade: model.ConditionGrade{Condition: model.ConditionNearMint}}
+
+func newTestConsignmentService(t *testing.T) (*ConsignmentService, *testFixture) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+
+	svc := NewConsignmentService(
+		repository.NewConsignmentRepository(db),
+		repository.NewCardRepository(db),
+		repository.NewStoreRepository(db),
+		NewUnitOfWork(db),
+	)
+	return svc, f
+}
+
+func TestConsignmentService_ItemHistory(t *testing.T) {
+	t.Run("rejection keeps its reason and is recorded", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		item, err := svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, ItemReview{Status: model.ItemStatusRejected, Reason: "damaged corner"})
+		assert.NoError(t, err)
+		assert.Equal(t, "damaged corner", item.RejectionReason)
+
+		events, err := svc.GetConsignmentItemHistory(f.player.ID, itemID)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, model.ItemStatusPending, events[0].ToStatus)
+			assert.Equal(t, model.ItemStatusPending, events[1].FromStatus)
+			assert.Equal(t, model.ItemStatusRejected, events[1].ToStatus)
+			assert.Equal(t, "damaged corner", events[1].Reason)
+			assert.Equal(t, f.storeUser.ID, *events[1].ActorID)
+		}
+	})
+
+	t.Run("illegal transitions are refused", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, approveNearMint)
+		assert.NoError(t, err)
+
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, ItemReview{Status: model.ItemStatusRejected, Reason: "changed my mind"})
+		assert.ErrorIs(t, err, ErrCannotUpdateStatus)
+
+		events, err := svc.GetConsignmentItemHistory(f.storeUser.ID, itemID)
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+}
+
+func TestConsignmentService_Condition(t *testing.T) {
+	t.Run("approval requires a valid condition and records it", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, ItemReview{Status: model.ItemStatusApproved})
+		assert.ErrorIs(t, err, ErrInvalidCondition)
+
+		score := 9.5
+		graded := model.ConditionGrade{Condition: model.ConditionNearMint, GradeCompany: "PSA", GradeScore: &score}
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, ItemReview{Status: model.ItemStatusApproved, Grade: graded})
+		assert.NoError(t, err)
+
+		// The player sees the grade on their consignment
+		consignment, err = svc.GetConsignment(f.player.ID, consignment.ID)
+		assert.NoError(t, err)
+		item := consignment.Items[0]
+		assert.Equal(t, model.ConditionNearMint, item.Condition)
+		assert.Equal(t, "PSA", item.GradeCompany)
+		if assert.NotNil(t, item.GradeScore) {
+			assert.Equal(t, 9.5, *item.GradeScore)
+		}
+	})
+
+	t.Run("partial approval rejects the remaining units", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID, Quantity: 12}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		review := approveNearMint
+		review.Quantity = 13
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, review)
+		assert.Equal(t, ErrInvalidQuantity, err)
+
+		review.Quantity = 10
+		review.Reason = "bent corners"
+		item, err := svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, review)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, item.Quantity)
+		assert.Equal(t, model.ItemStatusApproved, item.Status)
+
+		consignment, err = svc.GetConsignment(f.player.ID, consignment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ConsignmentRequestStatusPartiallyApproved, consignment.Status)
+		if assert.Len(t, consignment.Items, 2) {
+			rejected := consignment.Items[1]
+			assert.Equal(t, 2, rejected.Quantity)
+			assert.Equal(t, model.ItemStatusRejected, rejected.Status)
+			assert.Equal(t, "bent corners", rejected.RejectionReason)
+			assert.Equal(t, itemID, *rejected.SplitFromID)
+		}
+	})
+
+	t.Run("photos are attached by the store and visible to the player", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+		t.Chdir(t.TempDir()) // Photos are written under ./uploads
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		_, err = svc.AddItemPhoto(f.player.ID, itemID, strings.NewReader("front"), ".jpg")
+		assert.Equal(t, ErrForbidden, err)
+
+		photo, err := svc.AddItemPhoto(f.storeUser.ID, itemID, strings.NewReader("front"), ".jpg")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(photo.URL, "/uploads/"))
+
+		photos, err := svc.ListItemPhotos(f.player.ID, itemID)
+		assert.NoError(t, err)
+		assert.Len(t, photos, 1)
+
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, ItemReview{Status: model.ItemStatusRejected, Reason: "fake"})
+		assert.NoError(t, err)
+		_, err = svc.AddItemPhoto(f.storeUser.ID, itemID, strings.NewReader("back"), ".jpg")
+		assert.Equal(t, ErrPhotoNotAllowed, err)
+	})
+}
+
+func TestConsignmentService_ReviewItems(t *testing.T) {
+	t.Run("a refused decision rolls back the whole batch", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}, {CardID: f.card.ID}})
+		assert.NoError(t, err)
+		first, second := consignment.Items[0].ID, consignment.Items[1].ID
+
+		// The second decision asks for more units than the item holds
+		tooMany := approveNearMint
+		tooMany.Quantity = 2
+		results, err := svc.ReviewItems(f.storeUser.ID, []ItemDecision{
+			{ItemID: first, ItemReview: approveNearMint},
+			{ItemID: second, ItemReview: tooMany},
+		})
+		assert.ErrorIs(t, err, ErrBatchReviewRefused)
+		if assert.Len(t, results, 2) {
+			assert.Empty(t, results[0].Error)
+			assert.Nil(t, results[0].Item)
+			assert.Equal(t, ErrInvalidQuantity.Error(), results[1].Error)
+		}
+
+		consignment, err = svc.GetConsignment(f.storeUser.ID, consignment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusPending, consignment.Items[0].Status, "the first decision should be rolled back")
+	})
+
+	t.Run("every decision is applied with a price proposal", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}, {CardID: f.card.ID}})
+		assert.NoError(t, err)
+		first, second := consignment.Items[0].ID, consignment.Items[1].ID
+
+		priced := approveNearMint
+		priced.Price = 15000
+		results, err := svc.ReviewItems(f.storeUser.ID, []ItemDecision{
+			{ItemID: first, ItemReview: priced},
+			{ItemID: second, ItemReview: ItemReview{Status: model.ItemStatusRejected, Reason: "fake"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusApproved, results[0].Item.Status)
+		assert.Equal(t, model.ItemStatusRejected, results[1].Item.Status)
+
+		proposals, err := svc.ListPriceProposals(f.player.ID, first)
+		assert.NoError(t, err)
+		if assert.Len(t, proposals, 1) {
+			assert.Equal(t, model.Money(15000), proposals[0].Price)
+			assert.Equal(t, model.PricePartyStore, proposals[0].ProposedBy)
+		}
+	})
+
+	t.Run("duplicate and foreign items are refused", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		results, err := svc.ReviewItems(f.storeUser.ID, []ItemDecision{
+			{ItemID: itemID, ItemReview: approveNearMint},
+			{ItemID: itemID, ItemReview: approveNearMint},
+		})
+		assert.ErrorIs(t, err, ErrBatchReviewRefused)
+		assert.Equal(t, ErrDuplicateDecision.Error(), results[1].Error)
+
+		_, err = svc.ReviewItems(f.player.ID, []ItemDecision{{ItemID: itemID, ItemReview: approveNearMint}})
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("a suspended store's batch is refused", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		suspended, err := svc.storeRepo.UpdateStoreStatus(f.store.ID, model.StoreStatusActive, model.StoreStatusSuspended, "unpaid fees", f.storeUser.ID, time.Now())
+		if err != nil || !suspended {
+			t.Fatalf("failed to suspend store: %v", err)
+		}
+
+		results, err := svc.ReviewItems(f.storeUser.ID, []ItemDecision{{ItemID: itemID, ItemReview: approveNearMint}})
+		assert.ErrorIs(t, err, ErrBatchReviewRefused)
+		assert.ErrorIs(t, err, ErrStoreNotActive)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, ErrStoreNotActive.Error(), results[0].Error)
+			assert.Nil(t, results[0].Item)
+		}
+	})
+}
+
+func TestConsignmentService_PlayerExits(t *testing.T) {
+	t.Run("pending item can be cancelled but not withdrawn", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		_, err = svc.RequestItemWithdrawal(f.player.ID, itemID)
+		assert.ErrorIs(t, err, ErrCannotUpdateStatus)
+
+		_, err = svc.CancelConsignmentItem(f.storeUser.ID, itemID)
+		assert.Equal(t, ErrForbidden, err)
+
+		item, err := svc.CancelConsignmentItem(f.player.ID, itemID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusCancelled, item.Status)
+
+		consignment, err = svc.GetConsignment(f.player.ID, consignment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ConsignmentRequestStatusClosed, consignment.Status)
+	})
+
+	t.Run("approved item is withdrawn and returned", func(t *testing.T) {
+		svc, f := newTestConsignmentService(t)
+
+		consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+		assert.NoError(t, err)
+		itemID := consignment.Items[0].ID
+
+		_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, approveNearMint)
+		assert.NoError(t, err)
+
+		_, err = svc.ConfirmItemReturn(f.storeUser.ID, itemID)
+		assert.ErrorIs(t, err, ErrCannotUpdateStatus)
+
+		_, err = svc.RequestItemWithdrawal(f.player.ID, itemID)
+		assert.NoError(t, err)
+
+		item, err := svc.ConfirmItemReturn(f.storeUser.ID, itemID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusReturned, item.Status)
+
+		events, err := svc.GetConsignmentItemHistory(f.player.ID, itemID)
+		assert.NoError(t, err)
+		assert.Len(t, events, 4)
+	})
+}
+
+func TestConsignmentService_PriceNegotiation(t *testing.T) {
+	svc, f := newTestConsignmentService(t)
+
+	consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID, MinPrice: 12000}})
+	assert.NoError(t, err)
+	itemID := consignment.Items[0].ID
+	assert.Equal(t, model.Money(12000), consignment.Items[0].MinPrice)
+
+	// The player cannot open the negotiation or accept their own offer
+	_, err = svc.ProposePrice(f.player.ID, itemID, 20000)
+	assert.Equal(t, ErrNoOpenProposal, err)
+
+	_, err = svc.ProposePrice(f.storeUser.ID, itemID, 10000)
+	assert.NoError(t, err)
+	_, err = svc.AcceptPriceProposal(f.storeUser.ID, itemID)
+	assert.Equal(t, ErrNoOpenProposal, err)
+
+	// The player counters and the store accepts the counter-offer
+	_, err = svc.ProposePrice(f.player.ID, itemID, 15000)
+	assert.NoError(t, err)
+	item, err := svc.AcceptPriceProposal(f.storeUser.ID, itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Money(15000), item.ListedPrice)
+	assert.Equal(t, model.Money(12000), item.MinPrice)
+
+	// Accepting a store price below the floor lowers the floor
+	_, err = svc.ProposePrice(f.storeUser.ID, itemID, 11000)
+	assert.NoError(t, err)
+	item, err = svc.AcceptPriceProposal(f.player.ID, itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Money(11000), item.ListedPrice)
+	assert.Equal(t, model.Money(11000), item.MinPrice)
+
+	proposals, err := svc.ListPriceProposals(f.player.ID, itemID)
+	assert.NoError(t, err)
+	if assert.Len(t, proposals, 3) {
+		assert.Equal(t, model.ProposalStatusSuperseded, proposals[0].Status)
+		assert.Equal(t, model.ProposalStatusAccepted, proposals[1].Status)
+		assert.Equal(t, model.ProposalStatusAccepted, proposals[2].Status)
+	}
+}
+
+func TestConsignmentService_Expiry(t *testing.T) {
+	svc, f := newTestConsignmentService(t)
+
+	consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+	assert.NoError(t, err)
+	itemID := consignment.Items[0].ID
+
+	_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, approveNearMint)
+	assert.NoError(t, err)
+
+	// Pretend the item was listed 31 days ago under a 30-day agreement
+	now := time.Now()
+	listedAt := now.AddDate(0, 0, -31)
+	expiresAt := listedAt.AddDate(0, 0, 30)
+	assert.NoError(t, svc.consignmentRepo.StartItemListing(itemID, listedAt, &expiresAt))
+
+	expiring, err := svc.ListExpiringItems(f.storeUser.ID, 7*24*time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, expiring, 1)
+
+	_, err = svc.ExpireItems(now)
+	assert.NoError(t, err)
+	item, err := svc.consignmentRepo.GetConsignmentItemByID(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ItemStatusExpired, item.Status)
+
+	item, err = svc.RelistItem(f.player.ID, itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ItemStatusApproved, item.Status)
+	assert.Nil(t, item.ExpiresAt, "the fixture store has no agreement period")
+}
+
+func TestConsignmentService_PriceDropKeepsAgreedPrice(t *testing.T) {
+	svc, f := newTestConsignmentService(t)
+
+	f.store.PriceDropDays, f.store.PriceDropRate = 7, 1000 // 10% a week
+	assert.NoError(t, svc.storeRepo.UpdateStore(f.store))
+
+	consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+	assert.NoError(t, err)
+	itemID := consignment.Items[0].ID
+	_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, approveNearMint)
+	assert.NoError(t, err)
+	assert.NoError(t, svc.consignmentRepo.UpdateConsignmentItemPrices(itemID, testPrice, 0))
+	assert.NoError(t, svc.consignmentRepo.StartItemListing(itemID, time.Now().AddDate(0, 0, -8), nil))
+
+	candidates, err := svc.consignmentRepo.ListPriceDropCandidates()
+	assert.NoError(t, err)
+	var candidate *repository.PriceDropCandidate
+	for i := range candidates {
+		if candidates[i].Item.ID == itemID {
+			candidate = &candidates[i]
+		}
+	}
+	if candidate == nil {
+		t.Fatalf("item %d is not a price drop candidate", itemID)
+	}
+
+	// A price is agreed, with a higher floor, after the scheduler read the item
+	agreed, floor := model.Money(12000), model.Money(11000)
+	assert.NoError(t, svc.consignmentRepo.UpdateConsignmentItemPrices(itemID, agreed, floor))
+
+	changed, err := svc.consignmentRepo.ApplyPriceDrop(&candidate.Item, model.Money(9000), 1)
+	assert.NoError(t, err)
+	assert.False(t, changed, "the drop was worked out from the old price")
+	item, err := svc.consignmentRepo.GetConsignmentItemByID(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, agreed, item.ListedPrice)
+	assert.Equal(t, 0, item.PriceDrops)
+
+	// The next run drops the agreed price, but not below the new floor
+	_, err = svc.ApplyPriceDrops(time.Now())
+	assert.NoError(t, err)
+	item, err = svc.consignmentRepo.GetConsignmentItemByID(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, floor, item.ListedPrice)
+	assert.Equal(t, 1, item.PriceDrops)
+}
+
+func TestConsignmentService_AgreedPriceRestartsPriceDrops(t *testing.T) {
+	svc, f := newTestConsignmentService(t)
+
+	f.store.PriceDropDays, f.store.PriceDropRate = 7, 1000 // 10% a week
+	assert.NoError(t, svc.storeRepo.UpdateStore(f.store))
+
+	consignment, err := svc.CreateConsignment(f.player.ID, f.store.ID, []ConsignmentItemInput{{CardID: f.card.ID}})
+	assert.NoError(t, err)
+	itemID := consignment.Items[0].ID
+	_, err = svc.UpdateConsignmentItemStatus(f.storeUser.ID, itemID, approveNearMint)
+	assert.NoError(t, err)
+
+	// On sale for six days, one short of the first drop, when a price is agreed
+	expiresAt := time.Now().AddDate(0, 0, 30)
+	assert.NoError(t, svc.consignmentRepo.StartItemListing(itemID, time.Now().AddDate(0, 0, -6), &expiresAt))
+	_, err = svc.ProposePrice(f.storeUser.ID, itemID, testPrice)
+	assert.NoError(t, err)
+	agreedAt := time.Now()
+	item, err := svc.AcceptPriceProposal(f.player.ID, itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, item.PriceDrops)
+
+	// A day later the old schedule would have lowered the agreed price
+	_, err = svc.ApplyPriceDrops(time.Now().AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	item, err = svc.consignmentRepo.GetConsignmentItemByID(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrice, item.ListedPrice)
+	assert.Equal(t, 0, item.PriceDrops)
+	if assert.NotNil(t, item.ListedAt) && assert.NotNil(t, item.ExpiresAt) {
+		assert.False(t, item.ListedAt.Before(agreedAt.Add(-time.Second)), "drops count from the agreement")
+		assert.WithinDuration(t, expiresAt, *item.ExpiresAt, time.Second, "the agreement period is unchanged")
+	}
+
+	// A week after the agreement the first drop applies
+	_, err = svc.ApplyPriceDrops(time.Now().AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	item, err = svc.consignmentRepo.GetConsignmentItemByID(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Money(9000), item.ListedPrice)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCreditAmount = errors.New("credit amount must be greater than zero")
+	ErrInsufficientCredit  = errors.New("not enough store credit")
+	ErrBuyerRequired       = errors.New("a buyer account is required to pay with store credit")
+)
+
+// CreditService manages store credit (儲值金): each user has a separate balance at every store,
+// kept as an append-only ledger. Spends for CREDIT sales and settlement payouts are written by
+// TransactionService and SettlementService inside their own DB transactions.
+type CreditService struct {
+	repo      *repository.CreditRepository
+	storeRepo *repository.StoreRepository
+	userRepo  repository.IUserRepository
+}
+
+func NewCreditService(
+	repo *repository.CreditRepository,
+	storeRepo *repository.StoreRepository,
+	userRepo repository.IUserRepository,
+) *CreditService {
+	return &CreditService{
+		repo:      repo,
+		storeRepo: storeRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// TopUp adds credit to a user's balance at the store storeUserID works at, after the store has
+// taken payment for it. Only active stores can take top-ups.
+func (s *CreditService) TopUp(storeUserID, userID int64, amount model.Money, note string) (*model.CreditEntry, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidCreditAmount
+	}
+
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionSell)
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	entry := &model.CreditEntry{
+		StoreID: store.ID,
+		UserID:  userID,
+		Kind:    model.CreditEntryTopUp,
+		Amount:  amount,
+		ActorID: &storeUserID,
+		Note:    note,
+	}
+	if err := s.repo.CreateEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetStoreAccount returns a user's balance and ledger at the store storeUserID works at.
+func (s *CreditService) GetStoreAccount(storeUserID, userID int64) (*model.CreditAccount, error) {
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	return s.getAccount(store.ID, userID)
+}
+
+// GetMyAccount returns the user's own balance and ledger at a store.
+func (s *CreditService) GetMyAccount(userID, storeID int64) (*model.CreditAccount, error) {
+	return s.getAccount(storeID, userID)
+}
+
+// ListMyBalances returns the user's balance at every store they have store credit history with.
+func (s *CreditService) ListMyBalances(userID int64) ([]model.CreditBalance, error) {
+	balances, err := s.repo.ListBalancesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+func (s *CreditService) getAccount(storeID, userID int64) (*model.CreditAccount, error) {
+	entries, err := s.repo.ListEntries(storeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &model.CreditAccount{
+		CreditBalance: model.CreditBalance{StoreID: storeID, UserID: userID},
+		Entries:       entries,
+	}
+	for _, entry := range entries {
+		account.Balance += entry.Amount
+	}
+	return account, nil
+}
+
+// spendCredit takes amount out of the buyer's balance inside the caller's DB transaction. The
+// account stays locked until that transaction ends, so concurrent spends cannot overdraw it.
+// entry carries the store, buyer and what the credit paid for; its kind and amount are set here.
+func spendCredit(repo *repository.CreditRepository, entry *model.CreditEntry, amount model.Money) error {
+	if err := repo.LockAccount(entry.StoreID, entry.UserID); err != nil {
+		return err
+	}
+	balance, err := repo.GetBalance(entry.StoreID, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if balance < amount {
+		return ErrInsufficientCredit
+	}
+
+	entry.Kind = model.CreditEntrySpend
+	entry.Amount = -amount
+	return repo.CreateEntry(entry)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreditService_TopUp(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	storeRepo := repository.NewStoreRepository(db)
+	svc := NewCreditService(repository.NewCreditRepository(db), storeRepo, repository.NewUserRepository(db))
+
+	entry, err := svc.TopUp(f.storeUser.ID, f.player.ID, testPrice, "paid in cash")
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.CreditEntryTopUp, entry.Kind)
+		assert.Equal(t, testPrice, entry.Amount)
+	}
+	_, err = svc.TopUp(f.storeUser.ID, f.player.ID, 0, "")
+	assert.Equal(t, ErrInvalidCreditAmount, err)
+
+	suspended, err := storeRepo.UpdateStoreStatus(f.store.ID, model.StoreStatusActive, model.StoreStatusSuspended, "unpaid fees", f.storeUser.ID, time.Now())
+	if err != nil || !suspended {
+		t.Fatalf("failed to suspend store: %v", err)
+	}
+	_, err = svc.TopUp(f.storeUser.ID, f.player.ID, testPrice, "paid in cash")
+	assert.Equal(t, ErrStoreNotActive, err)
+
+	account, err := svc.GetMyAccount(f.player.ID, f.store.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, testPrice, account.Balance, "only the first top-up went through")
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ExpiryScheduler runs the consignment housekeeping jobs inside the server process:
+// automatic price drops and the expiry of items whose agreement period has ended.
+// Both jobs re-check each item before changing it, so running several server
+// instances at once is safe.
+type ExpiryScheduler struct {
+	consignmentService *ConsignmentService
+	interval           time.Duration
+}
+
+// NewExpiryScheduler creates a scheduler that runs every interval (e.g. "1h"). The interval must
+// be positive.
+func NewExpiryScheduler(consignmentService *ConsignmentService, intervalStr string) (*ExpiryScheduler, error) {
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("expiry check interval must be positive, got %s", intervalStr)
+	}
+	return &ExpiryScheduler{consignmentService: consignmentService, interval: interval}, nil
+}
+
+// Start runs the jobs once right away and then on every tick, in a background goroutine,
+// until ctx is cancelled.
+func (s *ExpiryScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.RunOnce(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce applies the price drops due by now, then expires overdue items. Failures are logged;
+// the affected items are retried on the next run.
+func (s *ExpiryScheduler) RunOnce(now time.Time) {
+	if dropped, err := s.consignmentService.ApplyPriceDrops(now); err != nil {
+		log.Printf("expiry scheduler: price drops: %v", err)
+	} else if dropped > 0 {
+		log.Printf("expiry scheduler: lowered the price of %d item(s)", dropped)
+	}
+
+	if expired, err := s.consignmentService.ExpireItems(now); err != nil {
+		log.Printf("expiry scheduler: expiry: %v", err)
+	} else if expired > 0 {
+		log.Printf("expiry scheduler: expired %d item(s)", expired)
+	}
+}
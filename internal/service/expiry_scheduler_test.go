@@ -0,0 +1,20 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExpirySchedulerInterval(t *testing.T) {
+	scheduler, err := NewExpiryScheduler(nil, "1h")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Hour, scheduler.interval)
+	}
+
+	for _, interval := range []string{"0s", "-1h", "hourly", ""} {
+		_, err := NewExpiryScheduler(nil, interval)
+		assert.Error(t, err, interval)
+	}
+}
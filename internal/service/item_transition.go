@@ -0,0 +1,65 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"fmt"
+)
+
+// transitionItem moves a consignment item from one status to another and appends the change
+// to the item's history. Every item status change goes through here so the transition table in
+// model.ConsignmentItemStatus.CanTransitionTo is enforced in one place.
+//
+// It must run inside the caller's DB transaction, with the item row locked, so that from is the
+// item's current status. actorID is the user who caused the change (nil for system changes);
+// reason is kept in the history and, for rejections, also shown on the item.
+func transitionItem(consignmentRepo *repository.ConsignmentRepository, itemID int64, from, to model.ConsignmentItemStatus, actorID *int64, reason string) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move item from %s to %s", ErrCannotUpdateStatus, from, to)
+	}
+
+	if err := consignmentRepo.UpdateConsignmentItemStatus(itemID, to, reason); err != nil {
+		return fmt.Errorf("failed to update item status: %w", err)
+	}
+	return recordItemEvent(consignmentRepo, itemID, from, to, actorID, reason)
+}
+
+// recordItemEvent writes one entry to an item's history without touching the item itself.
+func recordItemEvent(consignmentRepo *repository.ConsignmentRepository, itemID int64, from, to model.ConsignmentItemStatus, actorID *int64, reason string) error {
+	event := &model.ConsignmentItemEvent{
+		ConsignmentItemID: itemID,
+		ActorID:           actorID,
+		FromStatus:        from,
+		ToStatus:          to,
+		Reason:            reason,
+	}
+	if err := consignmentRepo.CreateConsignmentItemEvent(event); err != nil {
+		return fmt.Errorf("failed to record item history: %w", err)
+	}
+	return nil
+}
+
+// splitItem moves quantity units of a locked item into a new item in the same status and
+// records the split in both items' histories. The original item keeps the remaining units and
+// its quantity is updated in place. quantity must leave at least one unit on the original.
+func splitItem(consignmentRepo *repository.ConsignmentRepository, item *model.ConsignmentItem, quantity int, actorID *int64) (*model.ConsignmentItem, error) {
+	if quantity <= 0 || quantity >= item.Quantity {
+		return nil, ErrInvalidQuantity
+	}
+
+	part, err := consignmentRepo.SplitConsignmentItem(item.ID, quantity)
+	if err != nil {
+		return nil, err
+	}
+	item.Quantity -= quantity
+
+	if err := recordItemEvent(consignmentRepo, item.ID, item.Status, item.Status, actorID,
+		fmt.Sprintf("split %d units off into item %d", quantity, part.ID)); err != nil {
+		return nil, err
+	}
+	if err := recordItemEvent(consignmentRepo, part.ID, "", part.Status, actorID,
+		fmt.Sprintf("split off item %d", item.ID)); err != nil {
+		return nil, err
+	}
+	return part, nil
+}
@@ -7,7 +7,8 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// JWTService handles JWT generation and validation.
+// JWTService handles JWT generation and validation. Tokens are short-lived access tokens; a client
+// keeps its login going by exchanging its refresh token for new ones (see SessionService).
 type JWTService struct {
 	secretKey      string
 	expireDuration time.Duration
@@ -15,8 +16,9 @@ type JWTService struct {
 
 // CustomClaims are our custom claims, which includes standard claims and user-specific data.
 type CustomClaims struct {
-	UserID int64  `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    int64  `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID int64  `json:"session_id"` // The login session the token was issued for
 	jwt.RegisteredClaims
 }
 
@@ -32,11 +34,17 @@ func NewJWTService(secretKey string, expireDurationStr string) (*JWTService, err
 	}, nil
 }
 
-// GenerateToken generates a new JWT for a given user.
-func (s *JWTService) GenerateToken(user *model.User) (string, error) {
+// ExpireDuration returns how long generated tokens are valid for.
+func (s *JWTService) ExpireDuration() time.Duration {
+	return s.expireDuration
+}
+
+// GenerateToken generates a new JWT for a given user, tied to one of their login sessions.
+func (s *JWTService) GenerateToken(user *model.User, sessionID int64) (string, error) {
 	claims := CustomClaims{
-		UserID: user.ID,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expireDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
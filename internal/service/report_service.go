@@ -0,0 +1,82 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"errors"
+)
+
+var (
+	ErrInvalidReportPeriod   = errors.New("report period must be day, week or month")
+	ErrInvalidReportGrouping = errors.New("top sellers can be grouped by card or series")
+)
+
+// MaxTopSellers caps the length of a top sellers report.
+const MaxTopSellers = 100
+
+// ReportService serves the sales, commission and liability reports of a store to its
+// members whose role grants VIEW_REPORTS.
+type ReportService struct {
+	repo      *repository.ReportRepository
+	storeRepo *repository.StoreRepository
+}
+
+func NewReportService(repo *repository.ReportRepository, storeRepo *repository.StoreRepository) *ReportService {
+	return &ReportService{repo: repo, storeRepo: storeRepo}
+}
+
+// SalesByPeriod sums up the sales of the store owned by storeUserID per day, week or month.
+func (s *ReportService) SalesByPeriod(storeUserID int64, period model.ReportPeriod, filter repository.ReportFilter) ([]model.SalesPeriodReport, error) {
+	if !period.Valid() {
+		return nil, ErrInvalidReportPeriod
+	}
+	store, err := s.getStore(storeUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.SalesByPeriod(store.ID, period, filter)
+}
+
+// CommissionByPaymentMethod sums up the commission the store earned per payment method.
+func (s *ReportService) CommissionByPaymentMethod(storeUserID int64, filter repository.ReportFilter) ([]model.CommissionReport, error) {
+	store, err := s.getStore(storeUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CommissionByPaymentMethod(store.ID, filter)
+}
+
+// PlayerLiabilities lists what the store owes each player as of now.
+func (s *ReportService) PlayerLiabilities(storeUserID int64) ([]model.PlayerLiability, error) {
+	store, err := s.getStore(storeUserID)
+	if err != nil {
+		return nil, err
+	}
+	liabilities, err := s.repo.PlayerLiabilities(store.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range liabilities {
+		liabilities[i].Owed = liabilities[i].TotalOwed()
+	}
+	return liabilities, nil
+}
+
+// TopSellers ranks the store's best-selling cards or series. limit is clamped to 1..MaxTopSellers.
+func (s *ReportService) TopSellers(storeUserID int64, grouping model.TopSellerGrouping, filter repository.ReportFilter, limit int) ([]model.TopSeller, error) {
+	if !grouping.Valid() {
+		return nil, ErrInvalidReportGrouping
+	}
+	if limit < 1 || limit > MaxTopSellers {
+		limit = MaxTopSellers
+	}
+	store, err := s.getStore(storeUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.TopSellers(store.ID, grouping, filter, limit)
+}
+
+func (s *ReportService) getStore(storeUserID int64) (*model.Store, error) {
+	return memberStore(s.storeRepo, storeUserID, model.PermissionViewReports)
+}
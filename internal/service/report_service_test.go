@@ -0,0 +1,77 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportService(t *testing.T) {
+	svc, f, item := newTestTransactionService(t, 3)
+	reports := NewReportService(repository.NewReportRepository(svc.uow.db), svc.storeRepo)
+
+	// Two units sold for cash at 10%, the last one for store credit at 5%, then the credit sale refunded
+	_, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 2, testPrice, model.PaymentMethodCash, 0)
+	assert.NoError(t, err)
+	topUpCredit(t, svc, f, f.player.ID, testPrice)
+	creditSale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCredit, f.player.ID)
+	assert.NoError(t, err)
+	_, err = svc.RefundTransaction(f.storeUser.ID, creditSale.ID, "changed their mind", false)
+	assert.NoError(t, err)
+
+	t.Run("sales by day net refunds off", func(t *testing.T) {
+		days, err := reports.SalesByPeriod(f.storeUser.ID, model.ReportPeriodDay, repository.ReportFilter{})
+		assert.NoError(t, err)
+		if assert.Len(t, days, 1) {
+			assert.Equal(t, 2, days[0].Sales)
+			assert.Equal(t, 1, days[0].Refunds)
+			assert.Equal(t, 2, days[0].Units)
+			assert.Equal(t, testPrice.Mul(2), days[0].Gross)
+			assert.Equal(t, model.Money(2000), days[0].Commission)
+			assert.Equal(t, model.Money(18000), days[0].PlayerShare)
+		}
+
+		_, err = reports.SalesByPeriod(f.storeUser.ID, "year", repository.ReportFilter{})
+		assert.Equal(t, ErrInvalidReportPeriod, err)
+	})
+
+	t.Run("commission by payment method", func(t *testing.T) {
+		methods, err := reports.CommissionByPaymentMethod(f.storeUser.ID, repository.ReportFilter{})
+		assert.NoError(t, err)
+		if assert.Len(t, methods, 2) {
+			assert.Equal(t, model.PaymentMethodCash, methods[0].PaymentMethod)
+			assert.Equal(t, model.Money(2000), methods[0].Commission)
+			assert.Equal(t, model.PaymentMethodCredit, methods[1].PaymentMethod)
+			assert.Zero(t, methods[1].Commission, "the refund gives the commission back")
+		}
+	})
+
+	t.Run("liability to the player", func(t *testing.T) {
+		liabilities, err := reports.PlayerLiabilities(f.storeUser.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, liabilities, 1) {
+			assert.Equal(t, f.player.ID, liabilities[0].PlayerID)
+			assert.Equal(t, model.Money(18000), liabilities[0].UnsettledShare)
+			assert.Equal(t, model.Money(18000), liabilities[0].Owed)
+		}
+	})
+
+	t.Run("top sellers leave refunds out", func(t *testing.T) {
+		sellers, err := reports.TopSellers(f.storeUser.ID, model.TopSellersByCard, repository.ReportFilter{}, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, sellers, 1) {
+			assert.Equal(t, f.card.ID, *sellers[0].CardID)
+			assert.Equal(t, 2, sellers[0].Units)
+		}
+
+		_, err = reports.TopSellers(f.storeUser.ID, "rarity", repository.ReportFilter{}, 10)
+		assert.Equal(t, ErrInvalidReportGrouping, err)
+	})
+
+	t.Run("only stores have reports", func(t *testing.T) {
+		_, err := reports.PlayerLiabilities(f.player.ID)
+		assert.Equal(t, ErrStoreNotFound, err)
+	})
+}
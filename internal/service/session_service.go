@@ -0,0 +1,183 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionRevoked      = errors.New("session has expired or been revoked")
+)
+
+// TokenPair is what a client receives when it logs in or refreshes its tokens.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // Seconds until the access token expires
+}
+
+// SessionService manages login sessions. Each login starts a session with an opaque refresh token
+// the client exchanges for a new access token, and a new refresh token, once the short-lived
+// access token expires. A refresh token works once: presenting one that has already been replaced
+// means it was copied, so the session is revoked. A session ends on logout, on revocation, or when
+// its refresh lifetime, counted from login, runs out.
+type SessionService struct {
+	sessionRepo     *repository.SessionRepository
+	userRepo        repository.IUserRepository
+	jwtService      *JWTService
+	refreshDuration time.Duration
+}
+
+func NewSessionService(sessionRepo *repository.SessionRepository, userRepo repository.IUserRepository, jwtService *JWTService, refreshDurationStr string) (*SessionService, error) {
+	refreshDuration, err := time.ParseDuration(refreshDurationStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token expire duration: %w", err)
+	}
+
+	return &SessionService{
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		jwtService:      jwtService,
+		refreshDuration: refreshDuration,
+	}, nil
+}
+
+// StartSession starts a new session for a user who has just logged in.
+func (s *SessionService) StartSession(user *model.User) (*TokenPair, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt:        time.Now().Add(s.refreshDuration),
+	}
+	if err := s.sessionRepo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(user, session.ID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token. The new access
+// token carries the user's current role.
+func (s *SessionService) Refresh(refreshToken string) (*TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+	now := time.Now()
+
+	session, err := s.sessionRepo.GetSessionByRefreshHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		// A replaced token being presented again means someone else holds a copy of it
+		if _, err := s.sessionRepo.RevokeSessionByPreviousHash(hash, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if !session.Active(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetUserByID(session.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	newToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.RotateRefreshToken(session.ID, hash, hashRefreshToken(newToken), now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Refreshed or revoked in the meantime
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.tokenPair(user, session.ID, newToken)
+}
+
+// Logout ends the session the refresh token belongs to. Access tokens issued for it stop working
+// straight away.
+func (s *SessionService) Logout(refreshToken string) error {
+	revoked, err := s.sessionRepo.RevokeSessionByRefreshHash(hashRefreshToken(refreshToken), time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// RevokeUserSessions ends every session of a user, e.g. after their password or device was
+// compromised, and returns how many were ended.
+func (s *SessionService) RevokeUserSessions(userID int64) (int64, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("error getting user: %w", err)
+	}
+	return s.sessionRepo.RevokeUserSessions(userID, time.Now())
+}
+
+// CheckSession returns ErrSessionRevoked unless the session an access token was issued for is
+// still active.
+func (s *SessionService) CheckSession(sessionID int64) error {
+	session, err := s.sessionRepo.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || !session.Active(time.Now()) {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func (s *SessionService) tokenPair(user *model.User, sessionID int64, refreshToken string) (*TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.jwtService.ExpireDuration().Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns a random, URL-safe refresh token.
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken returns the hex SHA-256 of a refresh token, the form it is stored in.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
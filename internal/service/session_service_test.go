@@ -0,0 +1,69 @@
+package service
+
+import (
+	"card_manage/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionService_RefreshAndRevoke(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	jwtService, err := NewJWTService("test-secret", "15m")
+	if err != nil {
+		t.Fatalf("failed to create jwt service: %v", err)
+	}
+	svc, err := NewSessionService(repository.NewSessionRepository(db), repository.NewUserRepository(db), jwtService, "720h")
+	if err != nil {
+		t.Fatalf("failed to create session service: %v", err)
+	}
+
+	login, err := svc.StartSession(f.player)
+	if err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+	assert.Equal(t, int64(15*60), login.ExpiresIn)
+	claims, err := jwtService.ValidateToken(login.AccessToken)
+	if err != nil {
+		t.Fatalf("failed to validate access token: %v", err)
+	}
+	assert.Equal(t, f.player.ID, claims.UserID)
+	assert.NoError(t, svc.CheckSession(claims.SessionID))
+
+	refreshed, err := svc.Refresh(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+	refreshedClaims, _ := jwtService.ValidateToken(refreshed.AccessToken)
+	assert.Equal(t, claims.SessionID, refreshedClaims.SessionID, "refreshing keeps the session")
+
+	// Replaying the replaced token ends the session, including for whoever holds the new one
+	_, err = svc.Refresh(login.RefreshToken)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+	assert.Equal(t, ErrSessionRevoked, svc.CheckSession(claims.SessionID))
+	_, err = svc.Refresh(refreshed.RefreshToken)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+
+	// Logout ends only the session it is called for
+	phone, _ := svc.StartSession(f.player)
+	laptop, _ := svc.StartSession(f.player)
+	assert.NoError(t, svc.Logout(phone.RefreshToken))
+	assert.Equal(t, ErrInvalidRefreshToken, svc.Logout(phone.RefreshToken))
+	_, err = svc.Refresh(phone.RefreshToken)
+	assert.Equal(t, ErrInvalidRefreshToken, err)
+	laptop, err = svc.Refresh(laptop.RefreshToken)
+	if err != nil {
+		t.Fatalf("failed to refresh other session: %v", err)
+	}
+
+	// An admin can end every session at once
+	revoked, err := svc.RevokeUserSessions(f.player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), revoked)
+	laptopClaims, _ := jwtService.ValidateToken(laptop.AccessToken)
+	assert.Equal(t, ErrSessionRevoked, svc.CheckSession(laptopClaims.SessionID))
+	_, err = svc.RevokeUserSessions(-1)
+	assert.Equal(t, ErrUserNotFound, err)
+}
@@ -6,96 +6,413 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
-	ErrNoUnsettledTransactions = errors.New("no unsettled transactions found to create a settlement")
+	ErrNoUnsettledTransactions    = errors.New("no unsettled transactions found to create a settlement")
+	ErrSettlementNotFound         = errors.New("settlement not found")
+	ErrSettlementAlreadyCompleted = errors.New("settlement has already been completed")
+	ErrInvalidPayoutMethod        = errors.New("payout method must be CASH or CREDIT")
+	ErrTransactionNotSettleable   = errors.New("transaction is not an unsettled sale of the player at this store")
+	ErrBelowMinimumSettlement     = errors.New("settlement amount is below the store's minimum payout")
 )
 
 type SettlementService struct {
 	repo            *repository.SettlementRepository
 	consignmentRepo *repository.ConsignmentRepository
+	creditRepo      *repository.CreditRepository
 	storeRepo       *repository.StoreRepository
-	db              *sql.DB
+	uow             *UnitOfWork
 }
 
 func NewSettlementService(
 	repo *repository.SettlementRepository,
 	consignmentRepo *repository.ConsignmentRepository,
+	creditRepo *repository.CreditRepository,
 	storeRepo *repository.StoreRepository,
-	db *sql.DB,
+	uow *UnitOfWork,
 ) *SettlementService {
 	return &SettlementService{
 		repo:            repo,
 		consignmentRepo: consignmentRepo,
+		creditRepo:      creditRepo,
 		storeRepo:       storeRepo,
-		db:              db,
+		uow:             uow,
 	}
 }
 
 // CreateSettlement allows a player to request a settlement for a specific store.
-func (s *SettlementService) CreateSettlement(playerID, storeID int64) (*model.Settlement, error) {
-	// 1. Get all unsettled (SOLD) transactions for the player-store pair
-	transactions, err := s.repo.GetUnsettledTransactions(playerID, storeID)
+// The whole request runs in one DB transaction: the sold items are locked while they are
+// read, and the settlement row and the CLEARED item statuses are committed together.
+// The player chooses the payout method, and every sale's share is worked out again at the
+// commission rate for that method rather than the rate frozen at sale time: the rate of the
+// commission schedule rule the sale was charged under, or the store's current base rate.
+// With a CREDIT payout the net is added to the player's store credit and the settlement is
+// completed straight away, as there is nothing left to hand over.
+// transactionIDs picks the sales to settle; when empty, every unsettled sale is settled. The
+// player's share of the settled sales must reach the store's minimum payout.
+func (s *SettlementService) CreateSettlement(playerID, storeID int64, payoutMethod model.PaymentMethod, transactionIDs []int64) (*model.Settlement, error) {
+	if !payoutMethod.Valid() {
+		return nil, ErrInvalidPayoutMethod
+	}
+
+	store, err := s.storeRepo.GetStoreByID(storeID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting unsettled transactions: %w", err)
+		return nil, fmt.Errorf("error getting store: %w", err)
 	}
-	if len(transactions) == 0 {
-		return nil, ErrNoUnsettledTransactions
+	if store == nil {
+		return nil, ErrStoreNotFound
 	}
+	rate := store.CommissionFor(payoutMethod)
+	transactionIDs = uniqueInt64s(transactionIDs)
 
-	// 2. Calculate the total settlement amount
-	var totalAmount float64
-	var itemIDsToClear []int64
-	for _, tx := range transactions {
-		playerShare := tx.Price * (1 - (tx.CommissionRate / 100.0))
-		totalAmount += playerShare
-		itemIDsToClear = append(itemIDsToClear, tx.ConsignmentItemID)
+	var newSettlement *model.Settlement
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		repo := s.repo.WithTx(tx)
+		consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+		// 1. Get and lock the unsettled (SOLD) transactions for the player-store pair; every
+		// chosen transaction must be one of them
+		transactions, err := repo.GetUnsettledTransactions(playerID, storeID, transactionIDs)
+		if err != nil {
+			return fmt.Errorf("error getting unsettled transactions: %w", err)
+		}
+		if len(transactions) < len(transactionIDs) {
+			return ErrTransactionNotSettleable
+		}
+		if len(transactions) == 0 {
+			return ErrNoUnsettledTransactions
+		}
+
+		// 2. Split the sales at the payout method's rate
+		var ruleIDs []int64
+		for _, tx := range transactions {
+			if tx.CommissionRuleID != nil {
+				ruleIDs = append(ruleIDs, *tx.CommissionRuleID)
+			}
+		}
+		rules, err := s.storeRepo.WithTx(tx).GetCommissionRules(uniqueInt64s(ruleIDs))
+		if err != nil {
+			return err
+		}
+		lines, gross, commission, net := splitSales(transactions, payoutMethod, rate, rules)
+		if net < store.MinSettlement {
+			return ErrBelowMinimumSettlement
+		}
+		var itemIDsToClear []int64
+		for _, tx := range transactions {
+			itemIDsToClear = append(itemIDsToClear, tx.ConsignmentItemID)
+		}
+
+		// 3. Deduct outstanding withdrawal fees the payout can cover
+		fees, err := consignmentRepo.GetOutstandingWithdrawalFeesForUpdate(playerID, storeID)
+		if err != nil {
+			return fmt.Errorf("error getting withdrawal fees: %w", err)
+		}
+		feesDeducted, deductedFeeIDs := deductWithdrawalFees(net, fees)
+
+		// 4. Take back shares of sales refunded after an earlier settlement, from what is left
+		adjustments, err := repo.GetOutstandingAdjustmentsForUpdate(playerID, storeID)
+		if err != nil {
+			return fmt.Errorf("error getting settlement adjustments: %w", err)
+		}
+		adjustmentsDeducted, deductedAdjustmentIDs := deductAdjustments(net-feesDeducted, adjustments)
+
+		// 5. Create the settlement record
+		newSettlement = &model.Settlement{
+			PlayerID:            playerID,
+			StoreID:             storeID,
+			PayoutMethod:        payoutMethod,
+			CommissionRate:      rate,
+			GrossAmount:         gross,
+			CommissionAmount:    commission,
+			NetAmount:           net,
+			Amount:              net - feesDeducted - adjustmentsDeducted,
+			FeesDeducted:        feesDeducted,
+			AdjustmentsDeducted: adjustmentsDeducted,
+			Status:              model.StatusRequested,
+		}
+		settlementID, err := repo.CreateSettlement(newSettlement)
+		if err != nil {
+			return fmt.Errorf("failed to create settlement record: %w", err)
+		}
+		newSettlement.ID = settlementID
+
+		for i := range lines {
+			lines[i].SettlementID = settlementID
+		}
+		if err := repo.CreateSettlementLines(lines); err != nil {
+			return err
+		}
+		if len(deductedFeeIDs) > 0 {
+			if err := consignmentRepo.MarkWithdrawalFeesDeducted(deductedFeeIDs, settlementID); err != nil {
+				return err
+			}
+		}
+		if len(deductedAdjustmentIDs) > 0 {
+			if err := repo.MarkAdjustmentsDeducted(deductedAdjustmentIDs, settlementID); err != nil {
+				return err
+			}
+		}
+		if payoutMethod == model.PaymentMethodCredit {
+			if err := s.payOutAsCredit(tx, newSettlement); err != nil {
+				return err
+			}
+		}
+
+		// 6. Update all related consignment items to CLEARED
+		// (the items were locked as SOLD by GetUnsettledTransactions)
+		reason := fmt.Sprintf("cleared by settlement %d", settlementID)
+		for _, itemID := range itemIDsToClear {
+			if err := transitionItem(consignmentRepo, itemID, model.ItemStatusSold, model.ItemStatusCleared, &playerID, reason); err != nil {
+				return err
+			}
+		}
+		if err := consignmentRepo.RefreshConsignmentStatusesForItems(itemIDsToClear); err != nil {
+			return fmt.Errorf("failed to refresh consignment statuses: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 3. Create the settlement object
-	newSettlement := &model.Settlement{
-		PlayerID: playerID,
-		StoreID:  storeID,
-		Amount:   totalAmount,
-		Status:   model.StatusRequested,
+	return newSettlement, nil
+}
+
+// payOutAsCredit adds a settlement's net payout to the player's store credit and completes it.
+func (s *SettlementService) payOutAsCredit(tx *sql.Tx, settlement *model.Settlement) error {
+	if settlement.Amount > 0 {
+		entry := &model.CreditEntry{
+			StoreID:      settlement.StoreID,
+			UserID:       settlement.PlayerID,
+			Kind:         model.CreditEntrySettlement,
+			Amount:       settlement.Amount,
+			SettlementID: &settlement.ID,
+			ActorID:      &settlement.PlayerID,
+		}
+		if err := s.creditRepo.WithTx(tx).CreateEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	completedAt := time.Now()
+	if _, err := s.repo.WithTx(tx).CompleteSettlement(settlement.ID, completedAt, nil); err != nil {
+		return fmt.Errorf("failed to complete settlement: %w", err)
 	}
+	settlement.Status = model.StatusCompleted
+	settlement.CompletedAt = &completedAt
+	settlement.UpdatedAt = completedAt
+	return nil
+}
 
-	// 4. Execute in a DB transaction
-	tx, err := s.db.Begin()
+// GetSettlement returns a settlement with the sales it paid. Only the player who requested it
+// and the staff of the store it was addressed to can see it.
+func (s *SettlementService) GetSettlement(userID, settlementID int64) (*model.SettlementDetail, error) {
+	settlement, err := s.repo.GetSettlementByID(settlementID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin db transaction: %w", err)
+		return nil, fmt.Errorf("error getting settlement: %w", err)
+	}
+	if settlement == nil {
+		return nil, ErrSettlementNotFound
 	}
-	defer tx.Rollback()
 
-	// Create the settlement record
-	// This repository method needs to be adapted to accept a transaction
-	settlementID, err := s.repo.CreateSettlement(newSettlement) // Assuming CreateSettlement doesn't need to be in the tx for now.
+	if settlement.PlayerID != userID {
+		if _, err := checkStoreMember(s.storeRepo, userID, settlement.StoreID, model.PermissionView); err != nil {
+			return nil, err
+		}
+	}
+
+	lines, err := s.repo.ListSettlementLines(settlementID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SettlementDetail{Settlement: *settlement, Lines: lines}, nil
+}
+
+// GetPlayerEarnings returns the player's totals at every store they have consigned to (or at
+// filter.StoreID) and a page of their sales, with the total number of matching sales.
+// Unsettled sales are split at the rate recorded at sale time; the payout method chosen when
+// settling decides what the player is finally paid.
+func (s *SettlementService) GetPlayerEarnings(playerID int64, filter repository.EarningsFilter) (*model.PlayerEarnings, int, error) {
+	stores, err := s.repo.ListStoreEarnings(playerID, filter.StoreID)
+	if err != nil {
+		return nil, 0, err
+	}
+	unsettled, err := s.repo.ListUnsettledTransactions(playerID, filter.StoreID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create settlement record: %w", err)
+		return nil, 0, fmt.Errorf("error getting unsettled transactions: %w", err)
 	}
-	newSettlement.ID = settlementID
+	addUnsettledSales(stores, unsettled)
 
-	// Update all related consignment items to CLEARED
-	for _, itemID := range itemIDsToClear {
-		if err := s.consignmentRepo.UpdateConsignmentItemStatus(itemID, model.ItemStatusCleared, "Settled"); err != nil {
-			// Note: This update should ideally also be part of the DB transaction (tx)
-			return nil, fmt.Errorf("failed to update item status to cleared: %w", err)
+	sales, total, err := s.repo.ListPlayerSales(playerID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range sales {
+		if sales[i].SettlementID == nil {
+			sale := model.Transaction{Price: sales[i].Price, Quantity: sales[i].Quantity, CommissionRate: sales[i].CommissionRate}
+			sales[i].CommissionAmount, sales[i].NetAmount = sale.Split()
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit db transaction: %w", err)
+	return &model.PlayerEarnings{Stores: stores, Sales: sales}, total, nil
+}
+
+// addUnsettledSales adds each unsettled sale, and the player's share of it at the rate recorded at
+// sale time, to the totals of its store.
+func addUnsettledSales(stores []model.StoreEarnings, unsettled []model.Transaction) {
+	byStore := make(map[int64]*model.StoreEarnings, len(stores))
+	for i := range stores {
+		byStore[stores[i].StoreID] = &stores[i]
+	}
+	for _, tx := range unsettled {
+		store, ok := byStore[tx.StoreID]
+		if !ok {
+			continue
+		}
+		_, playerShare := tx.Split()
+		store.UnsettledSales += tx.Total()
+		store.UnsettledShare += playerShare
 	}
+}
 
-	return newSettlement, nil
+// ListPlayerSettlements returns the settlements requested by the given player.
+func (s *SettlementService) ListPlayerSettlements(playerID int64, filter repository.SettlementFilter) ([]model.Settlement, error) {
+	settlements, err := s.repo.ListSettlementsByPlayer(playerID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing player settlements: %w", err)
+	}
+	return settlements, nil
 }
 
-// CompleteSettlement allows a store to mark a settlement as completed.
+// ListStoreSettlements returns the settlement requests addressed to the store storeUserID works at.
+func (s *SettlementService) ListStoreSettlements(storeUserID int64, filter repository.SettlementFilter) ([]model.Settlement, error) {
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	settlements, err := s.repo.ListSettlementsByStore(store.ID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing store settlements: %w", err)
+	}
+	return settlements, nil
+}
+
+// CompleteSettlement allows a store to mark a settlement as completed once the player has been paid out.
 func (s *SettlementService) CompleteSettlement(storeUserID, settlementID int64) (*model.Settlement, error) {
-	// Implementation for store to complete a settlement
-	// 1. Get settlement
-	// 2. Verify store ownership
-	// 3. Update status
-	return nil, errors.New("not implemented yet")
-}
\ No newline at end of file
+	// 1. Get the settlement
+	settlement, err := s.repo.GetSettlementByID(settlementID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting settlement: %w", err)
+	}
+	if settlement == nil {
+		return nil, ErrSettlementNotFound
+	}
+
+	// 2. Verify the user works at the store and may pay out settlements
+	if _, err := checkStoreMember(s.storeRepo, storeUserID, settlement.StoreID, model.PermissionSettle); err != nil {
+		return nil, err
+	}
+
+	// 3. Only requested settlements can be completed
+	if settlement.Status != model.StatusRequested {
+		return nil, ErrSettlementAlreadyCompleted
+	}
+
+	// 4. Update the status; the guarded update protects against a concurrent completion
+	completedAt := time.Now()
+	updated, err := s.repo.CompleteSettlement(settlementID, completedAt, &storeUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete settlement: %w", err)
+	}
+	if !updated {
+		return nil, ErrSettlementAlreadyCompleted
+	}
+
+	settlement.Status = model.StatusCompleted
+	settlement.CompletedAt = &completedAt
+	settlement.CompletedBy = &storeUserID
+	settlement.UpdatedAt = completedAt
+	return settlement, nil
+}
+
+// splitSales splits each sale into a settlement line at the payout method's rate and totals the
+// lines. Sales charged under a commission schedule rule are split at that rule's rate for the
+// method, the others at baseRate. Every sale is split on its own, so the net is exactly the sum of
+// the shares the player would get settling the sales one by one.
+func splitSales(transactions []model.Transaction, method model.PaymentMethod, baseRate model.Rate, rules map[int64]model.CommissionRule) (lines []model.SettlementLine, gross, commission, net model.Money) {
+	for _, tx := range transactions {
+		rate := baseRate
+		if tx.CommissionRuleID != nil {
+			if rule, ok := rules[*tx.CommissionRuleID]; ok {
+				rate = rule.RateFor(method)
+			}
+		}
+
+		line := model.SettlementLine{
+			TransactionID:     tx.ID,
+			ConsignmentItemID: tx.ConsignmentItemID,
+			Price:             tx.Price,
+			Quantity:          tx.Quantity,
+			GrossAmount:       tx.Total(),
+			SoldAt:            tx.CreatedAt,
+		}
+		line.CommissionAmount, line.NetAmount = tx.SplitAt(rate)
+		lines = append(lines, line)
+
+		gross += line.GrossAmount
+		commission += line.CommissionAmount
+		net += line.NetAmount
+	}
+	return lines, gross, commission, net
+}
+
+// deductWithdrawalFees takes fees out of a payout, oldest first. A fee the remaining payout
+// cannot cover in full stays outstanding for a later settlement, so a payout never goes negative. It returns the total deducted and the IDs of the deducted fees.
+func deductWithdrawalFees(payout model.Money, fees []model.WithdrawalFee) (model.Money, []int64) {
+	amounts := make([]model.Money, len(fees))
+	for i, fee := range fees {
+		amounts[i] = fee.Amount
+	}
+	deducted, picked := deductOldestFirst(payout, amounts)
+
+	var feeIDs []int64
+	for _, i := range picked {
+		feeIDs = append(feeIDs, fees[i].ID)
+	}
+	return deducted, feeIDs
+}
+
+// deductAdjustments takes refunded shares out of a payout the same way deductWithdrawalFees
+// takes fees. It returns the total deducted and the IDs of the deducted adjustments.
+func deductAdjustments(payout model.Money, adjustments []model.SettlementAdjustment) (model.Money, []int64) {
+	amounts := make([]model.Money, len(adjustments))
+	for i, adjustment := range adjustments {
+		amounts[i] = adjustment.Amount
+	}
+	deducted, picked := deductOldestFirst(payout, amounts)
+
+	var adjustmentIDs []int64
+	for _, i := range picked {
+		adjustmentIDs = append(adjustmentIDs, adjustments[i].ID)
+	}
+	return deducted, adjustmentIDs
+}
+
+// deductOldestFirst deducts the amounts, in order, that still fit in the payout and returns
+// the total deducted and the indexes of the deducted amounts.
+func deductOldestFirst(payout model.Money, amounts []model.Money) (model.Money, []int) {
+	var deducted model.Money
+	var picked []int
+	for i, amount := range amounts {
+		if deducted+amount > payout {
+			continue
+		}
+		deducted += amount
+		picked = append(picked, i)
+	}
+	return deducted, picked
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementService_CreateSettlement(t *testing.T) {
+	t.Run("concurrent requests settle each sale only once", func(t *testing.T) {
+		db := openTestDB(t)
+		f := seedFixture(t, db)
+		item := seedApprovedItem(t, db, f, 1)
+
+		uow := NewUnitOfWork(db)
+		consignmentRepo := repository.NewConsignmentRepository(db)
+		storeRepo := repository.NewStoreRepository(db)
+		settlementRepo := repository.NewSettlementRepository(db)
+		txService := NewTransactionService(repository.NewTransactionRepository(db), consignmentRepo, repository.NewCardRepository(db), settlementRepo, repository.NewCreditRepository(db), storeRepo, uow)
+		svc := NewSettlementService(settlementRepo, consignmentRepo, repository.NewCreditRepository(db), storeRepo, uow)
+
+		_, err := txService.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+
+		const requests = 3
+		start := make(chan struct{})
+		errs := make([]error, requests)
+		var wg sync.WaitGroup
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				_, errs[i] = svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, nil)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, ErrNoUnsettledTransactions, err)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("settles only the chosen sales", func(t *testing.T) {
+		db := openTestDB(t)
+		f := seedFixture(t, db)
+		first := seedApprovedItem(t, db, f, 1)
+		second := seedApprovedItem(t, db, f, 1)
+
+		uow := NewUnitOfWork(db)
+		consignmentRepo := repository.NewConsignmentRepository(db)
+		storeRepo := repository.NewStoreRepository(db)
+		settlementRepo := repository.NewSettlementRepository(db)
+		txService := NewTransactionService(repository.NewTransactionRepository(db), consignmentRepo, repository.NewCardRepository(db), settlementRepo, repository.NewCreditRepository(db), storeRepo, uow)
+		svc := NewSettlementService(settlementRepo, consignmentRepo, repository.NewCreditRepository(db), storeRepo, uow)
+
+		sale, err := txService.CreateTransaction(f.storeUser.ID, first.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		other, err := txService.CreateTransaction(f.storeUser.ID, second.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+
+		_, err = svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, []int64{sale.ID, -1})
+		assert.Equal(t, ErrTransactionNotSettleable, err)
+
+		_, err = db.Exec(`UPDATE stores SET min_settlement_amount = 100 WHERE id = $1`, f.store.ID)
+		assert.NoError(t, err)
+		_, err = svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, []int64{sale.ID})
+		assert.Equal(t, ErrBelowMinimumSettlement, err, "one sale nets 90.00")
+
+		_, err = db.Exec(`UPDATE stores SET min_settlement_amount = 90 WHERE id = $1`, f.store.ID)
+		assert.NoError(t, err)
+		settlement, err := svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, []int64{sale.ID, sale.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money(9000), settlement.NetAmount)
+
+		detail, err := svc.GetSettlement(f.player.ID, settlement.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, detail.Lines, 1) {
+			assert.Equal(t, sale.ID, detail.Lines[0].TransactionID)
+			assert.Equal(t, first.ID, detail.Lines[0].ConsignmentItemID)
+			assert.Equal(t, model.Money(9000), detail.Lines[0].NetAmount)
+		}
+		_, err = svc.GetSettlement(f.storeUser.ID, settlement.ID)
+		assert.NoError(t, err)
+
+		// The other sale is still waiting to be settled
+		unsettled, err := settlementRepo.GetUnsettledTransactions(f.player.ID, f.store.ID, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, unsettled, 1) {
+			assert.Equal(t, other.ID, unsettled[0].ID)
+		}
+	})
+}
+
+func TestSettlementService_CreditPayout(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	item := seedApprovedItem(t, db, f, 1)
+
+	uow := NewUnitOfWork(db)
+	consignmentRepo := repository.NewConsignmentRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	creditRepo := repository.NewCreditRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	txService := NewTransactionService(repository.NewTransactionRepository(db), consignmentRepo, repository.NewCardRepository(db), settlementRepo, creditRepo, storeRepo, uow)
+	svc := NewSettlementService(settlementRepo, consignmentRepo, creditRepo, storeRepo, uow)
+
+	// Sold for cash at 10%, paid out as credit at the store's 5% credit rate
+	_, err := txService.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+	assert.NoError(t, err)
+
+	settlement, err := svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCredit, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentMethodCredit, settlement.PayoutMethod)
+	assert.Equal(t, model.Rate(500), settlement.CommissionRate)
+	assert.Equal(t, testPrice, settlement.GrossAmount)
+	assert.Equal(t, model.Money(500), settlement.CommissionAmount)
+	assert.Equal(t, model.Money(9500), settlement.NetAmount)
+	assert.Equal(t, model.Money(9500), settlement.Amount)
+	assert.Equal(t, model.StatusCompleted, settlement.Status, "a credit payout needs no handover")
+
+	balance, err := creditRepo.GetBalance(f.store.ID, f.player.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, settlement.Amount, balance)
+}
+
+func TestSettlementService_GetPlayerEarnings(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	settledItem := seedApprovedItem(t, db, f, 1)
+	item := seedApprovedItem(t, db, f, 3)
+
+	uow := NewUnitOfWork(db)
+	consignmentRepo := repository.NewConsignmentRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	txService := NewTransactionService(repository.NewTransactionRepository(db), consignmentRepo, repository.NewCardRepository(db), settlementRepo, repository.NewCreditRepository(db), storeRepo, uow)
+	svc := NewSettlementService(settlementRepo, consignmentRepo, repository.NewCreditRepository(db), storeRepo, uow)
+
+	// One sale settled at the 5% credit rate, then one of the three units of the other item sold for cash
+	_, err := txService.CreateTransaction(f.storeUser.ID, settledItem.ID, 1, testPrice, model.PaymentMethodCash, 0)
+	assert.NoError(t, err)
+	settlement, err := svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCredit, nil)
+	assert.NoError(t, err)
+	unsettled, err := txService.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+	assert.NoError(t, err)
+
+	earnings, total, err := svc.GetPlayerEarnings(f.player.ID, repository.EarningsFilter{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, earnings.Stores, 1) {
+		store := earnings.Stores[0]
+		assert.Equal(t, f.store.ID, store.StoreID)
+		assert.Equal(t, 2, store.ListedUnits)
+		assert.Equal(t, testPrice, store.UnsettledSales)
+		assert.Equal(t, model.Money(9000), store.UnsettledShare)
+		assert.Equal(t, model.Money(9500), store.SettledShare)
+		assert.Equal(t, settlement.Amount, store.PaidOut)
+	}
+
+	assert.Equal(t, 2, total)
+	if assert.Len(t, earnings.Sales, 2) {
+		assert.Equal(t, unsettled.ID, earnings.Sales[0].TransactionID, "newest first")
+		assert.Nil(t, earnings.Sales[0].SettlementID)
+		assert.Equal(t, model.Money(9000), earnings.Sales[0].NetAmount)
+		assert.Equal(t, f.card.Name, earnings.Sales[0].CardName)
+		assert.Equal(t, settlement.ID, *earnings.Sales[1].SettlementID)
+		assert.Equal(t, model.Money(500), earnings.Sales[1].CommissionAmount, "split as the settlement paid it")
+	}
+}
+
+func TestSettlementService_GetPlayerEarnings_CommissionRule(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	item := seedApprovedItem(t, db, f, 1)
+
+	uow := NewUnitOfWork(db)
+	consignmentRepo := repository.NewConsignmentRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	txService := NewTransactionService(repository.NewTransactionRepository(db), consignmentRepo, repository.NewCardRepository(db), settlementRepo, repository.NewCreditRepository(db), storeRepo, uow)
+	svc := NewSettlementService(settlementRepo, consignmentRepo, repository.NewCreditRepository(db), storeRepo, uow)
+
+	schedule := &model.CommissionSchedule{StoreID: f.store.ID, EffectiveFrom: time.Now().Add(-time.Hour), Rules: []model.CommissionRule{
+		{CommissionCash: 2000, CommissionCredit: 1500},
+	}}
+	if err := storeRepo.CreateCommissionSchedule(schedule); err != nil {
+		t.Fatalf("failed to seed commission schedule: %v", err)
+	}
+
+	// Sold under the rule and paid out in credit: split at the rule's 15%, not the store's 5%
+	_, err := txService.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+	assert.NoError(t, err)
+	_, err = svc.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCredit, nil)
+	assert.NoError(t, err)
+
+	earnings, _, err := svc.GetPlayerEarnings(f.player.ID, repository.EarningsFilter{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, earnings.Sales, 1) {
+		sale := earnings.Sales[0]
+		assert.Equal(t, model.Rate(1500), sale.CommissionRate)
+		assert.Equal(t, model.Money(1500), sale.CommissionAmount)
+		assert.Equal(t, model.Money(8500), sale.NetAmount)
+	}
+}
+
+func TestSplitSales(t *testing.T) {
+	transactions := []model.Transaction{
+		{Price: 10000, Quantity: 1, CommissionRate: 500},
+		{Price: 333, Quantity: 3, CommissionRate: 500},
+	}
+
+	// The rate frozen on the sales is ignored in favour of the payout method's rate
+	lines, gross, commission, net := splitSales(transactions, model.PaymentMethodCash, 1000, nil)
+	assert.Equal(t, model.Money(10999), gross)
+	assert.Equal(t, model.Money(1000+3*33), commission)
+	assert.Equal(t, gross-commission, net)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, model.Money(999), lines[1].GrossAmount)
+		assert.Equal(t, model.Money(99), lines[1].CommissionAmount)
+		assert.Equal(t, model.Money(900), lines[1].NetAmount)
+	}
+
+	// Sales charged under a schedule rule are split at the rule's rate for the payout method
+	ruleID := int64(7)
+	transactions[0].CommissionRuleID = &ruleID
+	rules := map[int64]model.CommissionRule{ruleID: {ID: ruleID, CommissionCash: 1500, CommissionCredit: 800}}
+	lines, _, commission, _ = splitSales(transactions, model.PaymentMethodCredit, 1000, rules)
+	assert.Equal(t, model.Money(800+3*33), commission)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, model.Money(800), lines[0].CommissionAmount)
+	}
+
+	lines, gross, commission, net = splitSales(nil, model.PaymentMethodCash, 1000, nil)
+	assert.Empty(t, lines)
+	assert.Zero(t, gross)
+	assert.Zero(t, commission)
+	assert.Zero(t, net)
+}
+
+func TestAddUnsettledSales(t *testing.T) {
+	stores := []model.StoreEarnings{{StoreID: 1}, {StoreID: 2}}
+	unsettled := []model.Transaction{
+		{StoreID: 1, Price: 10000, Quantity: 1, CommissionRate: 1000},
+		{StoreID: 1, Price: 333, Quantity: 3, CommissionRate: 500},
+		{StoreID: 3, Price: 5000, Quantity: 1, CommissionRate: 1000}, // Store not in the list
+	}
+
+	addUnsettledSales(stores, unsettled)
+	assert.Equal(t, model.Money(10999), stores[0].UnsettledSales)
+	assert.Equal(t, model.Money(9000+3*316), stores[0].UnsettledShare)
+	assert.Zero(t, stores[1].UnsettledSales)
+	assert.Zero(t, stores[1].UnsettledShare)
+}
+
+func TestDeductWithdrawalFees(t *testing.T) {
+	fees := []model.WithdrawalFee{{ID: 1, Amount: 3000}, {ID: 2, Amount: 5000}, {ID: 3, Amount: 1000}}
+
+	t.Run("payout covers every fee", func(t *testing.T) {
+		deducted, ids := deductWithdrawalFees(10000, fees)
+		assert.Equal(t, model.Money(9000), deducted)
+		assert.Equal(t, []int64{1, 2, 3}, ids)
+	})
+
+	t.Run("fees that do not fit stay outstanding", func(t *testing.T) {
+		deducted, ids := deductWithdrawalFees(5000, fees)
+		assert.Equal(t, model.Money(4000), deducted)
+		assert.Equal(t, []int64{1, 3}, ids)
+	})
+
+	t.Run("no fees", func(t *testing.T) {
+		deducted, ids := deductWithdrawalFees(5000, nil)
+		assert.Zero(t, deducted)
+		assert.Empty(t, ids)
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidStaffRole    = errors.New("staff role must be MANAGER, CASHIER or INTAKE_CLERK")
+	ErrNotStoreUser        = errors.New("only store accounts can join a store's staff")
+	ErrAlreadyInvited      = errors.New("user is already invited to or working at this store")
+	ErrStaffMemberNotFound = errors.New("staff member not found")
+	ErrInvitationNotFound  = errors.New("invitation not found")
+	ErrCannotChangeOwner   = errors.New("the store owner cannot be changed or removed")
+)
+
+// StaffService manages who works at a store. The owner invites other store accounts with a role,
+// which decides what they may do on the store's behalf (see model.StoreRole); the invite takes
+// effect once the user accepts it. A user works at one store at a time.
+type StaffService struct {
+	storeRepo *repository.StoreRepository
+	userRepo  repository.IUserRepository
+}
+
+func NewStaffService(storeRepo *repository.StoreRepository, userRepo repository.IUserRepository) *StaffService {
+	return &StaffService{
+		storeRepo: storeRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// InviteStaff invites the store account with the given email to the staff of the store userID
+// works at, with the given role.
+func (s *StaffService) InviteStaff(userID int64, email string, role model.StoreRole) (*model.StoreMember, error) {
+	if !role.IsStaff() {
+		return nil, ErrInvalidStaffRole
+	}
+
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageStaff)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	if invitee.Role != "STORE" {
+		return nil, ErrNotStoreUser
+	}
+
+	open, err := s.storeRepo.GetOpenMembership(store.ID, invitee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, ErrAlreadyInvited
+	}
+
+	member := &model.StoreMember{
+		StoreID:   store.ID,
+		UserID:    invitee.ID,
+		Email:     invitee.Email,
+		Role:      role,
+		Status:    model.MemberStatusInvited,
+		InvitedBy: &userID,
+	}
+	if err := s.storeRepo.CreateMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// ListStaff returns the staff and pending invitations of the store userID works at.
+func (s *StaffService) ListStaff(userID int64) ([]model.StoreMember, error) {
+	store, err := memberStore(s.storeRepo, userID, model.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeRepo.ListMembers(store.ID)
+}
+
+// UpdateStaffRole changes the role of a staff member, or of a pending invitation, at the store
+// userID works at. The change applies to the member's next request.
+func (s *StaffService) UpdateStaffRole(userID, memberID int64, role model.StoreRole) (*model.StoreMember, error) {
+	if !role.IsStaff() {
+		return nil, ErrInvalidStaffRole
+	}
+
+	member, err := s.staffMember(userID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storeRepo.UpdateMemberRole(member.ID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		// Removed or declined in the meantime
+		return nil, ErrStaffMemberNotFound
+	}
+
+	member.Role = role
+	return member, nil
+}
+
+// RemoveStaff takes a member off the staff of the store userID works at, or withdraws their
+// invitation. Their past actions stay recorded against them.
+func (s *StaffService) RemoveStaff(userID, memberID int64) error {
+	member, err := s.staffMember(userID, memberID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.storeRepo.UpdateMemberStatus(member.ID, member.Status, model.MemberStatusRemoved, time.Now())
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrStaffMemberNotFound
+	}
+	return nil
+}
+
+// ListMyInvitations returns the user's pending invitations to join a store's staff.
+func (s *StaffService) ListMyInvitations(userID int64) ([]model.StoreMember, error) {
+	return s.storeRepo.ListInvitations(userID)
+}
+
+// AcceptInvitation makes the user a member of the store that invited them. A user who already
+// works at a store has to leave it first (see LeaveStore); an owner cannot leave their own store.
+func (s *StaffService) AcceptInvitation(userID, invitationID int64) (*model.StoreMember, error) {
+	invitation, err := s.invitation(userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.storeRepo.GetActiveMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrAlreadyStoreMember
+	}
+
+	now := time.Now()
+	updated, err := s.storeRepo.UpdateMemberStatus(invitation.ID, model.MemberStatusInvited, model.MemberStatusActive, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		// Withdrawn by the store in the meantime
+		return nil, ErrInvitationNotFound
+	}
+
+	invitation.Status = model.MemberStatusActive
+	invitation.AcceptedAt = &now
+	invitation.UpdatedAt = now
+	return invitation, nil
+}
+
+// DeclineInvitation turns down an invitation to join a store's staff.
+func (s *StaffService) DeclineInvitation(userID, invitationID int64) error {
+	invitation, err := s.invitation(userID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.storeRepo.UpdateMemberStatus(invitation.ID, model.MemberStatusInvited, model.MemberStatusDeclined, time.Now())
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// LeaveStore takes the user off the staff of the store they work at. Their past actions stay
+// recorded against them. The owner cannot leave their own store.
+func (s *StaffService) LeaveStore(userID int64) error {
+	member, err := s.storeRepo.GetActiveMembership(userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrStoreNotFound
+	}
+	if member.Role == model.StoreRoleOwner {
+		return ErrCannotChangeOwner
+	}
+
+	updated, err := s.storeRepo.UpdateMemberStatus(member.ID, model.MemberStatusActive, model.MemberStatusRemoved, time.Now())
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// Removed by the owner in the meantime
+		return ErrStoreNotFound
+	}
+	return nil
+}
+
+// staffMember returns a pending or active staff member of the store userID works at, provided
+// userID may manage its staff. The owner's own membership cannot be managed.
+func (s *StaffService) staffMember(userID, memberID int64) (*model.StoreMember, error) {
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageStaff)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.storeRepo.GetMember(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.StoreID != store.ID ||
+		(member.Status != model.MemberStatusInvited && member.Status != model.MemberStatusActive) {
+		return nil, ErrStaffMemberNotFound
+	}
+	if member.Role == model.StoreRoleOwner {
+		return nil, ErrCannotChangeOwner
+	}
+	return member, nil
+}
+
+// invitation returns a pending invitation addressed to userID.
+func (s *StaffService) invitation(userID, invitationID int64) (*model.StoreMember, error) {
+	invitation, err := s.storeRepo.GetMember(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.UserID != userID || invitation.Status != model.MemberStatusInvited {
+		return nil, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaffService_Membership(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	userRepo := repository.NewUserRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	svc := NewStaffService(storeRepo, userRepo)
+	storeSvc := NewStoreService(storeRepo, NewUnitOfWork(db))
+	cardSvc := NewCardService(repository.NewCardRepository(db), storeRepo)
+
+	suffix := time.Now().UnixNano()
+	createUser := func(name string) *model.User {
+		user := &model.User{Email: fmt.Sprintf("staff-%s-%d@example.com", name, suffix), PasswordHash: "x", Role: "STORE"}
+		id, err := userRepo.CreateUser(user)
+		if err != nil {
+			t.Fatalf("failed to seed %s user: %v", name, err)
+		}
+		user.ID = id
+		t.Cleanup(func() { userRepo.DeleteUser(id) })
+		return user
+	}
+	staff, stranger := createUser("clerk"), createUser("stranger")
+	ownerID := f.storeUser.ID
+
+	_, err := svc.InviteStaff(ownerID, f.player.Email, model.StoreRoleCashier)
+	assert.Equal(t, ErrNotStoreUser, err)
+	_, err = svc.InviteStaff(ownerID, staff.Email, model.StoreRoleOwner)
+	assert.Equal(t, ErrInvalidStaffRole, err)
+
+	invitation, err := svc.InviteStaff(ownerID, staff.Email, model.StoreRoleCashier)
+	if err != nil {
+		t.Fatalf("failed to invite staff: %v", err)
+	}
+	assert.Equal(t, model.MemberStatusInvited, invitation.Status)
+	_, err = svc.InviteStaff(ownerID, staff.Email, model.StoreRoleManager)
+	assert.Equal(t, ErrAlreadyInvited, err)
+
+	// An invitation grants nothing until it is accepted
+	_, err = cardSvc.GetCard(staff.ID, f.card.ID)
+	assert.Equal(t, ErrForbidden, err)
+
+	invitations, err := svc.ListMyInvitations(staff.ID)
+	if assert.NoError(t, err) && assert.Len(t, invitations, 1) {
+		assert.Equal(t, f.store.ID, invitations[0].StoreID)
+	}
+	_, err = svc.AcceptInvitation(stranger.ID, invitation.ID)
+	assert.Equal(t, ErrInvitationNotFound, err)
+
+	member, err := svc.AcceptInvitation(staff.ID, invitation.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.MemberStatusActive, member.Status)
+		assert.NotNil(t, member.AcceptedAt)
+	}
+	_, err = storeSvc.CreateStore(staff.ID, StoreSettings{Name: "Side Business"})
+	assert.Equal(t, ErrAlreadyStoreMember, err)
+
+	t.Run("cashier can view but not edit cards or staff", func(t *testing.T) {
+		_, err := cardSvc.GetCard(staff.ID, f.card.ID)
+		assert.NoError(t, err)
+		_, err = cardSvc.UpdateCard(staff.ID, f.card.ID, "Renamed", "", "", "")
+		assert.Equal(t, ErrPermissionDenied, err)
+		_, err = svc.InviteStaff(staff.ID, stranger.Email, model.StoreRoleCashier)
+		assert.Equal(t, ErrPermissionDenied, err)
+		_, err = storeSvc.UpdateStore(staff.ID, StoreSettings{Name: "Taken Over"})
+		assert.Equal(t, ErrPermissionDenied, err)
+	})
+
+	t.Run("intake clerk can add cards, recorded against them", func(t *testing.T) {
+		updated, err := svc.UpdateStaffRole(ownerID, invitation.ID, model.StoreRoleIntakeClerk)
+		if assert.NoError(t, err) {
+			assert.Equal(t, model.StoreRoleIntakeClerk, updated.Role)
+		}
+
+		card, err := cardSvc.CreateCard(staff.ID, "Clerk Card", "", "", "", nil, "")
+		if assert.NoError(t, err) {
+			assert.Equal(t, f.store.ID, card.StoreID)
+			saved, err := cardSvc.GetCard(ownerID, card.ID)
+			if assert.NoError(t, err) && assert.NotNil(t, saved.CreatedBy) {
+				assert.Equal(t, staff.ID, *saved.CreatedBy)
+			}
+		}
+	})
+
+	t.Run("owner cannot be changed or removed", func(t *testing.T) {
+		members, err := svc.ListStaff(ownerID)
+		if !assert.NoError(t, err) || !assert.Len(t, members, 2) {
+			return
+		}
+		assert.Equal(t, model.StoreRoleOwner, members[0].Role)
+		assert.Equal(t, ErrCannotChangeOwner, svc.RemoveStaff(ownerID, members[0].ID))
+		_, err = svc.UpdateStaffRole(ownerID, members[0].ID, model.StoreRoleCashier)
+		assert.Equal(t, ErrCannotChangeOwner, err)
+	})
+
+	assert.NoError(t, svc.RemoveStaff(ownerID, invitation.ID))
+	_, err = cardSvc.GetCard(staff.ID, f.card.ID)
+	assert.Equal(t, ErrForbidden, err, "removed staff lose access")
+	assert.Equal(t, ErrStaffMemberNotFound, svc.RemoveStaff(ownerID, invitation.ID))
+
+	t.Run("staff can leave on their own, owners cannot", func(t *testing.T) {
+		invitation, err := svc.InviteStaff(ownerID, stranger.Email, model.StoreRoleCashier)
+		if err != nil {
+			t.Fatalf("failed to invite staff: %v", err)
+		}
+		_, err = svc.AcceptInvitation(stranger.ID, invitation.ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, svc.LeaveStore(stranger.ID))
+		_, err = cardSvc.GetCard(stranger.ID, f.card.ID)
+		assert.Equal(t, ErrForbidden, err, "former staff lose access")
+		assert.Equal(t, ErrStoreNotFound, svc.LeaveStore(stranger.ID))
+		assert.Equal(t, ErrCannotChangeOwner, svc.LeaveStore(ownerID))
+	})
+}
@@ -3,28 +3,112 @@ package service
 import (
 	"card_manage/internal/model"
 	"card_manage/internal/repository"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
+var (
+	ErrInvalidCommissionRate  = errors.New("commission rates must be between 0 and 100 percent")
+	ErrInvalidWithdrawalFee   = errors.New("withdrawal fee cannot be negative")
+	ErrInvalidExpiryPolicy    = errors.New("agreement and price drop days cannot be negative, and the price drop rate must be between 0 and 100 percent")
+	ErrInvalidMinSettlement   = errors.New("minimum settlement amount cannot be negative")
+	ErrStoreNotActive         = errors.New("store is not active")
+	ErrInvalidStoreTransition = errors.New("store cannot be moved to that status")
+	ErrInvalidStoreStatus     = errors.New("invalid store status")
+	ErrPermissionDenied       = errors.New("your store role does not allow this action")
+	ErrAlreadyStoreMember     = errors.New("user already works at a store")
+
+	ErrEmptyCommissionSchedule    = errors.New("a commission schedule needs at least one rule")
+	ErrInvalidCommissionRule      = errors.New("commission rules need rates between 0 and 100 percent and a maximum price above the minimum")
+	ErrOverlappingCommissionRules = errors.New("commission rules for the same cards cannot have overlapping price bands")
+	ErrScheduleInPast             = errors.New("a commission schedule cannot take effect in the past")
+	ErrCommissionScheduleNotFound = errors.New("commission schedule not found")
+	ErrScheduleInForce            = errors.New("commission schedule has already taken effect")
+)
+
+// StoreSettings are the store details chosen by its owner.
+type StoreSettings struct {
+	Name             string
+	Address          string
+	City             string
+	Phone            string
+	Email            string
+	OpeningHours     string
+	CommissionCash   model.Rate
+	CommissionCredit model.Rate
+	WithdrawalFee    model.Money
+	AgreementDays    int         // Days an approved item stays on sale; 0 for no expiry
+	PriceDropDays    int         // Lower listed prices every N days; 0 for never
+	PriceDropRate    model.Rate  // Percentage taken off at each price drop
+	MinSettlement    model.Money // Smallest player share a settlement may pay out; 0 for none
+}
+
+func (s StoreSettings) validate() error {
+	if !s.CommissionCash.Valid() || !s.CommissionCredit.Valid() {
+		return ErrInvalidCommissionRate
+	}
+	if s.WithdrawalFee < 0 {
+		return ErrInvalidWithdrawalFee
+	}
+	if s.AgreementDays < 0 || s.PriceDropDays < 0 || !s.PriceDropRate.Valid() {
+		return ErrInvalidExpiryPolicy
+	}
+	if s.MinSettlement < 0 {
+		return ErrInvalidMinSettlement
+	}
+	return nil
+}
+
+// applyTo copies the settings onto store.
+func (s StoreSettings) applyTo(store *model.Store) {
+	store.Name = s.Name
+	store.Address = s.Address
+	store.City = s.City
+	store.Phone = s.Phone
+	store.Email = s.Email
+	store.OpeningHours = s.OpeningHours
+	store.CommissionCash = s.CommissionCash
+	store.CommissionCredit = s.CommissionCredit
+	store.WithdrawalFee = s.WithdrawalFee
+	store.AgreementDays = s.AgreementDays
+	store.PriceDropDays = s.PriceDropDays
+	store.PriceDropRate = s.PriceDropRate
+	store.MinSettlement = s.MinSettlement
+}
+
 type StoreService struct {
 	storeRepo *repository.StoreRepository
+	uow       *UnitOfWork
 }
 
-func NewStoreService(storeRepo *repository.StoreRepository) *StoreService {
-	return &StoreService{storeRepo: storeRepo}
+func NewStoreService(storeRepo *repository.StoreRepository, uow *UnitOfWork) *StoreService {
+	return &StoreService{storeRepo: storeRepo, uow: uow}
 }
 
 // CreateStore handles the business logic for creating a new store.
 // It links the store to the user ID provided.
-func (s *StoreService) CreateStore(userID int64, name string, commissionCash, commissionCredit float64) (*model.Store, error) {
-	// In the future, we might add validation here, e.g., check if a user already has a store.
+func (s *StoreService) CreateStore(userID int64, settings StoreSettings) (*model.Store, error) {
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	// Staff work at one store at a time, and owners own one store
+	member, err := s.storeRepo.GetActiveMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil {
+		return nil, ErrAlreadyStoreMember
+	}
 
 	newStore := &model.Store{
-		UserID:           userID,
-		Name:             name,
-		CommissionCash:   commissionCash,
-		CommissionCredit: commissionCredit,
+		UserID: userID,
+		Status: model.StoreStatusPendingReview, // Opens once an admin approves it
 	}
+	settings.applyTo(newStore)
 
 	storeID, err := s.storeRepo.CreateStore(newStore)
 	if err != nil {
@@ -35,3 +119,238 @@ func (s *StoreService) CreateStore(userID int64, name string, commissionCash, co
 
 	return newStore, nil
 }
+
+// GetMyStore returns the store userID works at, whatever its review status, so that its staff
+// can see why it was rejected or suspended.
+func (s *StoreService) GetMyStore(userID int64) (*model.Store, error) {
+	return memberStore(s.storeRepo, userID, model.PermissionView)
+}
+
+// UpdateStore replaces the profile, commission rates and policies of the store userID works at.
+// New rates apply to sales made from now on; sales already made keep the rate they were made at.
+func (s *StoreService) UpdateStore(userID int64, settings StoreSettings) (*model.Store, error) {
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageStore)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.applyTo(store)
+	if err := s.storeRepo.UpdateStore(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// CreateCommissionSchedule adds a new version of the commission terms of the store userID works at.
+// It takes effect at effectiveFrom, or straight away when that is zero, and stays in force until a
+// later schedule takes effect. Sales no rule covers are charged the store's base rates.
+func (s *StoreService) CreateCommissionSchedule(userID int64, effectiveFrom time.Time, note string, rules []model.CommissionRule) (*model.CommissionSchedule, error) {
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+	if effectiveFrom.Before(now.Add(-time.Minute)) { // Allow for clock skew with the client
+		return nil, ErrScheduleInPast
+	}
+
+	schedule := &model.CommissionSchedule{EffectiveFrom: effectiveFrom, Note: note, CreatedBy: &userID, Rules: rules}
+	if len(rules) == 0 {
+		return nil, ErrEmptyCommissionSchedule
+	}
+	for i := range rules {
+		if !rules[i].Valid() {
+			return nil, ErrInvalidCommissionRule
+		}
+	}
+	if schedule.HasOverlappingRules() {
+		return nil, ErrOverlappingCommissionRules
+	}
+
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageStore)
+	if err != nil {
+		return nil, err
+	}
+	schedule.StoreID = store.ID
+
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		return s.storeRepo.WithTx(tx).CreateCommissionSchedule(schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ListCommissionSchedules returns every commission schedule of the store userID works at, past,
+// current and upcoming, latest effective date first.
+func (s *StoreService) ListCommissionSchedules(userID int64) ([]model.CommissionSchedule, error) {
+	store, err := s.GetMyStore(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeRepo.ListCommissionSchedules(store.ID)
+}
+
+// DeleteCommissionSchedule withdraws a schedule of the store userID works at before it takes effect.
+// Schedules that have taken effect are kept, as sales refer to their rules.
+func (s *StoreService) DeleteCommissionSchedule(userID, scheduleID int64) error {
+	store, err := memberStore(s.storeRepo, userID, model.PermissionManageStore)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := s.storeRepo.GetCommissionSchedule(scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil || schedule.StoreID != store.ID {
+		return ErrCommissionScheduleNotFound
+	}
+
+	deleted, err := s.storeRepo.DeleteCommissionSchedule(scheduleID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScheduleInForce
+	}
+	return nil
+}
+
+// SearchStores returns one page of the public store directory with the total number of
+// matching stores. Only active stores are listed.
+func (s *StoreService) SearchStores(filter repository.StoreFilter) ([]model.StoreProfile, int, error) {
+	stores, total, err := s.storeRepo.SearchStores(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	profiles := make([]model.StoreProfile, len(stores))
+	for i := range stores {
+		profiles[i] = stores[i].Profile()
+	}
+	return profiles, total, nil
+}
+
+// GetStoreProfile returns the public profile of an active store.
+func (s *StoreService) GetStoreProfile(storeID int64) (*model.StoreProfile, error) {
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting store: %w", err)
+	}
+	if store == nil || !store.IsActive() {
+		return nil, ErrTargetStoreNotFound
+	}
+
+	profile := store.Profile()
+	return &profile, nil
+}
+
+// ListStores returns the stores in the given status for admins, or every store when status is empty.
+func (s *StoreService) ListStores(status model.StoreStatus) ([]model.Store, error) {
+	switch status {
+	case "", model.StoreStatusPendingReview, model.StoreStatusActive, model.StoreStatusInactive,
+		model.StoreStatusRejected, model.StoreStatusSuspended:
+	default:
+		return nil, ErrInvalidStoreStatus
+	}
+	return s.storeRepo.ListStores(status)
+}
+
+// ApproveStore opens a store that is pending review, was rejected or is suspended.
+func (s *StoreService) ApproveStore(adminID, storeID int64) (*model.Store, error) {
+	return s.reviewStore(adminID, storeID, model.StoreStatusActive, "")
+}
+
+// RejectStore turns down a store registration. The reason is kept on the store for its owner.
+func (s *StoreService) RejectStore(adminID, storeID int64, reason string) (*model.Store, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+	return s.reviewStore(adminID, storeID, model.StoreStatusRejected, reason)
+}
+
+// SuspendStore closes an active store. Its cards, consignments and sales stay as they are,
+// but the store cannot act on them until it is approved again.
+func (s *StoreService) SuspendStore(adminID, storeID int64, reason string) (*model.Store, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+	return s.reviewStore(adminID, storeID, model.StoreStatusSuspended, reason)
+}
+
+func (s *StoreService) reviewStore(adminID, storeID int64, next model.StoreStatus, reason string) (*model.Store, error) {
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting store: %w", err)
+	}
+	if store == nil {
+		return nil, ErrTargetStoreNotFound
+	}
+	if !store.Status.CanTransitionTo(next) {
+		return nil, ErrInvalidStoreTransition
+	}
+
+	now := time.Now()
+	updated, err := s.storeRepo.UpdateStoreStatus(store.ID, store.Status, next, reason, adminID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		// Another admin reviewed the store first
+		return nil, ErrInvalidStoreTransition
+	}
+
+	store.Status = next
+	store.StatusReason = reason
+	store.ReviewedBy = &adminID
+	store.ReviewedAt = &now
+	store.UpdatedAt = now
+	return store, nil
+}
+
+// memberStore returns the store userID works at, provided their role there grants the permission.
+// It returns ErrStoreNotFound when the user is on no store's staff.
+func memberStore(storeRepo *repository.StoreRepository, userID int64, permission model.StorePermission) (*model.Store, error) {
+	member, err := storeRepo.GetActiveMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrStoreNotFound
+	}
+	return permittedStore(storeRepo, member, permission)
+}
+
+// checkStoreMember checks that userID works at the given store and that their role there grants
+// the permission. Users who work elsewhere, or at no store, get ErrForbidden.
+func checkStoreMember(storeRepo *repository.StoreRepository, userID, storeID int64, permission model.StorePermission) (*model.Store, error) {
+	member, err := storeRepo.GetActiveMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.StoreID != storeID {
+		return nil, ErrForbidden
+	}
+	return permittedStore(storeRepo, member, permission)
+}
+
+// permittedStore returns the member's store if their role grants the permission.
+func permittedStore(storeRepo *repository.StoreRepository, member *model.StoreMember, permission model.StorePermission) (*model.Store, error) {
+	if !member.Role.Can(permission) {
+		return nil, ErrPermissionDenied
+	}
+
+	store, err := storeRepo.GetStoreByID(member.StoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting store: %w", err)
+	}
+	if store == nil {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreService_Review(t *testing.T) {
+	db := openTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	svc := NewStoreService(storeRepo, NewUnitOfWork(db))
+	cardSvc := NewCardService(repository.NewCardRepository(db), storeRepo)
+
+	suffix := time.Now().UnixNano()
+	createUser := func(role string) int64 {
+		id, err := userRepo.CreateUser(&model.User{Email: fmt.Sprintf("review-%s-%d@example.com", role, suffix), PasswordHash: "x", Role: role})
+		if err != nil {
+			t.Fatalf("failed to seed %s user: %v", role, err)
+		}
+		t.Cleanup(func() { userRepo.DeleteUser(id) })
+		return id
+	}
+	ownerID, adminID := createUser("STORE"), createUser("ADMIN")
+
+	store, err := svc.CreateStore(ownerID, StoreSettings{Name: "New Store", CommissionCash: 1000, CommissionCredit: 500})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	assert.Equal(t, model.StoreStatusPendingReview, store.Status)
+
+	pending, err := svc.ListStores(model.StoreStatusPendingReview)
+	assert.NoError(t, err)
+	assert.Contains(t, storeIDs(pending), store.ID)
+
+	_, err = cardSvc.ListCardsByCurrentUser(ownerID)
+	assert.Equal(t, ErrStoreNotActive, err, "a pending store cannot manage cards")
+
+	_, err = svc.SuspendStore(adminID, store.ID, "spam")
+	assert.Equal(t, ErrInvalidStoreTransition, err, "only active stores can be suspended")
+	_, err = svc.RejectStore(adminID, store.ID, " ")
+	assert.Equal(t, ErrReasonRequired, err)
+
+	approved, err := svc.ApproveStore(adminID, store.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.StoreStatusActive, approved.Status)
+	}
+	_, err = cardSvc.ListCardsByCurrentUser(ownerID)
+	assert.NoError(t, err)
+
+	_, err = svc.SuspendStore(adminID, store.ID, "unpaid fees")
+	assert.NoError(t, err)
+	saved, err := storeRepo.GetStoreByID(store.ID)
+	if err != nil || saved == nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+	assert.Equal(t, model.StoreStatusSuspended, saved.Status)
+	assert.Equal(t, "unpaid fees", saved.StatusReason)
+	if assert.NotNil(t, saved.ReviewedBy) {
+		assert.Equal(t, adminID, *saved.ReviewedBy)
+	}
+
+	_, err = cardSvc.ListCardsByCurrentUser(ownerID)
+	assert.Equal(t, ErrStoreNotActive, err, "a suspended store cannot manage cards")
+
+	_, err = svc.ApproveStore(adminID, -1)
+	assert.Equal(t, ErrTargetStoreNotFound, err)
+	_, err = svc.ListStores("OPEN")
+	assert.Equal(t, ErrInvalidStoreStatus, err)
+}
+
+func storeIDs(stores []model.Store) []int64 {
+	ids := make([]int64, len(stores))
+	for i, store := range stores {
+		ids[i] = store.ID
+	}
+	return ids
+}
+
+func TestStoreService_ProfileAndDirectory(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	svc := NewStoreService(repository.NewStoreRepository(db), NewUnitOfWork(db))
+
+	city := fmt.Sprintf("Tainan-%d", time.Now().UnixNano())
+	_, err := svc.UpdateStore(f.storeUser.ID, StoreSettings{Name: "Card 100% Shop", CommissionCash: 20001})
+	assert.Equal(t, ErrInvalidCommissionRate, err)
+
+	updated, err := svc.UpdateStore(f.storeUser.ID, StoreSettings{
+		Name:             "Card 100% Shop",
+		City:             city,
+		Address:          "No. 1, Zhongzheng Rd.",
+		OpeningHours:     "Mon-Fri 12:00-21:00",
+		CommissionCash:   1500,
+		CommissionCredit: 800,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.StoreStatusActive, updated.Status, "editing the profile keeps the review status")
+	}
+
+	mine, err := svc.GetMyStore(f.storeUser.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, city, mine.City)
+		assert.Equal(t, model.Rate(1500), mine.CommissionCash)
+	}
+
+	stores, total, err := svc.SearchStores(repository.StoreFilter{City: strings.ToUpper(city), Name: "100%", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, stores, 1) {
+		assert.Equal(t, f.store.ID, stores[0].ID)
+		assert.Equal(t, "Mon-Fri 12:00-21:00", stores[0].OpeningHours)
+	}
+
+	_, total, err = svc.SearchStores(repository.StoreFilter{City: city, Name: "1_0", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total, "wildcards in the search match literally")
+
+	profile, err := svc.GetStoreProfile(f.store.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Card 100% Shop", profile.Name)
+	}
+
+	_, err = db.Exec(`UPDATE stores SET status = 'SUSPENDED' WHERE id = $1`, f.store.ID)
+	assert.NoError(t, err)
+	_, err = svc.GetStoreProfile(f.store.ID)
+	assert.Equal(t, ErrTargetStoreNotFound, err, "suspended stores are not in the directory")
+	_, total, err = svc.SearchStores(repository.StoreFilter{City: city, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestStoreService_CreateCommissionScheduleValidation(t *testing.T) {
+	svc := &StoreService{} // Rejected before the store is looked up
+	ten := model.Money(1000)
+
+	_, err := svc.CreateCommissionSchedule(1, time.Now().Add(-time.Hour), "", []model.CommissionRule{{}})
+	assert.Equal(t, ErrScheduleInPast, err)
+	_, err = svc.CreateCommissionSchedule(1, time.Time{}, "", nil)
+	assert.Equal(t, ErrEmptyCommissionSchedule, err)
+	_, err = svc.CreateCommissionSchedule(1, time.Time{}, "", []model.CommissionRule{{MinPrice: ten, MaxPrice: &ten}})
+	assert.Equal(t, ErrInvalidCommissionRule, err)
+	_, err = svc.CreateCommissionSchedule(1, time.Time{}, "", []model.CommissionRule{{}, {MinPrice: ten}})
+	assert.Equal(t, ErrOverlappingCommissionRules, err)
+}
+
+func TestStoreService_CommissionSchedules(t *testing.T) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	svc := NewStoreService(repository.NewStoreRepository(db), NewUnitOfWork(db))
+
+	rules := []model.CommissionRule{{CommissionCash: 1200, CommissionCredit: 700}, {Rarity: "SR", CommissionCash: 800, CommissionCredit: 400}}
+	current, err := svc.CreateCommissionSchedule(f.storeUser.ID, time.Time{}, "", rules)
+	assert.NoError(t, err)
+	upcoming, err := svc.CreateCommissionSchedule(f.storeUser.ID, time.Now().AddDate(0, 1, 0), "summer rates", rules[:1])
+	assert.NoError(t, err)
+
+	schedules, err := svc.ListCommissionSchedules(f.storeUser.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 2) {
+		assert.Equal(t, upcoming.ID, schedules[0].ID, "latest effective date first")
+		assert.Len(t, schedules[1].Rules, 2)
+	}
+
+	assert.Equal(t, ErrScheduleInForce, svc.DeleteCommissionSchedule(f.storeUser.ID, current.ID))
+	assert.Equal(t, ErrStoreNotFound, svc.DeleteCommissionSchedule(f.player.ID, upcoming.ID), "players have no store")
+	assert.NoError(t, svc.DeleteCommissionSchedule(f.storeUser.ID, upcoming.ID))
+}
@@ -6,38 +6,503 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
 	ErrItemNotApproved = errors.New("consignment item is not approved for sale")
 	ErrItemAlreadySold = errors.New("consignment item has already been sold")
+	ErrPriceBelowFloor = errors.New("sale price is below the player's minimum price")
+	ErrInsufficientQuantity = errors.New("not enough units of the item are available")
+	ErrEmptySale = errors.New("a sale needs at least one line")
+	ErrInvalidTender = errors.New("each tender needs a valid payment method and a positive amount")
+	ErrUnassignedLine = errors.New("a sale paid with several payment methods must assign one to every line")
+	ErrTenderMismatch = errors.New("tenders do not add up to the lines paid with each payment method")
+	ErrSaleNotFound = errors.New("sale not found")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrReasonRequired = errors.New("a reason is required")
+	ErrNotASale = errors.New("only sales can be voided or refunded")
+	ErrAlreadyReversed = errors.New("transaction has already been voided or refunded")
+	ErrVoidNotAllowed = errors.New("only unsettled sales made today can be voided")
+	ErrRefundNeedsAdjustment = errors.New("item has already been settled; refunding it requires adjusting the settlement")
 )
 
+// SaleLine is one consignment item rung up in a sale. PaymentMethod says which tender pays for
+// the line and decides its commission rate; it may be left empty when the sale has one tender method.
+type SaleLine struct {
+	ItemID        int64
+	Quantity      int
+	Price         model.Money // Unit price
+	PaymentMethod model.PaymentMethod
+}
+
+// Total returns the unit price times the quantity.
+func (l SaleLine) Total() model.Money {
+	return l.Price.Mul(int64(l.Quantity))
+}
+
+// SaleLineError reports which line of a sale failed, so the cashier can fix that line.
+type SaleLineError struct {
+	Index int
+	Err   error
+}
+
+func (e *SaleLineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Index+1, e.Err)
+}
+
+func (e *SaleLineError) Unwrap() error {
+	return e.Err
+}
+
 type TransactionService struct {
 	repo            *repository.TransactionRepository
 	consignmentRepo *repository.ConsignmentRepository
+	cardRepo        *repository.CardRepository
+	settlementRepo  *repository.SettlementRepository
+	creditRepo      *repository.CreditRepository
 	storeRepo       *repository.StoreRepository
-	db              *sql.DB
+	uow             *UnitOfWork
 }
 
 func NewTransactionService(
 	repo *repository.TransactionRepository,
 	consignmentRepo *repository.ConsignmentRepository,
+	cardRepo *repository.CardRepository,
+	settlementRepo *repository.SettlementRepository,
+	creditRepo *repository.CreditRepository,
 	storeRepo *repository.StoreRepository,
-	db *sql.DB,
+	uow *UnitOfWork,
 ) *TransactionService {
 	return &TransactionService{
 		repo:            repo,
 		consignmentRepo: consignmentRepo,
+		cardRepo:        cardRepo,
+		settlementRepo:  settlementRepo,
+		creditRepo:      creditRepo,
 		storeRepo:       storeRepo,
-		db:              db,
+		uow:             uow,
+	}
+}
+
+// CreateTransaction creates a new transaction selling quantity units of a consignment item at
+// the given unit price. The item row is locked for the duration of the sale, so two cashiers selling
+// the same card concurrently cannot both succeed: the second one sees the item as SOLD.
+// Selling part of an item splits the sold units off into a new SOLD item, which the transaction
+// refers to, and leaves the rest on sale under the original item ID.
+// A CREDIT sale is paid from buyerID's store credit, which must cover the total; buyerID is
+// ignored for other payment methods.
+func (s *TransactionService) CreateTransaction(storeUserID, itemID int64, quantity int, price model.Money, paymentMethod model.PaymentMethod, buyerID int64) (*model.Transaction, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if paymentMethod == model.PaymentMethodCredit && buyerID == 0 {
+		return nil, ErrBuyerRequired
+	}
+
+	// Verify the store up front; it does not change during the sale
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionSell)
+	if err == ErrStoreNotFound {
+		return nil, ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	line := SaleLine{ItemID: itemID, Quantity: quantity, Price: price, PaymentMethod: paymentMethod}
+	var newTxModel *model.Transaction
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		newTxModel, err = s.sellLine(tx, store, storeUserID, line, nil, buyerID)
+		if err != nil || paymentMethod != model.PaymentMethodCredit {
+			return err
+		}
+
+		// Pay from the buyer's store credit
+		spend := &model.CreditEntry{StoreID: store.ID, UserID: buyerID, TransactionID: &newTxModel.ID, ActorID: &storeUserID}
+		return spendCredit(s.creditRepo.WithTx(tx), spend, newTxModel.Total())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newTxModel, nil
+}
+
+// Checkout sells a basket of items under one receipt. The lines are paid with the given tenders;
+// each line's commission uses the rate of the payment method assigned to it. Either every line is
+// sold or, if any line fails, nothing is: the error is a *SaleLineError naming the failing line.
+// CREDIT tenders are paid from buyerID's store credit; buyerID is ignored when there are none.
+func (s *TransactionService) Checkout(storeUserID, buyerID int64, lines []SaleLine, tenders []model.SaleTender) (*model.Sale, error) {
+	lines, err := assignPaymentMethods(lines, tenders)
+	if err != nil {
+		return nil, err
+	}
+
+	var creditTendered model.Money
+	for _, tender := range tenders {
+		if tender.PaymentMethod == model.PaymentMethodCredit {
+			creditTendered += tender.Amount
+		}
+	}
+	if creditTendered > 0 && buyerID == 0 {
+		return nil, ErrBuyerRequired
+	}
+
+	store, err := memberStore(s.storeRepo, storeUserID, model.PermissionSell)
+	if err == ErrStoreNotFound {
+		return nil, ErrForbidden
 	}
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	sale := &model.Sale{StoreID: store.ID, CashierID: storeUserID}
+	for _, line := range lines {
+		sale.Total += line.Total()
+	}
+	if creditTendered > 0 {
+		sale.BuyerID = &buyerID
+	}
+
+	err = s.uow.Do(func(tx *sql.Tx) error {
+		repo := s.repo.WithTx(tx)
+		if err := repo.CreateSale(sale); err != nil {
+			return err
+		}
+
+		sale.Tenders = make([]model.SaleTender, 0, len(tenders))
+		for _, tender := range tenders {
+			tender.SaleID = sale.ID
+			if err := repo.CreateSaleTender(&tender); err != nil {
+				return err
+			}
+			sale.Tenders = append(sale.Tenders, tender)
+		}
+		if creditTendered > 0 {
+			spend := &model.CreditEntry{StoreID: store.ID, UserID: buyerID, SaleID: &sale.ID, ActorID: &storeUserID}
+			if err := spendCredit(s.creditRepo.WithTx(tx), spend, creditTendered); err != nil {
+				return err
+			}
+		}
+
+		sale.Lines = make([]model.Transaction, 0, len(lines))
+		for i, line := range lines {
+			txModel, err := s.sellLine(tx, store, storeUserID, line, &sale.ID, buyerID)
+			if err != nil {
+				return &SaleLineError{Index: i, Err: err}
+			}
+			sale.Lines = append(sale.Lines, *txModel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// GetSale returns a sale with its lines and tenders. Only the store that made the sale may see it.
+func (s *TransactionService) GetSale(storeUserID, saleID int64) (*model.Sale, error) {
+	sale, err := s.repo.GetSaleByID(saleID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sale: %w", err)
+	}
+	if sale == nil {
+		return nil, ErrSaleNotFound
+	}
+
+	store, err := checkStoreMember(s.storeRepo, storeUserID, sale.StoreID, model.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	if !store.IsActive() {
+		return nil, ErrStoreNotActive
+	}
+
+	return sale, nil
 }
 
-// CreateTransaction creates a new transaction for a specific consignment item.
-func (s *TransactionService) CreateTransaction(storeUserID, itemID int64, price float64, paymentMethod model.PaymentMethod) (*model.Transaction, error) {
-	// 1. Get the consignment item and its parent consignment
-	item, err := s.consignmentRepo.GetConsignmentItemByID(itemID)
+// VoidTransaction cancels a sale rung up in error, as if it had never happened: the sale is
+// marked voided and the item goes back on sale. Voids are only allowed on the day of the sale and
+// before the item has been settled; later corrections go through RefundTransaction.
+func (s *TransactionService) VoidTransaction(storeUserID, transactionID int64, reason string) (*model.Transaction, error) {
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	var sale *model.Transaction
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		var item *model.ConsignmentItem
+		var err error
+		sale, item, err = s.lockSaleForReversal(tx, storeUserID, transactionID, model.PermissionSell)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if item.Status != model.ItemStatusSold || !sale.CanVoid(now) {
+			return ErrVoidNotAllowed
+		}
+
+		voided, err := s.repo.WithTx(tx).VoidTransaction(sale.ID, storeUserID, reason, now)
+		if err != nil {
+			return err
+		}
+		if !voided {
+			return ErrAlreadyReversed
+		}
+		sale.VoidedAt = &now
+		sale.VoidedBy = &storeUserID
+		sale.VoidReason = reason
+
+		if err := s.returnCredit(tx, sale, sale.ID, storeUserID, reason); err != nil {
+			return err
+		}
+
+		return s.putBackOnSale(tx, item, storeUserID, fmt.Sprintf("transaction %d voided: %s", sale.ID, reason))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// RefundTransaction reverses a sale when the buyer returns the card: it writes a REFUND
+// transaction with the sale's unit price and a negative quantity, and puts the item back on sale.
+// An item that has already been settled is only refunded when adjustSettlement is set; the
+// player's share of the sale, if any, is then taken back from their next settlement with the store.
+// It returns the refund transaction.
+func (s *TransactionService) RefundTransaction(storeUserID, transactionID int64, reason string, adjustSettlement bool) (*model.Transaction, error) {
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	var refund *model.Transaction
+	err := s.uow.Do(func(tx *sql.Tx) error {
+		sale, item, err := s.lockSaleForReversal(tx, storeUserID, transactionID, model.PermissionRefund)
+		if err != nil {
+			return err
+		}
+		if item.Status == model.ItemStatusCleared && !adjustSettlement {
+			return ErrRefundNeedsAdjustment
+		}
+
+		// 1. Write the reversing transaction
+		refund = &model.Transaction{
+			ConsignmentItemID: sale.ConsignmentItemID,
+			StoreID:           sale.StoreID,
+			Type:              model.TransactionTypeRefund,
+			ReversesID:        &sale.ID,
+			Price:             sale.Price,
+			Quantity:          -sale.Quantity,
+			PaymentMethod:     sale.PaymentMethod,
+			CommissionRate:    sale.CommissionRate,
+			CommissionRuleID:  sale.CommissionRuleID,
+			ConditionGrade:    sale.ConditionGrade,
+			ActorID:           &storeUserID,
+			BuyerID:           sale.BuyerID,
+			Reason:            reason,
+		}
+		refundID, err := s.repo.WithTx(tx).CreateTransaction(refund)
+		if err != nil {
+			return fmt.Errorf("failed to create refund transaction: %w", err)
+		}
+		refund.ID = refundID
+		if err := s.returnCredit(tx, sale, refundID, storeUserID, reason); err != nil {
+			return err
+		}
+
+		// 2. Take the player's share back from their next settlement if it was already paid out
+		if item.Status == model.ItemStatusCleared {
+			consignment, err := s.consignmentRepo.WithTx(tx).GetConsignmentByID(item.ConsignmentID)
+			if err != nil {
+				return fmt.Errorf("error getting parent consignment: %w", err)
+			}
+			// Take back what the settlement actually paid for the sale, which depends on its payout method
+			settlementRepo := s.settlementRepo.WithTx(tx)
+			_, playerShare := sale.Split()
+			line, err := settlementRepo.GetSettlementLineByTransaction(sale.ID)
+			if err != nil {
+				return err
+			}
+			if line != nil {
+				playerShare = line.NetAmount
+			}
+			// Nothing to take back when the store kept the whole sale as commission
+			if playerShare > 0 {
+				adjustment := &model.SettlementAdjustment{
+					TransactionID: refundID,
+					PlayerID:      consignment.PlayerID,
+					StoreID:       sale.StoreID,
+					Amount:        playerShare,
+				}
+				if err := settlementRepo.CreateSettlementAdjustment(adjustment); err != nil {
+					return err
+				}
+			}
+		}
+
+		// 3. Put the item back on sale
+		return s.putBackOnSale(tx, item, storeUserID, fmt.Sprintf("refunded in transaction %d: %s", refundID, reason))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// returnCredit gives the total of a voided or refunded CREDIT sale back to the buyer's store credit,
+// linking the entry to transactionID: the voided sale itself, or the refund.
+func (s *TransactionService) returnCredit(tx *sql.Tx, sale *model.Transaction, transactionID, actorID int64, reason string) error {
+	if sale.PaymentMethod != model.PaymentMethodCredit || sale.BuyerID == nil {
+		return nil
+	}
+	entry := &model.CreditEntry{
+		StoreID:       sale.StoreID,
+		UserID:        *sale.BuyerID,
+		Kind:          model.CreditEntryRefund,
+		Amount:        sale.Total(),
+		TransactionID: &transactionID,
+		ActorID:       &actorID,
+		Note:          reason,
+	}
+	return s.creditRepo.WithTx(tx).CreateEntry(entry)
+}
+
+// lockSaleForReversal locks a sale and its item for a void or refund and checks that the
+// user works at the store that made the sale, with the permission, and that it has not been
+// reversed already.
+func (s *TransactionService) lockSaleForReversal(tx *sql.Tx, storeUserID, transactionID int64, permission model.StorePermission) (*model.Transaction, *model.ConsignmentItem, error) {
+	sale, err := s.repo.WithTx(tx).GetTransactionForUpdate(transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sale == nil {
+		return nil, nil, ErrTransactionNotFound
+	}
+	store, err := checkStoreMember(s.storeRepo, storeUserID, sale.StoreID, permission)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !store.IsActive() {
+		return nil, nil, ErrStoreNotActive
+	}
+	if sale.Type != model.TransactionTypeSale {
+		return nil, nil, ErrNotASale
+	}
+	if sale.IsReversed() {
+		return nil, nil, ErrAlreadyReversed
+	}
+	// A refund committed while we waited for the lock is not in the row we read
+	refunded, err := s.repo.WithTx(tx).IsRefunded(sale.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if refunded {
+		return nil, nil, ErrAlreadyReversed
+	}
+
+	item, err := s.consignmentRepo.WithTx(tx).GetConsignmentItemForUpdate(sale.ConsignmentItemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting consignment item: %w", err)
+	}
+	if item == nil {
+		return nil, nil, ErrConsignmentItemNotFound
+	}
+	return sale, item, nil
+}
+
+// putBackOnSale returns a sold or cleared item to APPROVED and rolls it up to the request. The
+// listing starts over, so the time it was off sale neither counts towards its agreement period nor
+// earns it automatic price drops.
+func (s *TransactionService) putBackOnSale(tx *sql.Tx, item *model.ConsignmentItem, actorID int64, reason string) error {
+	consignmentRepo := s.consignmentRepo.WithTx(tx)
+	if err := transitionItem(consignmentRepo, item.ID, item.Status, model.ItemStatusApproved, &actorID, reason); err != nil {
+		return err
+	}
+	consignment, err := consignmentRepo.GetConsignmentByID(item.ConsignmentID)
+	if err != nil {
+		return fmt.Errorf("error getting parent consignment: %w", err)
+	}
+	if err := startListing(consignmentRepo, item, consignment, time.Now()); err != nil {
+		return err
+	}
+	if _, err := consignmentRepo.RefreshConsignmentStatus(item.ConsignmentID); err != nil {
+		return fmt.Errorf("failed to refresh consignment status: %w", err)
+	}
+	return nil
+}
+
+// assignPaymentMethods checks a sale's lines and tenders and returns the lines with every
+// payment method filled in. With a single tender method, lines without one take it; with several,
+// every line must name one. Either way the tenders of each method must add up exactly to the lines
+// paid with it, so the commission on every line matches how it was actually paid.
+func assignPaymentMethods(lines []SaleLine, tenders []model.SaleTender) ([]SaleLine, error) {
+	if len(lines) == 0 {
+		return nil, ErrEmptySale
+	}
+	if len(tenders) == 0 {
+		return nil, ErrInvalidTender
+	}
+
+	tendered := make(map[model.PaymentMethod]model.Money)
+	for _, tender := range tenders {
+		if !tender.PaymentMethod.Valid() || tender.Amount <= 0 {
+			return nil, ErrInvalidTender
+		}
+		tendered[tender.PaymentMethod] += tender.Amount
+	}
+
+	assigned := make([]SaleLine, len(lines))
+	owed := make(map[model.PaymentMethod]model.Money)
+	for i, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, &SaleLineError{Index: i, Err: ErrInvalidQuantity}
+		}
+		if line.Price <= 0 {
+			return nil, &SaleLineError{Index: i, Err: ErrInvalidPrice}
+		}
+		if line.PaymentMethod == "" {
+			if len(tendered) > 1 {
+				return nil, &SaleLineError{Index: i, Err: ErrUnassignedLine}
+			}
+			line.PaymentMethod = tenders[0].PaymentMethod
+		}
+		if !line.PaymentMethod.Valid() {
+			return nil, &SaleLineError{Index: i, Err: ErrInvalidTender}
+		}
+		assigned[i] = line
+		owed[line.PaymentMethod] += line.Total()
+	}
+
+	if len(owed) != len(tendered) {
+		return nil, ErrTenderMismatch
+	}
+	for method, amount := range owed {
+		if tendered[method] != amount {
+			return nil, ErrTenderMismatch
+		}
+	}
+	return assigned, nil
+}
+
+// sellLine sells one line inside tx: it locks the item, checks that the store may sell it at that
+// price, splits off the units sold when the item is not sold in full, and records the transaction.
+// CREDIT lines record buyerID as the account charged; paying from it is left to the caller.
+func (s *TransactionService) sellLine(tx *sql.Tx, store *model.Store, storeUserID int64, line SaleLine, saleID *int64, buyerID int64) (*model.Transaction, error) {
+	consignmentRepo := s.consignmentRepo.WithTx(tx)
+
+	// 1. Lock the consignment item and load its parent consignment
+	item, err := consignmentRepo.GetConsignmentItemForUpdate(line.ItemID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting consignment item: %w", err)
 	}
@@ -45,68 +510,103 @@ func (s *TransactionService) CreateTransaction(storeUserID, itemID int64, price
 		return nil, ErrConsignmentItemNotFound
 	}
 
-	consignment, err := s.consignmentRepo.GetConsignmentByID(item.ConsignmentID)
+	consignment, err := consignmentRepo.GetConsignmentByID(item.ConsignmentID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting parent consignment: %w", err)
 	}
 	if consignment == nil {
 		return nil, ErrConsignmentNotFound
 	}
-
-	// 2. Verify store ownership
-	store, err := s.storeRepo.GetStoreByUserID(storeUserID)
-	if err != nil {
-		return nil, fmt.Errorf("error getting store: %w", err)
-	}
-	if store == nil || store.ID != consignment.StoreID {
+	if store.ID != consignment.StoreID {
 		return nil, ErrForbidden
 	}
 
-	// 3. Check if the item can be sold
-	if item.Status != model.ItemStatusApproved {
+	// 2. Check if the item can be sold
+	if !item.Status.CanTransitionTo(model.ItemStatusSold) {
+		if item.Status == model.ItemStatusSold || item.Status == model.ItemStatusCleared {
+			return nil, ErrItemAlreadySold
+		}
 		return nil, ErrItemNotApproved
 	}
 
-	// 4. Determine commission rate
-	var commissionRate float64
-	if paymentMethod == model.PaymentMethodCash {
-		commissionRate = store.CommissionCash
-	} else {
-		commissionRate = store.CommissionCredit
+	if line.Quantity > item.Quantity {
+		return nil, ErrInsufficientQuantity
 	}
 
-	// 5. Create transaction object
-	newTxModel := &model.Transaction{
-		ConsignmentItemID: itemID,
-		StoreID:           store.ID,
-		Price:             price,
-		PaymentMethod:     paymentMethod,
-		CommissionRate:    commissionRate,
+	// 3. Never sell below the player's floor; selling below the agreed listed price only warns
+	if item.MinPrice > 0 && line.Price < item.MinPrice {
+		return nil, ErrPriceBelowFloor
+	}
+	var warnings []string
+	if item.ListedPrice > 0 && line.Price < item.ListedPrice {
+		warnings = append(warnings, fmt.Sprintf("sale price %s is below the listed price %s", line.Price, item.ListedPrice))
 	}
 
-	// 6. Execute in a DB transaction
-	tx, err := s.db.Begin()
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin db transaction: %w", err)
+	// 4. Split off the units being sold when the item is not sold in full
+	soldItemID := item.ID
+	if line.Quantity < item.Quantity {
+		soldItem, err := splitItem(consignmentRepo, item, line.Quantity, &storeUserID)
+		if err != nil {
+			return nil, err
+		}
+		soldItemID = soldItem.ID
 	}
-	defer tx.Rollback()
 
-	// Create the transaction record
-	txID, err := s.repo.CreateTransactionInTx(tx, newTxModel)
+	// 5. Create the transaction record at the commission rate in force for the card, price and payment method
+	card, err := s.cardRepo.WithTx(tx).GetCardByID(item.CardID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting card: %w", err)
+	}
+	rate, ruleID, err := resolveCommission(s.storeRepo.WithTx(tx), store, card, line.Price, line.PaymentMethod, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	newTxModel := &model.Transaction{
+		ConsignmentItemID: soldItemID,
+		SaleID:            saleID,
+		StoreID:           store.ID,
+		Price:             line.Price,
+		Quantity:          line.Quantity,
+		PaymentMethod:     line.PaymentMethod,
+		CommissionRate:    rate,
+		CommissionRuleID:  ruleID,
+		ConditionGrade:    item.ConditionGrade,
+		ActorID:           &storeUserID,
+		Warnings:          warnings,
+	}
+	if line.PaymentMethod == model.PaymentMethodCredit {
+		newTxModel.BuyerID = &buyerID
+	}
+	txID, err := s.repo.WithTx(tx).CreateTransaction(newTxModel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction record: %w", err)
 	}
 	newTxModel.ID = txID
 
-	// Update the item status to SOLD
-	if err := s.consignmentRepo.UpdateConsignmentItemStatus(itemID, model.ItemStatusSold, ""); err != nil {
-		return nil, fmt.Errorf("failed to update item status: %w", err)
+	// 6. Update the sold units to SOLD and roll it up to the request
+	reason := fmt.Sprintf("sold in transaction %d", txID)
+	if err := transitionItem(consignmentRepo, soldItemID, item.Status, model.ItemStatusSold, &storeUserID, reason); err != nil {
+		return nil, err
 	}
-
-	// Commit the transaction
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit db transaction: %w", err)
+	if _, err := consignmentRepo.RefreshConsignmentStatus(consignment.ID); err != nil {
+		return nil, fmt.Errorf("failed to refresh consignment status: %w", err)
 	}
-
 	return newTxModel, nil
-}
\ No newline at end of file
+}
+
+// resolveCommission works out the commission rate for a card sold at the given unit price at time at:
+// the most specific rule of the store's commission schedule in force then, or the store's base rate
+// for the payment method when there is no schedule or none of its rules covers the sale. It also
+// returns the ID of the rule applied, nil for the base rate.
+func resolveCommission(storeRepo *repository.StoreRepository, store *model.Store, card *model.Card, price model.Money, method model.PaymentMethod, at time.Time) (model.Rate, *int64, error) {
+	schedule, err := storeRepo.GetCommissionScheduleInForce(store.ID, at)
+	if err != nil {
+		return 0, nil, err
+	}
+	if schedule != nil {
+		if rule := schedule.RuleFor(price, card); rule != nil {
+			return rule.RateFor(method), &rule.ID, nil
+		}
+	}
+	return store.CommissionFor(method), nil, nil
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"card_manage/internal/model"
+	"card_manage/internal/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransactionService(t *testing.T, quantity int) (*TransactionService, *testFixture, *model.ConsignmentItem) {
+	db := openTestDB(t)
+	f := seedFixture(t, db)
+	item := seedApprovedItem(t, db, f, quantity)
+
+	svc := NewTransactionService(
+		repository.NewTransactionRepository(db),
+		repository.NewConsignmentRepository(db),
+		repository.NewCardRepository(db),
+		repository.NewSettlementRepository(db),
+		repository.NewCreditRepository(db),
+		repository.NewStoreRepository(db),
+		NewUnitOfWork(db),
+	)
+	return svc, f, item
+}
+
+// topUpCredit gives the user store credit at the fixture's store.
+func topUpCredit(t *testing.T, svc *TransactionService, f *testFixture, userID int64, amount model.Money) {
+	t.Helper()
+	entry := &model.CreditEntry{StoreID: f.store.ID, UserID: userID, Kind: model.CreditEntryTopUp, Amount: amount}
+	if err := svc.creditRepo.CreateEntry(entry); err != nil {
+		t.Fatalf("failed to top up store credit: %v", err)
+	}
+}
+
+func TestTransactionService_CreateTransaction(t *testing.T) {
+	t.Run("second sale of the same item is rejected", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		tx, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		if assert.NotNil(t, tx) {
+			assert.Equal(t, model.ConditionLightlyPlayed, tx.Condition, "the sale should keep the item's condition")
+		}
+
+		_, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.Equal(t, ErrItemAlreadySold, err)
+	})
+
+	t.Run("concurrent sales of the same item only succeed once", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		const cashiers = 5
+		start := make(chan struct{})
+		errs := make([]error, cashiers)
+		var wg sync.WaitGroup
+		for i := 0; i < cashiers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				_, errs[i] = svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, ErrItemAlreadySold, err)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("sale below the player's floor is refused", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+		assert.NoError(t, svc.consignmentRepo.UpdateConsignmentItemPrices(item.ID, 15000, 12000))
+
+		_, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.Equal(t, ErrPriceBelowFloor, err)
+
+		tx, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, 13000, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		assert.Len(t, tx.Warnings, 1, "selling below the listed price should warn")
+	})
+
+	t.Run("partial sales leave the remaining units on sale", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 5)
+
+		tx, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 3, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		assert.NotEqual(t, item.ID, tx.ConsignmentItemID, "the sold units should be split into their own item")
+		assert.Equal(t, testPrice.Mul(3), tx.Total())
+
+		remaining, err := svc.consignmentRepo.GetConsignmentItemByID(item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, remaining.Quantity)
+		assert.Equal(t, model.ItemStatusApproved, remaining.Status)
+
+		sold, err := svc.consignmentRepo.GetConsignmentItemByID(tx.ConsignmentItemID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, sold.Quantity)
+		assert.Equal(t, model.ItemStatusSold, sold.Status)
+		assert.Equal(t, item.ID, *sold.SplitFromID)
+
+		_, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 3, testPrice, model.PaymentMethodCash, 0)
+		assert.Equal(t, ErrInsufficientQuantity, err)
+
+		_, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 2, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("credit sales spend the buyer's store credit", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 2)
+
+		_, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCredit, 0)
+		assert.Equal(t, ErrBuyerRequired, err)
+		_, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCredit, f.player.ID)
+		assert.Equal(t, ErrInsufficientCredit, err)
+
+		topUpCredit(t, svc, f, f.player.ID, testPrice.Mul(3))
+		tx, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 2, testPrice, model.PaymentMethodCredit, f.player.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, f.player.ID, *tx.BuyerID)
+
+		balance, err := svc.creditRepo.GetBalance(f.store.ID, f.player.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, testPrice, balance)
+	})
+
+	t.Run("commission schedule in force sets the rate", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 2)
+
+		tx, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Rate(1000), tx.CommissionRate, "no schedule: the store's base rate")
+		assert.Nil(t, tx.CommissionRuleID)
+
+		fifty := model.Money(5000)
+		current := &model.CommissionSchedule{StoreID: f.store.ID, EffectiveFrom: time.Now().Add(-time.Hour), Rules: []model.CommissionRule{
+			{MaxPrice: &fifty, CommissionCash: 2000, CommissionCredit: 1500},
+			{MinPrice: fifty, CommissionCash: 1500, CommissionCredit: 1000},
+		}}
+		upcoming := &model.CommissionSchedule{StoreID: f.store.ID, EffectiveFrom: time.Now().Add(24 * time.Hour), Rules: []model.CommissionRule{
+			{CommissionCash: 100, CommissionCredit: 100},
+		}}
+		for _, schedule := range []*model.CommissionSchedule{current, upcoming} {
+			if err := svc.storeRepo.CreateCommissionSchedule(schedule); err != nil {
+				t.Fatalf("failed to seed commission schedule: %v", err)
+			}
+		}
+
+		tx, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Rate(1500), tx.CommissionRate, "the band rule of the schedule in force")
+		if assert.NotNil(t, tx.CommissionRuleID) {
+			assert.Equal(t, current.Rules[1].ID, *tx.CommissionRuleID)
+		}
+	})
+
+	t.Run("cards without optional details can be sold", func(t *testing.T) {
+		db := openTestDB(t)
+		f := seedFixture(t, db)
+		item := seedApprovedItem(t, db, f, 1)
+		// Cards from before images were kept have NULL details
+		if _, err := db.Exec(`UPDATE cards SET series = NULL, rarity = NULL, card_number = NULL, image_url = NULL WHERE id = $1`, f.card.ID); err != nil {
+			t.Fatalf("failed to clear card details: %v", err)
+		}
+		svc := NewTransactionService(
+			repository.NewTransactionRepository(db),
+			repository.NewConsignmentRepository(db),
+			repository.NewCardRepository(db),
+			repository.NewSettlementRepository(db),
+			repository.NewCreditRepository(db),
+			repository.NewStoreRepository(db),
+			NewUnitOfWork(db),
+		)
+
+		_, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		cards, err := svc.cardRepo.ListCardsByStore(f.store.ID)
+		assert.NoError(t, err)
+		assert.Len(t, cards, 1)
+	})
+
+	t.Run("other store is forbidden", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		_, err := svc.CreateTransaction(f.player.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
+func TestAssignPaymentMethods(t *testing.T) {
+	cash := func(amount model.Money) model.SaleTender {
+		return model.SaleTender{PaymentMethod: model.PaymentMethodCash, Amount: amount}
+	}
+	credit := func(amount model.Money) model.SaleTender {
+		return model.SaleTender{PaymentMethod: model.PaymentMethodCredit, Amount: amount}
+	}
+
+	t.Run("single tender method is assigned to every line", func(t *testing.T) {
+		lines := []SaleLine{{ItemID: 1, Quantity: 2, Price: 1000}, {ItemID: 2, Quantity: 1, Price: 500}}
+		assigned, err := assignPaymentMethods(lines, []model.SaleTender{cash(2000), cash(500)})
+		assert.NoError(t, err)
+		for _, line := range assigned {
+			assert.Equal(t, model.PaymentMethodCash, line.PaymentMethod)
+		}
+		assert.Empty(t, lines[0].PaymentMethod, "the caller's lines should not be modified")
+	})
+
+	t.Run("split tenders must match the lines of each method", func(t *testing.T) {
+		lines := []SaleLine{
+			{ItemID: 1, Quantity: 1, Price: 1000, PaymentMethod: model.PaymentMethodCash},
+			{ItemID: 2, Quantity: 1, Price: 500, PaymentMethod: model.PaymentMethodCredit},
+		}
+		_, err := assignPaymentMethods(lines, []model.SaleTender{cash(1000), credit(500)})
+		assert.NoError(t, err)
+
+		_, err = assignPaymentMethods(lines, []model.SaleTender{cash(500), credit(1000)})
+		assert.Equal(t, ErrTenderMismatch, err)
+
+		_, err = assignPaymentMethods(lines, []model.SaleTender{cash(1500)})
+		assert.Equal(t, ErrTenderMismatch, err)
+	})
+
+	t.Run("split tenders need a method on every line", func(t *testing.T) {
+		lines := []SaleLine{
+			{ItemID: 1, Quantity: 1, Price: 1000, PaymentMethod: model.PaymentMethodCash},
+			{ItemID: 2, Quantity: 1, Price: 500},
+		}
+		_, err := assignPaymentMethods(lines, []model.SaleTender{cash(1000), credit(500)})
+		var lineErr *SaleLineError
+		if assert.ErrorAs(t, err, &lineErr) {
+			assert.Equal(t, 1, lineErr.Index)
+			assert.ErrorIs(t, err, ErrUnassignedLine)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		line := SaleLine{ItemID: 1, Quantity: 1, Price: 1000}
+		_, err := assignPaymentMethods(nil, []model.SaleTender{cash(1000)})
+		assert.Equal(t, ErrEmptySale, err)
+		_, err = assignPaymentMethods([]SaleLine{line}, nil)
+		assert.Equal(t, ErrInvalidTender, err)
+		_, err = assignPaymentMethods([]SaleLine{line}, []model.SaleTender{{PaymentMethod: "CHEQUE", Amount: 1000}})
+		assert.Equal(t, ErrInvalidTender, err)
+		_, err = assignPaymentMethods([]SaleLine{{ItemID: 1, Price: 1000}}, []model.SaleTender{cash(1000)})
+		assert.ErrorIs(t, err, ErrInvalidQuantity)
+	})
+}
+
+func TestTransactionService_Checkout(t *testing.T) {
+	t.Run("split tenders sell every line under one receipt", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 3)
+
+		lines := []SaleLine{
+			{ItemID: item.ID, Quantity: 2, Price: testPrice, PaymentMethod: model.PaymentMethodCash},
+			{ItemID: item.ID, Quantity: 1, Price: testPrice, PaymentMethod: model.PaymentMethodCredit},
+		}
+		tenders := []model.SaleTender{
+			{PaymentMethod: model.PaymentMethodCash, Amount: testPrice.Mul(2)},
+			{PaymentMethod: model.PaymentMethodCredit, Amount: testPrice},
+		}
+		_, err := svc.Checkout(f.storeUser.ID, 0, lines, tenders)
+		assert.Equal(t, ErrBuyerRequired, err)
+
+		topUpCredit(t, svc, f, f.player.ID, testPrice)
+		sale, err := svc.Checkout(f.storeUser.ID, f.player.ID, lines, tenders)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, sale) {
+			return
+		}
+		assert.NotEmpty(t, sale.ReceiptNumber)
+		assert.Equal(t, testPrice.Mul(3), sale.Total)
+		if assert.Len(t, sale.Lines, 2) {
+			assert.Equal(t, f.store.CommissionCash, sale.Lines[0].CommissionRate)
+			assert.Equal(t, f.store.CommissionCredit, sale.Lines[1].CommissionRate)
+		}
+
+		stored, err := svc.GetSale(f.storeUser.ID, sale.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, sale.ReceiptNumber, stored.ReceiptNumber)
+		assert.Len(t, stored.Lines, 2)
+		assert.Len(t, stored.Tenders, 2)
+
+		_, err = svc.GetSale(f.player.ID, sale.ID)
+		assert.Equal(t, ErrForbidden, err)
+	})
+
+	t.Run("a failing line rolls back the whole sale", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 2)
+
+		lines := []SaleLine{
+			{ItemID: item.ID, Quantity: 1, Price: testPrice},
+			{ItemID: item.ID, Quantity: 2, Price: testPrice},
+		}
+		tenders := []model.SaleTender{{PaymentMethod: model.PaymentMethodCash, Amount: testPrice.Mul(3)}}
+		_, err := svc.Checkout(f.storeUser.ID, 0, lines, tenders)
+		var lineErr *SaleLineError
+		if assert.ErrorAs(t, err, &lineErr) {
+			assert.Equal(t, 1, lineErr.Index)
+			assert.ErrorIs(t, err, ErrInsufficientQuantity)
+		}
+
+		remaining, err := svc.consignmentRepo.GetConsignmentItemByID(item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, remaining.Quantity, "the first line should not have been sold")
+		assert.Equal(t, model.ItemStatusApproved, remaining.Status)
+	})
+}
+
+func TestTransactionService_VoidAndRefund(t *testing.T) {
+	t.Run("void puts the item back on sale", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+
+		_, err = svc.VoidTransaction(f.storeUser.ID, sale.ID, "")
+		assert.Equal(t, ErrReasonRequired, err)
+		_, err = svc.VoidTransaction(f.player.ID, sale.ID, "wrong card")
+		assert.Equal(t, ErrForbidden, err)
+
+		voided, err := svc.VoidTransaction(f.storeUser.ID, sale.ID, "wrong card")
+		assert.NoError(t, err)
+		assert.NotNil(t, voided.VoidedAt)
+		assert.Equal(t, f.storeUser.ID, *voided.VoidedBy)
+
+		relisted, err := svc.consignmentRepo.GetConsignmentItemByID(item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusApproved, relisted.Status)
+
+		_, err = svc.VoidTransaction(f.storeUser.ID, sale.ID, "wrong card")
+		assert.Equal(t, ErrAlreadyReversed, err)
+
+		_, err = svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err, "a voided item can be sold again")
+	})
+
+	t.Run("refund writes a reversing transaction", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 2)
+
+		topUpCredit(t, svc, f, f.player.ID, testPrice.Mul(2))
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 2, testPrice, model.PaymentMethodCredit, f.player.ID)
+		assert.NoError(t, err)
+
+		refund, err := svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the cards", false)
+		assert.NoError(t, err)
+		assert.Equal(t, model.TransactionTypeRefund, refund.Type)
+		assert.Equal(t, sale.ID, *refund.ReversesID)
+		assert.Equal(t, -2, refund.Quantity)
+		assert.Equal(t, -sale.Total(), refund.Total())
+
+		relisted, err := svc.consignmentRepo.GetConsignmentItemByID(item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusApproved, relisted.Status)
+
+		balance, err := svc.creditRepo.GetBalance(f.store.ID, f.player.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, testPrice.Mul(2), balance, "the store credit should be given back")
+
+		_, err = svc.RefundTransaction(f.storeUser.ID, sale.ID, "again", false)
+		assert.Equal(t, ErrAlreadyReversed, err)
+		_, err = svc.RefundTransaction(f.storeUser.ID, refund.ID, "refund of a refund", false)
+		assert.Equal(t, ErrNotASale, err)
+	})
+
+	t.Run("refund restarts the listing window", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		// Listed long ago, with an agreement about to end and two price drops already applied
+		listedAt, expiresAt := time.Now().AddDate(0, 0, -20), time.Now().Add(time.Hour)
+		assert.NoError(t, svc.consignmentRepo.StartItemListing(item.ID, listedAt, &expiresAt))
+		dropped, err := svc.consignmentRepo.ApplyPriceDrop(item, 0, 2)
+		if err != nil || !dropped {
+			t.Fatalf("failed to seed price drops: %v", err)
+		}
+
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		refundedAt := time.Now()
+		_, err = svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the card", false)
+		assert.NoError(t, err)
+
+		relisted, err := svc.consignmentRepo.GetConsignmentItemByID(item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ItemStatusApproved, relisted.Status)
+		if assert.NotNil(t, relisted.ListedAt) {
+			assert.False(t, relisted.ListedAt.Before(refundedAt.Add(-time.Second)), "listed again at the refund")
+		}
+		assert.Nil(t, relisted.ExpiresAt, "the fixture store has no agreement period")
+		assert.Equal(t, 0, relisted.PriceDrops)
+	})
+
+	t.Run("concurrent refunds of the same sale only succeed once", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+
+		const cashiers = 5
+		start := make(chan struct{})
+		errs := make([]error, cashiers)
+		var wg sync.WaitGroup
+		for i := 0; i < cashiers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				_, errs[i] = svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the card", false)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, ErrAlreadyReversed, err)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("refund after settlement needs an adjustment", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+		settlementService := NewSettlementService(svc.settlementRepo, svc.consignmentRepo, svc.creditRepo, svc.storeRepo, svc.uow)
+
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		_, err = settlementService.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, nil)
+		assert.NoError(t, err)
+
+		_, err = svc.VoidTransaction(f.storeUser.ID, sale.ID, "too late")
+		assert.Equal(t, ErrVoidNotAllowed, err)
+		_, err = svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the card", false)
+		assert.Equal(t, ErrRefundNeedsAdjustment, err)
+
+		_, err = svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the card", true)
+		assert.NoError(t, err)
+
+		// The next settlement takes the refunded share back
+		resale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice.Mul(2), model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		settlement, err := settlementService.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, nil)
+		assert.NoError(t, err)
+		_, refundedShare := sale.Split()
+		_, resaleShare := resale.Split()
+		assert.Equal(t, refundedShare, settlement.AdjustmentsDeducted)
+		assert.Equal(t, resaleShare-refundedShare, settlement.Amount)
+	})
+
+	t.Run("refund of a sale settled at full commission takes nothing back", func(t *testing.T) {
+		svc, f, item := newTestTransactionService(t, 1)
+		settlementService := NewSettlementService(svc.settlementRepo, svc.consignmentRepo, svc.creditRepo, svc.storeRepo, svc.uow)
+
+		f.store.CommissionCash = 10000 // 100%
+		assert.NoError(t, svc.storeRepo.UpdateStore(f.store))
+
+		sale, err := svc.CreateTransaction(f.storeUser.ID, item.ID, 1, testPrice, model.PaymentMethodCash, 0)
+		assert.NoError(t, err)
+		settlement, err := settlementService.CreateSettlement(f.player.ID, f.store.ID, model.PaymentMethodCash, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Money(0), settlement.NetAmount)
+
+		_, err = svc.RefundTransaction(f.storeUser.ID, sale.ID, "buyer returned the card", true)
+		assert.NoError(t, err)
+
+		adjustments, err := svc.settlementRepo.GetOutstandingAdjustmentsForUpdate(f.player.ID, f.store.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, adjustments)
+	})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// UnitOfWork groups several repository calls into a single database transaction.
+// Services obtain transaction-bound repositories inside Do via each repository's WithTx.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a new UnitOfWork on top of the given connection pool.
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do begins a transaction, runs fn with it and commits if fn succeeds.
+// If fn returns an error the transaction is rolled back and the error is returned unchanged,
+// so callers can keep comparing against the service's sentinel errors.
+func (u *UnitOfWork) Do(fn func(tx *sql.Tx) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin db transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op once the transaction has been committed
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit db transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidImage = errors.New("image must be a JPEG, PNG or WebP file")
+
+// uploadDir is where uploaded images are stored; it is served under /uploads.
+const uploadDir = "uploads"
+
+// imageTypes maps the accepted image extensions to the content type their files must sniff as.
+// Anything else, e.g. HTML or SVG, could run script when opened from /uploads.
+var imageTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
+
+// saveUpload writes an uploaded image under a unique name in uploadDir and returns its URL path.
+// It returns ErrInvalidImage unless both the extension and the content are an accepted image type.
+func saveUpload(content io.Reader, extension string) (string, error) {
+	content, extension, err := checkImage(content, extension)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the uploads directory if it doesn't exist
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	// Generate a unique filename
+	fileName := uuid.New().String() + extension
+	filePath := filepath.Join(uploadDir, fileName)
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create image file: %w", err)
+	}
+
+	// Don't leave a partial file behind; a short write may only show up on Close
+	_, err = io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filePath)
+		return "", fmt.Errorf("failed to save image content: %w", err)
+	}
+	return "/" + filePath, nil // Store a URL-like path
+}
+
+// removeUpload deletes a file saved by saveUpload, given its URL path, when whatever was meant to
+// refer to it could not be saved.
+func removeUpload(url string) {
+	os.Remove(strings.TrimPrefix(url, "/"))
+}
+
+// checkImage sniffs the start of an upload and checks it matches its extension. It returns the
+// whole content again and the extension in lower case.
+func checkImage(content io.Reader, extension string) (io.Reader, string, error) {
+	extension = strings.ToLower(extension)
+	contentType, ok := imageTypes[extension]
+	if !ok {
+		return nil, "", ErrInvalidImage
+	}
+
+	head := make([]byte, 512) // All http.DetectContentType looks at
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read image content: %w", err)
+	}
+	head = head[:n]
+	if http.DetectContentType(head) != contentType {
+		return nil, "", ErrInvalidImage
+	}
+	return io.MultiReader(bytes.NewReader(head), content), extension, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckImage(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 600)
+	jpeg := "\xff\xd8\xff\xe0" + strings.Repeat("\x00", 20)
+	webp := "RIFF\x00\x00\x00\x00WEBPVP8 " + strings.Repeat("\x00", 20)
+
+	t.Run("accepted images keep their content", func(t *testing.T) {
+		for extension, content := range map[string]string{".png": png, ".JPG": jpeg, ".jpeg": jpeg, ".webp": webp} {
+			reader, normalized, err := checkImage(strings.NewReader(content), extension)
+			if assert.NoError(t, err, extension) {
+				assert.Equal(t, strings.ToLower(extension), normalized)
+				saved, _ := io.ReadAll(reader)
+				assert.Equal(t, content, string(saved), extension)
+			}
+		}
+	})
+
+	t.Run("other extensions are refused", func(t *testing.T) {
+		for _, extension := range []string{".html", ".svg", ".gif", ""} {
+			_, _, err := checkImage(strings.NewReader(png), extension)
+			assert.Equal(t, ErrInvalidImage, err, extension)
+		}
+	})
+
+	t.Run("content must match the extension", func(t *testing.T) {
+		_, _, err := checkImage(strings.NewReader("<html><script>alert(1)</script></html>"), ".png")
+		assert.Equal(t, ErrInvalidImage, err)
+		_, _, err = checkImage(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), ".jpg")
+		assert.Equal(t, ErrInvalidImage, err)
+		_, _, err = checkImage(strings.NewReader(jpeg), ".png")
+		assert.Equal(t, ErrInvalidImage, err)
+		_, _, err = checkImage(strings.NewReader(""), ".png")
+		assert.Equal(t, ErrInvalidImage, err)
+	})
+}
+
+// failingReader returns its content and then an error, like a dropped upload.
+type failingReader struct{ content io.Reader }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.content.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestSaveUpload(t *testing.T) {
+	t.Chdir(t.TempDir())
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 600)
+
+	url, err := saveUpload(strings.NewReader(png), ".png")
+	if err != nil {
+		t.Fatalf("failed to save upload: %v", err)
+	}
+	saved, err := os.ReadFile(strings.TrimPrefix(url, "/"))
+	assert.NoError(t, err)
+	assert.Equal(t, png, string(saved))
+
+	removeUpload(url)
+	_, err = os.Stat(strings.TrimPrefix(url, "/"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = saveUpload(&failingReader{strings.NewReader(png)}, ".png")
+	assert.Error(t, err)
+	files, _ := filepath.Glob(filepath.Join(uploadDir, "*"))
+	assert.Empty(t, files, "a failed upload leaves no partial file")
+}
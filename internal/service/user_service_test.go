@@ -186,4 +186,4 @@ func TestUserService_GetUserByID(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, ErrUserNotFound, err)
 	})
-}
\ No newline at end of file
+}